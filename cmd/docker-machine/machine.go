@@ -148,6 +148,24 @@ func main() {
 			Usage:  "Token to use for requests to the Github API",
 			Value:  "",
 		},
+		cli.StringFlag{
+			EnvVar: "MACHINE_B2D_RELEASE_SOURCE",
+			Name:   "b2d-release-source",
+			Usage:  "Index of the Boot2Docker releases to use instead of the Github API: URL of the index, base URL of a mirror, or local index file or directory",
+			Value:  "",
+		},
+		cli.StringSliceFlag{
+			EnvVar: "MACHINE_DRIVER_CONCURRENCY",
+			Name:   "driver-concurrency",
+			Usage:  "Concurrency policy overriding the one of a driver, e.g. amazonec2:parallel=5:rate=2:retries=3:backoff=2s, parallel=0 lifts the limit",
+			Value:  &cli.StringSlice{},
+		},
+		cli.StringFlag{
+			EnvVar: "MACHINE_SECRETS_PROVIDER",
+			Name:   "secrets-provider",
+			Usage:  "Provider sealing the secrets of the drivers: passphrase (from MACHINE_SECRETS_PASSPHRASE), keyring or file",
+			Value:  "",
+		},
 		cli.BoolFlag{
 			EnvVar: "MACHINE_NATIVE_SSH",
 			Name:   "native-ssh",
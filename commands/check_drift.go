@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/provision"
+)
+
+var (
+	errDriftDetected = errors.New("Configuration drift detected, use --fix to re-apply the machine configuration")
+)
+
+func cmdCheckDrift(c CommandLine, api libmachine.API) error {
+	hosts, err := loadTargetHosts(c, api)
+	if err != nil {
+		return err
+	}
+
+	drifted := []*host.Host{}
+	for _, h := range hosts {
+		drifts, err := h.CheckDrift()
+		if err != nil {
+			return fmt.Errorf("Error checking %s for drift: %s", h.Name, err)
+		}
+
+		printDrifts(os.Stdout, h.Name, drifts)
+
+		if len(drifts) > 0 {
+			drifted = append(drifted, h)
+		}
+	}
+
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	if !c.Bool("fix") {
+		return errDriftDetected
+	}
+
+	for _, h := range drifted {
+		log.Infof("Re-applying the configuration of %s...", h.Name)
+		if err := h.FixDrift(); err != nil {
+			return fmt.Errorf("Error fixing drift on %s: %s", h.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func printDrifts(w io.Writer, name string, drifts []provision.Drift) {
+	if len(drifts) == 0 {
+		fmt.Fprintf(w, "%s: no drift\n", name)
+		return
+	}
+
+	fmt.Fprintf(w, "%s: %d difference(s)\n", name, len(drifts))
+	for _, drift := range drifts {
+		if drift.Diff != "" {
+			fmt.Fprintf(w, "  %s:\n", drift.Setting)
+			for _, line := range strings.Split(strings.TrimRight(drift.Diff, "\n"), "\n") {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+			continue
+		}
+
+		fmt.Fprintf(w, "  %s: desired %q, actual %q\n", drift.Setting, drift.Desired, drift.Actual)
+	}
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintDriftsNone(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printDrifts(out, "foo", []provision.Drift{})
+
+	assert.Equal(t, "foo: no drift\n", out.String())
+}
+
+func TestPrintDrifts(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printDrifts(out, "foo", []provision.Drift{
+		{Setting: "storage driver", Desired: "overlay2", Actual: "aufs"},
+		{Setting: "/etc/default/docker", Diff: "-a\n+b\n"},
+	})
+
+	assert.Equal(t, `foo: 2 difference(s)
+  storage driver: desired "overlay2", actual "aufs"
+  /etc/default/docker:
+    -a
+    +b
+`, out.String())
+}
@@ -10,11 +10,14 @@ import (
 	"github.com/docker/machine/commands/mcndirs"
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/crashreport"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/drivers/rpc"
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnerror"
 	"github.com/docker/machine/libmachine/mcnutils"
 	"github.com/docker/machine/libmachine/persist"
+	"github.com/docker/machine/libmachine/secrets"
 	"github.com/docker/machine/libmachine/ssh"
 )
 
@@ -95,6 +98,17 @@ func targetHost(c CommandLine, api libmachine.API) (string, error) {
 }
 
 func runAction(actionName string, c CommandLine, api libmachine.API) error {
+	hosts, err := loadTargetHosts(c, api)
+	if err != nil {
+		return err
+	}
+
+	return runActionOnHosts(actionName, hosts, api)
+}
+
+// loadTargetHosts loads the hosts named on the command line, or the default
+// host if none are given.
+func loadTargetHosts(c CommandLine, api libmachine.API) ([]*host.Host, error) {
 	var (
 		hostsToLoad []string
 	)
@@ -105,7 +119,7 @@ func runAction(actionName string, c CommandLine, api libmachine.API) error {
 	if len(c.Args()) == 0 {
 		target, err := targetHost(c, api)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		hostsToLoad = []string{target}
@@ -120,13 +134,19 @@ func runAction(actionName string, c CommandLine, api libmachine.API) error {
 		for _, err := range hostsInError {
 			errs = append(errs, err)
 		}
-		return consolidateErrs(errs)
+		return nil, consolidateErrs(errs)
 	}
 
 	if len(hosts) == 0 {
-		return ErrHostLoad
+		return nil, ErrHostLoad
 	}
 
+	return hosts, nil
+}
+
+// runActionOnHosts runs the named action across the given hosts and saves
+// them back to the store.
+func runActionOnHosts(actionName string, hosts []*host.Host, api libmachine.API) error {
 	if errs := runActionForeachMachine(actionName, hosts); len(errs) > 0 {
 		return consolidateErrs(errs)
 	}
@@ -151,6 +171,22 @@ func runCommand(command func(commandLine CommandLine, api libmachine.API) error)
 		api.GithubAPIToken = context.GlobalString("github-api-token")
 		api.Filestore.Path = context.GlobalString("storage-path")
 
+		overrides, err := drivers.ParseConcurrencyOverrides(context.GlobalStringSlice("driver-concurrency"))
+		if err != nil {
+			log.Error(err)
+			osExit(1)
+			return
+		}
+		rpcdriver.SetConcurrencyPolicyOverrides(overrides)
+
+		secretsProvider, err := secrets.NewProvider(context.GlobalString("secrets-provider"), api.Filestore.Path)
+		if err != nil {
+			log.Error(err)
+			osExit(1)
+			return
+		}
+		api.Filestore.Secrets = secretsProvider
+
 		// TODO (nathanleclaire): These should ultimately be accessed
 		// through the libmachine client by the rest of the code and
 		// not through their respective modules.  For now, however,
@@ -158,6 +194,13 @@ func runCommand(command func(commandLine CommandLine, api libmachine.API) error)
 		// set to preserve backwards compatibility.
 		mcndirs.BaseDir = api.Filestore.Path
 		mcnutils.GithubAPIToken = api.GithubAPIToken
+
+		// the drivers run in their own processes, where the release
+		// source is read from the environment
+		if source := context.GlobalString("b2d-release-source"); source != "" {
+			mcnutils.B2dReleaseSource = source
+			os.Setenv(mcnutils.ReleaseSourceEnvVar, source)
+		}
 		ssh.SetDefaultClient(api.SSHClientType)
 
 		if err := command(&contextCommandLine{context}, api); err != nil {
@@ -205,6 +248,18 @@ var Commands = []cli.Command{
 			},
 		},
 	},
+	{
+		Name:        "check-drift",
+		Usage:       "Compare the live configuration of machines with their persisted configuration",
+		Description: "Argument(s) are one or more machine names.",
+		Action:      runCommand(cmdCheckDrift),
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "fix",
+				Usage: "Re-apply the persisted configuration to machines which drifted",
+			},
+		},
+	},
 	{
 		Name:        "config",
 		Usage:       "Print the connection config for machine",
@@ -225,6 +280,18 @@ var Commands = []cli.Command{
 		Action:          runCommand(cmdCreateOuter),
 		SkipFlagParsing: true,
 	},
+	{
+		Name:        "demote",
+		Usage:       "Demote swarm mode managers to workers",
+		Description: "Argument(s) are one or more machine names.",
+		Action:      runCommand(cmdDemote),
+	},
+	{
+		Name:        "drivers",
+		Usage:       "List the core and plugin drivers with their status",
+		Description: "Argument(s) are optional driver names, to show their details and create flags.",
+		Action:      runCommand(cmdDrivers),
+	},
 	{
 		Name:        "env",
 		Usage:       "Display the commands to set up the environment for the Docker client",
@@ -249,6 +316,52 @@ var Commands = []cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "images",
+		Usage: "Manage the cache of Boot2Docker ISOs",
+		Subcommands: []cli.Command{
+			{
+				Name:   "ls",
+				Usage:  "List the cached ISOs",
+				Action: runCommand(cmdImagesLs),
+			},
+			{
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "sha256",
+						Usage: "SHA-256 digest the ISO must have, rather than the one published with its release",
+					},
+				},
+				Name:        "pull",
+				Usage:       "Download an ISO into the cache",
+				Description: "Argument is a version, e.g. v18.09.1, or the URL of an ISO. The latest release is pulled by default.",
+				Action:      runCommand(cmdImagesPull),
+			},
+			{
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "sha256",
+						Usage: "SHA-256 digest the ISO must have",
+					},
+				},
+				Name:        "import",
+				Usage:       "Copy a local ISO into the cache",
+				Description: "Argument is the path of the ISO.",
+				Action:      runCommand(cmdImagesImport),
+			},
+			{
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "all, a",
+						Usage: "Remove all the ISOs, including the most recently pulled one",
+					},
+				},
+				Name:   "prune",
+				Usage:  "Remove the cached ISOs but the most recently pulled one",
+				Action: runCommand(cmdImagesPrune),
+			},
+		},
+	},
 	{
 		Name:        "inspect",
 		Usage:       "Inspect information about a machine",
@@ -295,14 +408,38 @@ var Commands = []cli.Command{
 			},
 			cli.StringFlag{
 				Name:  "format, f",
-				Usage: "Pretty-print machines using a Go template",
+				Usage: "Pretty-print machines using a Go template, e.g. with {{.SwarmMode}} for the swarm mode clusters",
 			},
 		},
 	},
+	{
+		Name:        "pause",
+		Usage:       "Pause a machine, freezing it in memory",
+		Description: "Argument(s) are one or more machine names.",
+		Action:      runCommand(cmdPause),
+	},
+	{
+		Name:        "promote",
+		Usage:       "Promote swarm mode workers to managers",
+		Description: "Argument(s) are one or more machine names.",
+		Action:      runCommand(cmdPromote),
+	},
 	{
 		Name:   "provision",
 		Usage:  "Re-provision existing machines",
 		Action: runCommand(cmdProvision),
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print what provisioning would change without changing anything",
+			},
+		},
+	},
+	{
+		Name:        "provisioners",
+		Usage:       "List the registered provisioners",
+		Description: "Argument is an optional machine name, to show which provisioners match it.",
+		Action:      runCommand(cmdProvisioners),
 	},
 	{
 		Name:        "regenerate-certs",
@@ -320,12 +457,42 @@ var Commands = []cli.Command{
 			},
 		},
 	},
+	{
+		Name:        "resize",
+		Usage:       "Change the CPUs, memory or disk size of a machine",
+		Description: "Argument is a machine name. The machine is restarted.",
+		Action:      runCommand(cmdResize),
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "cpus",
+				Usage: "Number of CPUs",
+			},
+			cli.IntFlag{
+				Name:  "memory",
+				Usage: "Size of the memory (in MB)",
+			},
+			cli.IntFlag{
+				Name:  "disk",
+				Usage: "Size of the disk (in MB), which can only grow",
+			},
+			cli.StringFlag{
+				Name:  "machine-type",
+				Usage: "Size of the provider, e.g. an Amazon EC2 instance type, instead of CPUs and memory",
+			},
+		},
+	},
 	{
 		Name:        "restart",
 		Usage:       "Restart a machine",
 		Description: "Argument(s) are one or more machine names.",
 		Action:      runCommand(cmdRestart),
 	},
+	{
+		Name:        "resume",
+		Usage:       "Resume a suspended machine",
+		Description: "Argument(s) are one or more machine names.",
+		Action:      runCommand(cmdResume),
+	},
 	{
 		Flags: []cli.Flag{
 			cli.BoolFlag{
@@ -342,6 +509,60 @@ var Commands = []cli.Command{
 		Description: "Argument(s) are one or more machine names.",
 		Action:      runCommand(cmdRm),
 	},
+	{
+		Name:  "share",
+		Usage: "Manage the directories of the host shared with a machine",
+		Subcommands: []cli.Command{
+			{
+				Name:        "add",
+				Usage:       "Share a directory of the host with a machine",
+				Description: "Arguments are [machine-name] [host-path:guest-path[:ro]].",
+				Action:      runCommand(cmdShareAdd),
+			},
+			{
+				Name:        "rm",
+				Usage:       "Stop sharing a directory with a machine",
+				Description: "Arguments are [machine-name] [guest-path].",
+				Action:      runCommand(cmdShareRm),
+			},
+			{
+				Name:        "ls",
+				Usage:       "List the directories shared with a machine",
+				Description: "Argument is a machine name.",
+				Action:      runCommand(cmdShareLs),
+			},
+		},
+	},
+	{
+		Name:  "snapshot",
+		Usage: "Manage the snapshots of a machine",
+		Subcommands: []cli.Command{
+			{
+				Name:        "create",
+				Usage:       "Take a snapshot of a machine",
+				Description: "Arguments are [machine-name] [snapshot-name]. The snapshot is named after the current time by default.",
+				Action:      runCommand(cmdSnapshotCreate),
+			},
+			{
+				Name:        "ls",
+				Usage:       "List the snapshots of a machine",
+				Description: "Argument is a machine name.",
+				Action:      runCommand(cmdSnapshotLs),
+			},
+			{
+				Name:        "restore",
+				Usage:       "Restore a machine to a snapshot",
+				Description: "Arguments are [machine-name] [snapshot-name]. The latest snapshot is restored by default.",
+				Action:      runCommand(cmdSnapshotRestore),
+			},
+			{
+				Name:        "rm",
+				Usage:       "Remove a snapshot of a machine",
+				Description: "Arguments are [machine-name] [snapshot-name].",
+				Action:      runCommand(cmdSnapshotRm),
+			},
+		},
+	},
 	{
 		Name:            "ssh",
 		Usage:           "Log into or run a command on a machine with SSH.",
@@ -399,11 +620,29 @@ var Commands = []cli.Command{
 		Description: "Argument(s) are one or more machine names.",
 		Action:      runCommand(cmdStop),
 	},
+	{
+		Name:        "suspend",
+		Usage:       "Suspend a machine, saving its memory to disk",
+		Description: "Argument(s) are one or more machine names.",
+		Action:      runCommand(cmdSuspend),
+	},
+	{
+		Name:        "unpause",
+		Usage:       "Unpause a paused machine",
+		Description: "Argument(s) are one or more machine names.",
+		Action:      runCommand(cmdUnpause),
+	},
 	{
 		Name:        "upgrade",
 		Usage:       "Upgrade a machine to the latest version of Docker",
 		Description: "Argument(s) are one or more machine names.",
 		Action:      runCommand(cmdUpgrade),
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "to",
+				Usage: "Upgrade (or downgrade) to the given Docker engine version and pin it, 'latest' unpins it",
+			},
+		},
 	},
 	{
 		Name:        "url",
@@ -445,6 +684,10 @@ func machineCommand(actionName string, host *host.Host, errorChan chan<- error)
 		"upgrade":          host.Upgrade,
 		"ip":               printIP(host),
 		"provision":        host.Provision,
+		"pause":            host.Pause,
+		"unpause":          host.Unpause,
+		"suspend":          host.Suspend,
+		"resume":           host.Resume,
 	}
 
 	log.Debugf("command=%s machine=%s", actionName, host.Name)
@@ -452,7 +695,10 @@ func machineCommand(actionName string, host *host.Host, errorChan chan<- error)
 	errorChan <- commands[actionName]()
 }
 
-// runActionForeachMachine will run the command across multiple machines
+// runActionForeachMachine will run the command across multiple machines at
+// once. The concurrency policies of their drivers only apply to the calls
+// made to the drivers, so that waiting for SSH or provisioning a machine
+// doesn't hold back the others.
 func runActionForeachMachine(actionName string, machines []*host.Host) []error {
 	var (
 		numConcurrentActions = 0
@@ -465,9 +711,6 @@ func runActionForeachMachine(actionName string, machines []*host.Host) []error {
 		go machineCommand(actionName, machine, errorChan)
 	}
 
-	// TODO: We should probably only do 5-10 of these
-	// at a time, since otherwise cloud providers might
-	// rate limit us.
 	for i := 0; i < numConcurrentActions; i++ {
 		if err := <-errorChan; err != nil {
 			errs = append(errs, err)
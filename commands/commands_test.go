@@ -3,13 +3,16 @@ package commands
 import (
 	"errors"
 	"flag"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/codegangsta/cli"
 	"github.com/docker/machine/commands/commandstest"
 	"github.com/docker/machine/drivers/fakedriver"
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/crashreport"
+	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/hosttest"
 	"github.com/docker/machine/libmachine/mcnerror"
@@ -92,6 +95,62 @@ func TestRunActionForeachMachine(t *testing.T) {
 	}
 }
 
+// serialDriver starts once all of its machines are starting, and declares a
+// policy of one call at a time, which only applies to calls made over RPC.
+type serialDriver struct {
+	*fakedriver.Driver
+	starting *sync.WaitGroup
+}
+
+func (d *serialDriver) ConcurrencyPolicy() drivers.ConcurrencyPolicy {
+	return drivers.ConcurrencyPolicy{MaxParallel: 1}
+}
+
+func (d *serialDriver) Start() error {
+	d.starting.Done()
+	d.starting.Wait()
+
+	return d.Driver.Start()
+}
+
+func TestRunActionForeachMachineDoesntHoldDriverSlots(t *testing.T) {
+	defer provision.SetDetector(&provision.StandardDetector{})
+	provision.SetDetector(&provision.FakeDetector{
+		Provisioner: provision.NewNetstatProvisioner(),
+	})
+
+	names := []string{"foo", "bar", "baz", "spam"}
+	starting := &sync.WaitGroup{}
+	starting.Add(len(names))
+
+	machines := []*host.Host{}
+	for _, name := range names {
+		machines = append(machines, &host.Host{
+			Name:       name,
+			DriverName: "serial",
+			Driver: &serialDriver{
+				Driver:   &fakedriver.Driver{MockState: state.Stopped},
+				starting: starting,
+			},
+		})
+	}
+
+	done := make(chan []error)
+	go func() { done <- runActionForeachMachine("start", machines) }()
+
+	select {
+	case errs := <-done:
+		assert.Empty(t, errs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the machines to start at once")
+	}
+	for _, machine := range machines {
+		machineState, _ := machine.Driver.GetState()
+
+		assert.Equal(t, state.Running, machineState)
+	}
+}
+
 func TestPrintIPEmptyGivenLocalEngine(t *testing.T) {
 	stdoutGetter := commandstest.NewStdoutGetter()
 	defer stdoutGetter.Stop()
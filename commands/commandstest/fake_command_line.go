@@ -1,6 +1,8 @@
 package commandstest
 
 import (
+	"time"
+
 	"github.com/codegangsta/cli"
 )
 
@@ -42,6 +44,27 @@ func (ff FakeFlagger) Bool(key string) bool {
 	return false
 }
 
+func (ff FakeFlagger) Duration(key string) time.Duration {
+	if value, ok := ff.Data[key]; ok {
+		return value.(time.Duration)
+	}
+	return 0
+}
+
+func (ff FakeFlagger) Float(key string) float64 {
+	if value, ok := ff.Data[key]; ok {
+		return value.(float64)
+	}
+	return 0
+}
+
+func (ff FakeFlagger) Map(key string) map[string]string {
+	if value, ok := ff.Data[key]; ok {
+		return value.(map[string]string)
+	}
+	return map[string]string{}
+}
+
 func (fcli *FakeCommandLine) IsSet(key string) bool {
 	_, ok := fcli.LocalFlags.Data[key]
 	return ok
@@ -26,11 +26,16 @@ import (
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnerror"
 	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/secrets"
 	"github.com/docker/machine/libmachine/swarm"
 )
 
 var (
-	errNoMachineName = errors.New("Error: No machine name specified")
+	errNoMachineName      = errors.New("Error: No machine name specified")
+	errRootlessDaemonJSON = errors.New("The rootless engine can't be configured with --engine-config-mode daemon-json")
+	errRootlessSwarmMode  = errors.New("The rootless engine can't join a swarm mode cluster")
 )
 
 var (
@@ -47,6 +52,35 @@ var (
 			Value:  drivers.DefaultEngineInstallURL,
 			EnvVar: "MACHINE_DOCKER_INSTALL_URL",
 		},
+		cli.StringFlag{
+			Name:   "engine-version",
+			Usage:  "Specify the Docker engine version to install (default: latest)",
+			EnvVar: "MACHINE_DOCKER_VERSION",
+		},
+		cli.StringFlag{
+			Name:   "engine-channel",
+			Usage:  fmt.Sprintf("Specify the release channel to install the engine from: [%s]", strings.Join(engine.Channels, ", ")),
+			EnvVar: "MACHINE_DOCKER_CHANNEL",
+		},
+		cli.StringFlag{
+			Name:  "engine-install-bundle",
+			Usage: "Install the engine from a local static binaries tarball or package bundle instead of downloading it",
+		},
+		cli.StringFlag{
+			Name:  "engine-install-bundle-sha256",
+			Usage: "Expected SHA-256 digest of the engine install bundle",
+		},
+		cli.StringFlag{
+			Name:   "engine-config-mode",
+			Usage:  fmt.Sprintf("Configure the engine with command line flags or a daemon.json: [%s, %s]", engine.ConfigModeFlags, engine.ConfigModeDaemonJSON),
+			Value:  engine.ConfigModeFlags,
+			EnvVar: "MACHINE_DOCKER_CONFIG_MODE",
+		},
+		cli.StringFlag{
+			Name:   "engine-mode",
+			Usage:  fmt.Sprintf("Run the engine as the SSH user, listening on port %d, with %q", engine.DefaultRootlessPort, engine.ModeRootless),
+			EnvVar: "MACHINE_DOCKER_MODE",
+		},
 		cli.StringSliceFlag{
 			Name:  "engine-opt",
 			Usage: "Specify arbitrary flags to include with the created engine in the form flag=value",
@@ -125,6 +159,30 @@ var (
 			Name:  "swarm-experimental",
 			Usage: "Enable Swarm experimental features",
 		},
+		cli.BoolFlag{
+			Name:  "swarm-mode-manager",
+			Usage: "Configure Machine as a swarm mode manager, joining the existing managers of the cluster if any",
+		},
+		cli.BoolFlag{
+			Name:  "swarm-mode-worker",
+			Usage: "Configure Machine as a swarm mode worker joining a manager of the cluster",
+		},
+		cli.StringFlag{
+			Name:  "swarm-mode-cluster",
+			Usage: "Name of the swarm mode cluster to initialize or join",
+		},
+		cli.StringFlag{
+			Name:  "provisioner",
+			Usage: "Use the named provisioner instead of detecting it from the OS of the machine, see the provisioners command",
+		},
+		cli.StringFlag{
+			Name:  "pre-provision-script",
+			Usage: "Local script to upload and run on the machine before the engine is provisioned",
+		},
+		cli.StringFlag{
+			Name:  "post-provision-script",
+			Usage: "Local script to upload and run on the machine after the engine is provisioned",
+		},
 		cli.StringSliceFlag{
 			Name:  "tls-san",
 			Usage: "Support extra SANs for TLS certs",
@@ -153,6 +211,48 @@ func cmdCreateInner(c CommandLine, api libmachine.API) error {
 		return fmt.Errorf("Error parsing swarm discovery: %s", err)
 	}
 
+	swarmMode, err := swarmModeFromFlags(c)
+	if err != nil {
+		return err
+	}
+
+	if !engine.IsValidChannel(c.String("engine-channel")) {
+		return fmt.Errorf("Invalid engine channel %q, expected one of: %s", c.String("engine-channel"), strings.Join(engine.Channels, ", "))
+	}
+
+	if !engine.IsValidVersion(c.String("engine-version")) {
+		return fmt.Errorf("Invalid engine version %q, expected a release like 18.09.1", c.String("engine-version"))
+	}
+
+	if !engine.IsValidConfigMode(c.String("engine-config-mode")) {
+		return fmt.Errorf("Invalid engine config mode %q, expected %s or %s", c.String("engine-config-mode"), engine.ConfigModeFlags, engine.ConfigModeDaemonJSON)
+	}
+
+	if err := validateEngineMode(c, swarmMode); err != nil {
+		return err
+	}
+
+	if name := c.String("provisioner"); name != "" && !provision.IsRegistered(name) {
+		return provision.ErrUnknownProvisioner{
+			Name: name,
+		}
+	}
+
+	installBundle, err := localFilePath(c.String("engine-install-bundle"))
+	if err != nil {
+		return fmt.Errorf("Error reading engine install bundle: %s", err)
+	}
+
+	preProvisionScript, err := localFilePath(c.String("pre-provision-script"))
+	if err != nil {
+		return fmt.Errorf("Error reading pre-provision script: %s", err)
+	}
+
+	postProvisionScript, err := localFilePath(c.String("post-provision-script"))
+	if err != nil {
+		return fmt.Errorf("Error reading post-provision script: %s", err)
+	}
+
 	// TODO: Fix hacky JSON solution
 	rawDriver, err := json.Marshal(&drivers.BaseDriver{
 		MachineName: name,
@@ -181,14 +281,20 @@ func cmdCreateInner(c CommandLine, api libmachine.API) error {
 			ServerCertSANs:   c.StringSlice("tls-san"),
 		},
 		EngineOptions: &engine.Options{
-			ArbitraryFlags:   c.StringSlice("engine-opt"),
-			Env:              c.StringSlice("engine-env"),
-			InsecureRegistry: c.StringSlice("engine-insecure-registry"),
-			Labels:           c.StringSlice("engine-label"),
-			RegistryMirror:   c.StringSlice("engine-registry-mirror"),
-			StorageDriver:    c.String("engine-storage-driver"),
-			TLSVerify:        true,
-			InstallURL:       c.String("engine-install-url"),
+			ArbitraryFlags:      c.StringSlice("engine-opt"),
+			Env:                 c.StringSlice("engine-env"),
+			InsecureRegistry:    c.StringSlice("engine-insecure-registry"),
+			Labels:              c.StringSlice("engine-label"),
+			RegistryMirror:      c.StringSlice("engine-registry-mirror"),
+			StorageDriver:       c.String("engine-storage-driver"),
+			TLSVerify:           true,
+			InstallURL:          c.String("engine-install-url"),
+			InstallVersion:      c.String("engine-version"),
+			InstallChannel:      c.String("engine-channel"),
+			InstallBundle:       installBundle,
+			InstallBundleSHA256: c.String("engine-install-bundle-sha256"),
+			ConfigMode:          c.String("engine-config-mode"),
+			Mode:                c.String("engine-mode"),
 		},
 		SwarmOptions: &swarm.Options{
 			IsSwarm:            c.Bool("swarm") || c.Bool("swarm-master"),
@@ -202,7 +308,18 @@ func cmdCreateInner(c CommandLine, api libmachine.API) error {
 			ArbitraryFlags:     c.StringSlice("swarm-opt"),
 			ArbitraryJoinFlags: c.StringSlice("swarm-join-opt"),
 			IsExperimental:     c.Bool("swarm-experimental"),
+			Mode:               swarmMode,
+			ModeCluster:        c.String("swarm-mode-cluster"),
 		},
+		PreProvisionScript:  preProvisionScript,
+		PostProvisionScript: postProvisionScript,
+		Provisioner:         c.String("provisioner"),
+	}
+
+	if h.HostOptions.EngineOptions.ConfigMode == engine.ConfigModeDaemonJSON {
+		if err := provision.ValidateDaemonConfig(*h.HostOptions.EngineOptions); err != nil {
+			return fmt.Errorf("Error validating engine options: %s", err)
+		}
 	}
 
 	exists, err := api.Exists(h.Name)
@@ -215,16 +332,28 @@ func cmdCreateInner(c CommandLine, api libmachine.API) error {
 		}
 	}
 
+	if err := setSwarmModeJoinOptions(api, h.HostOptions.SwarmOptions); err != nil {
+		return err
+	}
+
 	// driverOpts is the actual data we send over the wire to set the
 	// driver parameters (an interface fulfilling drivers.DriverOptions,
 	// concrete type rpcdriver.RpcFlags).
 	mcnFlags := h.Driver.GetCreateFlags()
 	driverOpts := getDriverOpts(c, mcnFlags)
 
+	if err := mcnflag.Validate(mcnFlags, driverOpts.Values); err != nil {
+		return err
+	}
+
 	if err := h.Driver.SetConfigFromFlags(driverOpts); err != nil {
 		return fmt.Errorf("Error setting machine configuration from flags provided: %s", err)
 	}
 
+	if h.HostOptions.SecretFields, err = secretFields(h.Driver, mcnFlags, driverOpts); err != nil {
+		return fmt.Errorf("Error finding the secrets in the machine configuration: %s", err)
+	}
+
 	if err := api.Create(h); err != nil {
 		// Wait for all the logs to reach the client
 		time.Sleep(2 * time.Second)
@@ -340,7 +469,29 @@ func cmdCreateOuter(c CommandLine, api libmachine.API) error {
 	return c.Application().Run(os.Args)
 }
 
-func getDriverOpts(c CommandLine, mcnflags []mcnflag.Flag) drivers.DriverOptions {
+// secretFields returns the fields of the driver configuration holding the
+// values of the secret flags.
+func secretFields(d drivers.Driver, mcnflags []mcnflag.Flag, driverOpts drivers.DriverOptions) ([]string, error) {
+	values := []string{}
+	for _, f := range mcnflags {
+		if mcnflag.IsSecret(f) {
+			values = append(values, driverOpts.String(f.String()))
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	rawDriver, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets.FindFields(rawDriver, values)
+}
+
+func getDriverOpts(c CommandLine, mcnflags []mcnflag.Flag) rpcdriver.RPCFlags {
 	// TODO: This function is pretty damn YOLO and would benefit from some
 	// sanity checking around types and assertions.
 	//
@@ -374,6 +525,24 @@ func getDriverOpts(c CommandLine, mcnflags []mcnflag.Flag) drivers.DriverOptions
 		}
 	}
 
+	// Boot2Docker based drivers get their engine from the ISO, so pin the
+	// ISO matching the requested engine version unless one was given, or
+	// the machine boots from a cloud image instead.
+	if engineVersion, _ := driverOpts.Values["engine-version"].(string); engineVersion != "" {
+		for _, f := range mcnflags {
+			name := f.String()
+			if !strings.HasSuffix(name, "boot2docker-url") {
+				continue
+			}
+
+			isoURL, _ := driverOpts.Values[name].(string)
+			cloudImage, _ := driverOpts.Values[strings.TrimSuffix(name, "boot2docker-url")+"cloud-image"].(string)
+			if isoURL == "" && cloudImage == "" {
+				driverOpts.Values[name] = mcnutils.Boot2DockerReleaseURL(engineVersion)
+			}
+		}
+	}
+
 	return driverOpts
 }
 
@@ -389,14 +558,14 @@ func convertMcnFlagsToCliFlags(mcnFlags []mcnflag.Flag) ([]cli.Flag, error) {
 			cliFlags = append(cliFlags, cli.BoolFlag{
 				Name:   f.Name,
 				EnvVar: f.EnvVar,
-				Usage:  f.Usage,
+				Usage:  mcnflag.Usage(f),
 			})
 		case *mcnflag.IntFlag:
 			f := f.(*mcnflag.IntFlag)
 			cliFlags = append(cliFlags, cli.IntFlag{
 				Name:   f.Name,
 				EnvVar: f.EnvVar,
-				Usage:  f.Usage,
+				Usage:  mcnflag.Usage(f),
 				Value:  f.Value,
 			})
 		case *mcnflag.StringFlag:
@@ -404,7 +573,7 @@ func convertMcnFlagsToCliFlags(mcnFlags []mcnflag.Flag) ([]cli.Flag, error) {
 			cliFlags = append(cliFlags, cli.StringFlag{
 				Name:   f.Name,
 				EnvVar: f.EnvVar,
-				Usage:  f.Usage,
+				Usage:  mcnflag.Usage(f),
 				Value:  f.Value,
 			})
 		case *mcnflag.StringSliceFlag:
@@ -412,11 +581,39 @@ func convertMcnFlagsToCliFlags(mcnFlags []mcnflag.Flag) ([]cli.Flag, error) {
 			cliFlags = append(cliFlags, cli.StringSliceFlag{
 				Name:   f.Name,
 				EnvVar: f.EnvVar,
-				Usage:  f.Usage,
+				Usage:  mcnflag.Usage(f),
 
 				//TODO: Is this used with defaults? Can we convert the literal []string to cli.StringSlice properly?
 				Value: &cli.StringSlice{},
 			})
+		case *mcnflag.EnumFlag:
+			cliFlags = append(cliFlags, cli.GenericFlag{
+				Name:   t.Name,
+				EnvVar: t.EnvVar,
+				Usage:  mcnflag.Usage(t),
+				Value:  newEnumValue(*t),
+			})
+		case *mcnflag.DurationFlag:
+			cliFlags = append(cliFlags, cli.DurationFlag{
+				Name:   t.Name,
+				EnvVar: t.EnvVar,
+				Usage:  mcnflag.Usage(t),
+				Value:  t.Value,
+			})
+		case *mcnflag.FloatFlag:
+			cliFlags = append(cliFlags, cli.Float64Flag{
+				Name:   t.Name,
+				EnvVar: t.EnvVar,
+				Usage:  mcnflag.Usage(t),
+				Value:  t.Value,
+			})
+		case *mcnflag.MapFlag:
+			cliFlags = append(cliFlags, cli.GenericFlag{
+				Name:   t.Name,
+				EnvVar: t.EnvVar,
+				Usage:  mcnflag.Usage(t),
+				Value:  newMapValue(*t),
+			})
 		default:
 			log.Warn("Flag is ", f)
 			return nil, fmt.Errorf("Flag is unrecognized flag type: %T", t)
@@ -452,6 +649,25 @@ func validateSwarmDiscovery(discovery string) error {
 	return fmt.Errorf("Swarm Discovery URL was in the wrong format: %s", discovery)
 }
 
+// localFilePath returns the absolute path of a local file given at create
+// time, since it is used again whenever the machine is re-provisioned.
+func localFilePath(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(absName); err != nil {
+		return "", err
+	}
+
+	return absName, nil
+}
+
 func tlsPath(c CommandLine, flag string, defaultName string) string {
 	path := c.GlobalString(flag)
 	if path != "" {
@@ -460,3 +676,27 @@ func tlsPath(c CommandLine, flag string, defaultName string) string {
 
 	return filepath.Join(mcndirs.GetMachineCertDir(), defaultName)
 }
+
+// validateEngineMode checks the engine mode against the options it doesn't
+// support: a rootless engine doesn't read the system daemon.json and can't
+// join a swarm mode cluster.
+func validateEngineMode(c CommandLine, swarmMode string) error {
+	mode := c.String("engine-mode")
+	if !engine.IsValidMode(mode) {
+		return fmt.Errorf("Invalid engine mode %q, expected %s", mode, engine.ModeRootless)
+	}
+
+	if mode != engine.ModeRootless {
+		return nil
+	}
+
+	if c.String("engine-config-mode") == engine.ConfigModeDaemonJSON {
+		return errRootlessDaemonJSON
+	}
+
+	if swarmMode != "" {
+		return errRootlessSwarmMode
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"flag"
 	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/drivers/fakedriver"
 	"github.com/docker/machine/libmachine/mcnflag"
 	"github.com/stretchr/testify/assert"
 )
@@ -117,3 +118,129 @@ func TestGetDriverOpts(t *testing.T) {
 		assert.Equal(t, tt.expected["stringslice_defaulted"], driverOpts.StringSlice("stringslice_defaulted"))
 	}
 }
+
+func TestGetDriverOptsPinsBoot2DockerVersion(t *testing.T) {
+	flags := []mcnflag.Flag{
+		mcnflag.StringFlag{
+			Name: "virtualbox-boot2docker-url",
+		},
+	}
+	commandLine := &commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{
+				"engine-version": fakeFlagGetter{value: "18.09.1"},
+			},
+		},
+	}
+
+	driverOpts := getDriverOpts(commandLine, flags)
+
+	assert.Equal(t, "https://github.com/boot2docker/boot2docker/releases/download/v18.09.1/boot2docker.iso", driverOpts.String("virtualbox-boot2docker-url"))
+}
+
+func TestGetDriverOptsKeepsExplicitBoot2DockerURL(t *testing.T) {
+	flags := []mcnflag.Flag{
+		mcnflag.StringFlag{
+			Name: "virtualbox-boot2docker-url",
+		},
+	}
+	commandLine := &commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{
+				"engine-version":             fakeFlagGetter{value: "18.09.1"},
+				"virtualbox-boot2docker-url": fakeFlagGetter{value: "http://example.com/custom.iso"},
+			},
+		},
+	}
+
+	driverOpts := getDriverOpts(commandLine, flags)
+
+	assert.Equal(t, "http://example.com/custom.iso", driverOpts.String("virtualbox-boot2docker-url"))
+}
+
+func TestGetDriverOptsSkipsBoot2DockerPinWithCloudImage(t *testing.T) {
+	flags := []mcnflag.Flag{
+		mcnflag.StringFlag{
+			Name: "virtualbox-boot2docker-url",
+		},
+		mcnflag.StringFlag{
+			Name: "virtualbox-cloud-image",
+		},
+	}
+	commandLine := &commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{
+				"engine-version":         fakeFlagGetter{value: "18.09.1"},
+				"virtualbox-cloud-image": fakeFlagGetter{value: "focal.img"},
+			},
+		},
+	}
+
+	driverOpts := getDriverOpts(commandLine, flags)
+
+	assert.Equal(t, "", driverOpts.String("virtualbox-boot2docker-url"))
+	assert.Equal(t, "focal.img", driverOpts.String("virtualbox-cloud-image"))
+}
+
+func TestSecretFields(t *testing.T) {
+	flags := []mcnflag.Flag{
+		mcnflag.StringFlag{
+			Name:   "fake-token",
+			Secret: true,
+		},
+		mcnflag.StringFlag{
+			Name: "fake-ip",
+		},
+	}
+	commandLine := &commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{
+				"fake-token": fakeFlagGetter{value: "token"},
+				"fake-ip":    fakeFlagGetter{value: "1.2.3.4"},
+			},
+		},
+	}
+	driver := &fakedriver.Driver{
+		MockName: "token",
+		MockIP:   "1.2.3.4",
+	}
+
+	fields, err := secretFields(driver, flags, getDriverOpts(commandLine, flags))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"MockName"}, fields)
+}
+
+func TestValidateEngineMode(t *testing.T) {
+	var tests = []struct {
+		data      map[string]interface{}
+		swarmMode string
+		expected  error
+	}{
+		{map[string]interface{}{}, "", nil},
+		{map[string]interface{}{"engine-mode": "rootless"}, "", nil},
+		{map[string]interface{}{"engine-mode": "rootless", "engine-config-mode": "daemon-json"}, "", errRootlessDaemonJSON},
+		{map[string]interface{}{"engine-mode": "rootless"}, "worker", errRootlessSwarmMode},
+		{map[string]interface{}{"engine-config-mode": "daemon-json"}, "worker", nil},
+	}
+
+	for _, tt := range tests {
+		commandLine := &commandstest.FakeCommandLine{
+			LocalFlags: &commandstest.FakeFlagger{
+				Data: tt.data,
+			},
+		}
+
+		assert.Equal(t, tt.expected, validateEngineMode(commandLine, tt.swarmMode))
+	}
+}
+
+func TestValidateEngineModeInvalid(t *testing.T) {
+	commandLine := &commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{"engine-mode": "root"},
+		},
+	}
+
+	assert.EqualError(t, validateEngineMode(commandLine, ""), `Invalid engine mode "root", expected rootless`)
+}
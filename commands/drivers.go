@@ -0,0 +1,307 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/drivers/plugin/localbinary"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/persist"
+)
+
+const (
+	driverTypeCore        = "core"
+	driverTypePlugin      = "plugin"
+	driverStatusOK        = "OK"
+	driverStatusMissing   = "missing"
+	driverStatusError     = "error"
+	driverStatusTimeout   = "timeout"
+	driverProbeTimeoutSec = 10
+)
+
+// driverInfo describes a driver as found on this system. The reason the
+// driver isn't OK is only logged in debug mode.
+type driverInfo struct {
+	Name         string
+	Type         string
+	Path         string
+	APIVersion   int
+	Capabilities []drivers.Capability
+	Flags        []mcnflag.Flag
+	Status       string
+	Machines     []string
+}
+
+type apiVersioner interface {
+	APIVersion() int
+}
+
+func cmdDrivers(c CommandLine, api libmachine.API) error {
+	machines, err := driverMachines(api)
+	if err != nil {
+		return err
+	}
+
+	names := c.Args()
+	if len(names) == 0 {
+		names = driverNames(localbinary.CoreDrivers, localbinary.PluginDrivers(), machines)
+	}
+
+	infos := probeDrivers(names, func(name string) driverInfo {
+		return probeDriver(api, name)
+	}, driverProbeTimeoutSec*time.Second)
+	for i := range infos {
+		infos[i].Machines = machines[infos[i].Name]
+	}
+
+	if len(c.Args()) == 0 {
+		printDrivers(os.Stdout, infos)
+		return nil
+	}
+
+	for i, info := range infos {
+		if i > 0 {
+			fmt.Println()
+		}
+		printDriverDetails(os.Stdout, info)
+	}
+
+	return nil
+}
+
+// driverMachines returns the names of the stored machines by the name of
+// their driver.
+func driverMachines(api libmachine.API) (map[string][]string, error) {
+	hosts, hostsInError, err := persist.LoadAllHosts(api)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, err := range hostsInError {
+		log.Debugf("Skipping machine %s which can't be loaded: %s", name, err)
+	}
+
+	machines := map[string][]string{}
+	for _, h := range hosts {
+		machines[h.DriverName] = append(machines[h.DriverName], h.Name)
+	}
+
+	for _, names := range machines {
+		sort.Strings(names)
+	}
+
+	return machines, nil
+}
+
+// driverNames returns the core drivers, then the plugin drivers and the
+// drivers only known from stored machines, sorted by name.
+func driverNames(core, plugins []string, machines map[string][]string) []string {
+	names := append([]string{}, core...)
+	seen := map[string]bool{}
+	for _, name := range core {
+		seen[name] = true
+	}
+
+	others := []string{}
+	for _, name := range plugins {
+		if !seen[name] {
+			seen[name] = true
+			others = append(others, name)
+		}
+	}
+
+	for name := range machines {
+		if !seen[name] {
+			seen[name] = true
+			others = append(others, name)
+		}
+	}
+
+	sort.Strings(others)
+
+	return append(names, others...)
+}
+
+// probeDrivers probes the drivers in parallel, in the order of their names.
+// The drivers whose probe doesn't return within the timeout have the timeout
+// status.
+func probeDrivers(names []string, probe func(name string) driverInfo, timeout time.Duration) []driverInfo {
+	results := make([]chan driverInfo, len(names))
+	for i, name := range names {
+		results[i] = make(chan driverInfo, 1)
+		go func(name string, result chan<- driverInfo) {
+			result <- probe(name)
+		}(name, results[i])
+	}
+
+	deadline := time.After(timeout)
+	expired := false
+
+	infos := make([]driverInfo, len(names))
+	for i, name := range names {
+		if !expired {
+			select {
+			case infos[i] = <-results[i]:
+				continue
+			case <-deadline:
+				expired = true
+			}
+		}
+
+		// past the deadline, only the probes already done are waited for
+		select {
+		case infos[i] = <-results[i]:
+		default:
+			log.Debugf("Probing the %s driver timed out after %s", name, timeout)
+			infos[i] = driverInfo{
+				Name:   name,
+				Type:   driverType(name),
+				Status: driverStatusTimeout,
+			}
+		}
+	}
+
+	return infos
+}
+
+func driverType(name string) string {
+	if localbinary.IsCoreDriver(name) {
+		return driverTypeCore
+	}
+	return driverTypePlugin
+}
+
+// probeDriver starts the driver binary of name to get the version of the
+// plugin API it serves and its create flags.
+func probeDriver(api libmachine.API, name string) driverInfo {
+	info := driverInfo{
+		Name:   name,
+		Type:   driverType(name),
+		Status: driverStatusOK,
+	}
+
+	path, err := localbinary.LookupDriver(name)
+	if err != nil {
+		log.Debugf("The %s driver is missing: %s", name, err)
+		info.Status = driverStatusMissing
+		return info
+	}
+	info.Path = path
+
+	rawDriver, err := json.Marshal(&drivers.BaseDriver{
+		MachineName: "driver-probe",
+	})
+	if err != nil {
+		log.Debugf("Unable to probe the %s driver: %s", name, err)
+		info.Status = driverStatusError
+		return info
+	}
+
+	h, err := api.NewHost(name, rawDriver)
+	if err != nil {
+		log.Debugf("Unable to probe the %s driver: %s", name, err)
+		info.Status = driverStatusError
+		return info
+	}
+
+	if versioner, ok := h.Driver.(apiVersioner); ok {
+		info.APIVersion = versioner.APIVersion()
+	}
+	info.Capabilities = drivers.Capabilities(h.Driver)
+	info.Flags = h.Driver.GetCreateFlags()
+
+	return info
+}
+
+func printDrivers(out io.Writer, infos []driverInfo) {
+	w := tabwriter.NewWriter(out, 5, 1, 3, ' ', 0)
+
+	fmt.Fprintln(w, "NAME\tTYPE\tAPI\tPATH\tSTATUS\tMACHINES")
+	for _, info := range infos {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", info.Name, info.Type, apiVersionColumn(info), info.Path, info.Status, strings.Join(info.Machines, ", "))
+	}
+
+	w.Flush()
+
+	for _, info := range infos {
+		if info.Status != driverStatusOK && len(info.Machines) > 0 {
+			fmt.Fprintf(out, "\nThe %s driver is unavailable (%s) and used by: %s\n", info.Name, info.Status, strings.Join(info.Machines, ", "))
+		}
+	}
+
+	for _, info := range infos {
+		if info.Status == driverStatusError || info.Status == driverStatusTimeout {
+			fmt.Fprintln(out, "\nRun with --debug to see why drivers are unavailable.")
+			break
+		}
+	}
+}
+
+func printDriverDetails(out io.Writer, info driverInfo) {
+	w := tabwriter.NewWriter(out, 5, 1, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Name:\t%s\n", info.Name)
+	fmt.Fprintf(w, "Type:\t%s\n", info.Type)
+	fmt.Fprintf(w, "Path:\t%s\n", info.Path)
+	fmt.Fprintf(w, "API version:\t%s\n", apiVersionColumn(info))
+	fmt.Fprintf(w, "Status:\t%s\n", info.Status)
+	fmt.Fprintf(w, "Machines:\t%s\n", strings.Join(info.Machines, ", "))
+	fmt.Fprintf(w, "Capabilities:\t%s\n", capabilitiesColumn(info.Capabilities))
+
+	if len(info.Flags) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "Create flags:")
+	for _, flag := range info.Flags {
+		fmt.Fprintf(w, "  --%s\t%s\n", flag.String(), flagUsage(flag))
+	}
+}
+
+func apiVersionColumn(info driverInfo) string {
+	if info.APIVersion == 0 {
+		return "-"
+	}
+	return fmt.Sprint(info.APIVersion)
+}
+
+func flagUsage(flag mcnflag.Flag) string {
+	usage := mcnflag.Usage(flag)
+
+	switch value := flag.Default().(type) {
+	case nil, bool:
+		return usage
+	case string:
+		if value == "" {
+			return usage
+		}
+	case []string:
+		if len(value) == 0 {
+			return usage
+		}
+	case map[string]string:
+		if len(value) == 0 {
+			return usage
+		}
+	}
+
+	return fmt.Sprintf("%s (default %v)", usage, flag.Default())
+}
+
+func capabilitiesColumn(capabilities []drivers.Capability) string {
+	names := []string{}
+	for _, capability := range capabilities {
+		names = append(names, string(capability))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriverMachines(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			{Name: "web", DriverName: "virtualbox", Driver: &fakedriver.Driver{}},
+			{Name: "db", DriverName: "virtualbox", Driver: &fakedriver.Driver{}},
+			{Name: "cloud", DriverName: "kvm", Driver: &fakedriver.Driver{}},
+		},
+	}
+
+	machines, err := driverMachines(api)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"virtualbox": {"db", "web"},
+		"kvm":        {"cloud"},
+	}, machines)
+}
+
+func TestDriverNames(t *testing.T) {
+	names := driverNames(
+		[]string{"virtualbox", "generic"},
+		[]string{"xhyve", "generic", "kvm"},
+		map[string][]string{"virtualbox": {"default"}, "qemu": {"old"}},
+	)
+
+	assert.Equal(t, []string{"virtualbox", "generic", "kvm", "qemu", "xhyve"}, names)
+}
+
+func TestPrintDrivers(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printDrivers(out, []driverInfo{
+		{Name: "virtualbox", Type: "core", APIVersion: 1, Path: "/usr/bin/docker-machine", Status: "OK", Machines: []string{"default"}},
+		{Name: "qemu", Type: "plugin", Status: "missing", Machines: []string{"old", "test"}},
+	})
+
+	assert.Equal(t, "NAME         TYPE     API   PATH                      STATUS    MACHINES\n"+
+		"virtualbox   core     1     /usr/bin/docker-machine   OK        default\n"+
+		"qemu         plugin   -                               missing   old, test\n"+
+		"\nThe qemu driver is unavailable (missing) and used by: old, test\n", out.String())
+}
+
+func TestPrintDriversError(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printDrivers(out, []driverInfo{
+		{Name: "kvm", Type: "plugin", Path: "/usr/local/bin/docker-machine-driver-kvm", Status: "error"},
+	})
+
+	assert.Equal(t, "NAME   TYPE     API   PATH                                       STATUS   MACHINES\n"+
+		"kvm    plugin   -     /usr/local/bin/docker-machine-driver-kvm   error    \n"+
+		"\nRun with --debug to see why drivers are unavailable.\n", out.String())
+}
+
+func TestProbeDrivers(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	infos := probeDrivers([]string{"kvm", "xhyve", "virtualbox", "qemu"}, func(name string) driverInfo {
+		if name == "xhyve" || name == "qemu" {
+			<-unblock
+		}
+		return driverInfo{Name: name, Type: "plugin", Status: "OK"}
+	}, 10*time.Millisecond)
+
+	assert.Equal(t, []driverInfo{
+		{Name: "kvm", Type: "plugin", Status: "OK"},
+		{Name: "xhyve", Type: "plugin", Status: "timeout"},
+		{Name: "virtualbox", Type: "plugin", Status: "OK"},
+		{Name: "qemu", Type: "plugin", Status: "timeout"},
+	}, infos)
+}
+
+func TestPrintDriverDetails(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printDriverDetails(out, driverInfo{
+		Name:       "kvm",
+		Type:       "plugin",
+		APIVersion: 1,
+		Path:       "/usr/local/bin/docker-machine-driver-kvm",
+		Status:     "OK",
+		Flags: []mcnflag.Flag{
+			mcnflag.IntFlag{Name: "kvm-memory", Usage: "Size of memory in MB", Value: 1024},
+			mcnflag.BoolFlag{Name: "kvm-nested", Usage: "Enable nested virtualization"},
+			mcnflag.StringFlag{Name: "kvm-network", Usage: "Name of the network"},
+		},
+	})
+
+	assert.Equal(t, "Name:           kvm\n"+
+		"Type:           plugin\n"+
+		"Path:           /usr/local/bin/docker-machine-driver-kvm\n"+
+		"API version:    1\n"+
+		"Status:         OK\n"+
+		"Machines:       \n"+
+		"Capabilities:   \n"+
+		"Create flags:\n"+
+		"  --kvm-memory    Size of memory in MB (default 1024)\n"+
+		"  --kvm-nested    Enable nested virtualization\n"+
+		"  --kvm-network   Name of the network\n", out.String())
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/machine/libmachine/mcnflag"
+)
+
+// enumValue is the value of an mcnflag.EnumFlag on the command line, it
+// rejects the values the flag doesn't allow as soon as they're given. They
+// are kept anyway, for mcnflag.Validate to reject them when they come from
+// an environment variable, whose errors codegangsta/cli ignores.
+type enumValue struct {
+	flag  mcnflag.EnumFlag
+	value string
+}
+
+func newEnumValue(f mcnflag.EnumFlag) *enumValue {
+	return &enumValue{
+		flag:  f,
+		value: f.Value,
+	}
+}
+
+func (v *enumValue) Set(value string) error {
+	v.value = value
+
+	for _, allowed := range v.flag.Values {
+		if value == allowed {
+			return nil
+		}
+	}
+
+	return mcnflag.ErrNotAllowed{
+		Name:    v.flag.Name,
+		Value:   value,
+		Allowed: v.flag.Values,
+	}
+}
+
+func (v *enumValue) String() string {
+	return v.value
+}
+
+func (v *enumValue) Get() interface{} {
+	return v.value
+}
+
+// mapValue is the value of an mcnflag.MapFlag on the command line. Each
+// time the flag is given, it takes one or more comma separated key=value
+// pairs, which replace the default pairs.
+type mapValue struct {
+	values map[string]string
+	isSet  bool
+}
+
+func newMapValue(f mcnflag.MapFlag) *mapValue {
+	values := map[string]string{}
+	for k, v := range f.Value {
+		values[k] = v
+	}
+
+	return &mapValue{
+		values: values,
+	}
+}
+
+func (v *mapValue) Set(value string) error {
+	if !v.isSet {
+		v.values = map[string]string{}
+		v.isSet = true
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("%q isn't a key=value pair", pair)
+		}
+
+		v.values[parts[0]] = parts[1]
+	}
+
+	return nil
+}
+
+func (v *mapValue) String() string {
+	pairs := []string{}
+	for k, val := range v.values {
+		pairs = append(pairs, k+"="+val)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (v *mapValue) Get() interface{} {
+	values := map[string]string{}
+	for k, val := range v.values {
+		values[k] = val
+	}
+
+	return values
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumValue(t *testing.T) {
+	value := newEnumValue(mcnflag.EnumFlag{
+		Name:   "ui",
+		Value:  "headless",
+		Values: []string{"gui", "headless"},
+	})
+
+	assert.Equal(t, "headless", value.Get())
+	assert.NoError(t, value.Set("gui"))
+	assert.Equal(t, "gui", value.Get())
+	assert.Equal(t, mcnflag.ErrNotAllowed{Name: "ui", Value: "sdl", Allowed: []string{"gui", "headless"}}, value.Set("sdl"))
+}
+
+func TestMapValue(t *testing.T) {
+	value := newMapValue(mcnflag.MapFlag{
+		Value: map[string]string{"env": "test"},
+	})
+
+	assert.Equal(t, map[string]string{"env": "test"}, value.Get())
+
+	assert.NoError(t, value.Set("team=infra,env=prod"))
+	assert.NoError(t, value.Set("owner=me"))
+
+	assert.Equal(t, map[string]string{"team": "infra", "env": "prod", "owner": "me"}, value.Get())
+	assert.Equal(t, "env=prod,owner=me,team=infra", value.String())
+	assert.Error(t, value.Set("invalid"))
+}
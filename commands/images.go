@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/docker/machine/commands/mcndirs"
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnutils"
+)
+
+var errNoImagePath = errors.New("Error: Expected the path of an ISO as argument")
+
+func cmdImagesLs(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) > 0 {
+		return ErrTooManyArguments
+	}
+
+	images, err := mcnutils.NewImageCache(mcndirs.GetBaseDir()).List()
+	if err != nil {
+		return err
+	}
+
+	printImages(os.Stdout, images)
+
+	return nil
+}
+
+func cmdImagesPull(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) > 1 {
+		return ErrTooManyArguments
+	}
+
+	ref, digest := mcnutils.SplitDigest(c.Args().First())
+	if c.String("sha256") != "" {
+		digest = c.String("sha256")
+	}
+
+	image, err := mcnutils.NewB2dUtils(mcndirs.GetBaseDir()).PullImage(ref, digest)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Pulled %s (%s) into the image cache.", image.URL, image.Version)
+
+	return nil
+}
+
+func cmdImagesImport(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) != 1 {
+		return errNoImagePath
+	}
+
+	image, err := mcnutils.NewImageCache(mcndirs.GetBaseDir()).Import(c.Args().First(), c.String("sha256"))
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Imported %s (%s) into the image cache.", image.URL, image.Version)
+
+	return nil
+}
+
+func cmdImagesPrune(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) > 0 {
+		return ErrTooManyArguments
+	}
+
+	keep := 1
+	if c.Bool("all") {
+		keep = 0
+	}
+
+	removed, err := mcnutils.NewImageCache(mcndirs.GetBaseDir()).Prune(keep)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range removed {
+		log.Infof("Removed %s (%s) from the image cache.", image.URL, image.Version)
+	}
+
+	return nil
+}
+
+func printImages(out io.Writer, images []*mcnutils.CachedImage) {
+	w := tabwriter.NewWriter(out, 5, 1, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "VERSION\tSHA256\tSIZE\tPULLED\tURL")
+	for _, image := range images {
+		digest := image.SHA256
+		if len(digest) > 12 {
+			digest = digest[:12]
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			image.Version,
+			digest,
+			units.HumanSize(float64(image.Size)),
+			image.Pulled.Local().Format(snapshotTimeFormat),
+			image.URL)
+	}
+}
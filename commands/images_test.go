@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/commands/mcndirs"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// withImageStore points the base dir to a temporary store with an ISO to
+// import.
+func withImageStore(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "machine-images-")
+	assert.NoError(t, err)
+
+	baseDir := mcndirs.BaseDir
+	mcndirs.BaseDir = dir
+
+	isoPath := filepath.Join(dir, "local.iso")
+	assert.NoError(t, ioutil.WriteFile(isoPath, []byte("iso"), 0644))
+
+	return isoPath, func() {
+		mcndirs.BaseDir = baseDir
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCmdImagesImport(t *testing.T) {
+	isoPath, cleanup := withImageStore(t)
+	defer cleanup()
+
+	err := cmdImagesImport(&commandstest.FakeCommandLine{
+		CliArgs: []string{isoPath},
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{
+				"sha256": strings.Repeat("0", 64),
+			},
+		},
+	}, &libmachinetest.FakeAPI{})
+
+	assert.IsType(t, mcnutils.ErrChecksumMismatch{}, err)
+
+	err = cmdImagesImport(&commandstest.FakeCommandLine{
+		CliArgs:    []string{isoPath},
+		LocalFlags: &commandstest.FakeFlagger{},
+	}, &libmachinetest.FakeAPI{})
+
+	assert.NoError(t, err)
+
+	images, err := mcnutils.NewImageCache(mcndirs.GetBaseDir()).List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+}
+
+func TestCmdImagesImportNoPath(t *testing.T) {
+	err := cmdImagesImport(&commandstest.FakeCommandLine{}, &libmachinetest.FakeAPI{})
+
+	assert.Equal(t, errNoImagePath, err)
+}
+
+func TestCmdImagesPrune(t *testing.T) {
+	isoPath, cleanup := withImageStore(t)
+	defer cleanup()
+
+	cache := mcnutils.NewImageCache(mcndirs.GetBaseDir())
+	for _, name := range []string{"first.iso", "second.iso", "third.iso"} {
+		path := filepath.Join(filepath.Dir(isoPath), name)
+		assert.NoError(t, os.Rename(isoPath, path))
+
+		_, err := cache.Import(path, "")
+		assert.NoError(t, err)
+
+		isoPath = path
+	}
+
+	images, err := cache.List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 3)
+
+	err = cmdImagesPrune(&commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{},
+	}, &libmachinetest.FakeAPI{})
+
+	assert.NoError(t, err)
+
+	images, err = cache.List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+
+	err = cmdImagesPrune(&commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{
+				"all": true,
+			},
+		},
+	}, &libmachinetest.FakeAPI{})
+
+	assert.NoError(t, err)
+
+	images, err = cache.List()
+	assert.NoError(t, err)
+	assert.Empty(t, images)
+}
+
+func TestPrintImages(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printImages(out, []*mcnutils.CachedImage{
+		{
+			Version: "v18.09.1",
+			URL:     "https://github.com/boot2docker/boot2docker/releases/download/v18.09.1/boot2docker.iso",
+			SHA256:  strings.Repeat("ab", 32),
+			Size:    88000000,
+			Pulled:  time.Date(2018, 10, 2, 13, 4, 5, 0, time.UTC),
+		},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, []string{"VERSION", "SHA256", "SIZE", "PULLED", "URL"}, strings.Fields(lines[0]))
+	assert.Contains(t, lines[1], "v18.09.1")
+	assert.Contains(t, lines[1], "abababababab")
+	assert.Contains(t, lines[1], "88 MB")
+	assert.Contains(t, lines[1], "/download/v18.09.1/boot2docker.iso")
+}
+
+func TestPrintImagesShortDigest(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printImages(out, []*mcnutils.CachedImage{{Version: "v18.09.1", SHA256: "abc"}})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "abc")
+}
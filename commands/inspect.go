@@ -7,6 +7,9 @@ import (
 	"text/template"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/secrets"
 )
 
 var funcMap = template.FuncMap{
@@ -20,6 +23,33 @@ var funcMap = template.FuncMap{
 	},
 }
 
+// inspectedHost adds what's known about a host at runtime to its stored
+// configuration, with the secrets of its driver masked.
+type inspectedHost struct {
+	*host.Host
+	Driver       json.RawMessage
+	Capabilities []drivers.Capability
+}
+
+func newInspectedHost(h *host.Host) (*inspectedHost, error) {
+	rawDriver, err := json.Marshal(h.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.HostOptions != nil {
+		if rawDriver, err = secrets.Mask(rawDriver, h.HostOptions.SecretFields); err != nil {
+			return nil, err
+		}
+	}
+
+	return &inspectedHost{
+		Host:         h,
+		Driver:       rawDriver,
+		Capabilities: drivers.Capabilities(h.Driver),
+	}, nil
+}
+
 func cmdInspect(c CommandLine, api libmachine.API) error {
 	if len(c.Args()) > 1 {
 		c.ShowHelp()
@@ -31,7 +61,12 @@ func cmdInspect(c CommandLine, api libmachine.API) error {
 		return err
 	}
 
-	host, err := api.Load(target)
+	h, err := api.Load(target)
+	if err != nil {
+		return err
+	}
+
+	inspected, err := newInspectedHost(h)
 	if err != nil {
 		return err
 	}
@@ -44,7 +79,7 @@ func cmdInspect(c CommandLine, api libmachine.API) error {
 			return fmt.Errorf("template parsing error: %v", err)
 		}
 
-		jsonHost, err := json.Marshal(host)
+		jsonHost, err := json.Marshal(inspected)
 		if err != nil {
 			return err
 		}
@@ -60,7 +95,7 @@ func cmdInspect(c CommandLine, api libmachine.API) error {
 
 		os.Stdout.Write([]byte{'\n'})
 	} else {
-		prettyJSON, err := json.MarshalIndent(host, "", "    ")
+		prettyJSON, err := json.MarshalIndent(inspected, "", "    ")
 		if err != nil {
 			return err
 		}
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/drivers/fakedriver"
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/libmachinetest"
 	"github.com/stretchr/testify/assert"
 )
@@ -29,3 +33,46 @@ func TestCmdInspect(t *testing.T) {
 		assert.Equal(t, tc.expectedErr, err)
 	}
 }
+
+func TestInspectedHostCapabilities(t *testing.T) {
+	h := &host.Host{
+		Name:       "default",
+		DriverName: "Driver",
+		Driver:     &fakedriver.Driver{},
+	}
+
+	inspected, err := newInspectedHost(h)
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(inspected)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"default"`)
+	assert.Contains(t, string(data), `"Capabilities":[]`)
+}
+
+func TestInspectedHostMasksSecrets(t *testing.T) {
+	h := &host.Host{
+		Name:       "default",
+		DriverName: "Driver",
+		Driver: &fakedriver.Driver{
+			BaseDriver: &drivers.BaseDriver{
+				SSHUser: "docker",
+			},
+			MockName: "token",
+		},
+		HostOptions: &host.Options{
+			SecretFields: []string{"MockName"},
+		},
+	}
+
+	inspected, err := newInspectedHost(h)
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(inspected)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"MockName":"********"`)
+	assert.Contains(t, string(data), `"SSHUser":"docker"`)
+	assert.NotContains(t, string(data), "token")
+}
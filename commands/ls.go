@@ -42,6 +42,7 @@ var (
 		"URL":           "URL",
 		"SwarmOptions":  "SWARM_OPTIONS",
 		"Swarm":         "SWARM",
+		"SwarmMode":     "SWARM_MODE",
 		"EngineOptions": "ENGINE_OPTIONS",
 		"Error":         "ERRORS",
 		"DockerVersion": "DOCKER",
@@ -59,6 +60,7 @@ type HostListItem struct {
 	URL           string
 	SwarmOptions  *swarm.Options
 	Swarm         string
+	SwarmMode     string
 	EngineOptions *engine.Options
 	Error         string
 	DockerVersion string
@@ -384,9 +386,20 @@ func attemptGetHostState(h *host.Host, stateQueryChan chan<- HostListItem) {
 
 	isMaster := false
 	swarmHost := ""
+	var swarmModeInfo *mcndockerclient.SwarmModeInfo
 	if swarmOptions != nil {
 		isMaster = swarmOptions.Master
 		swarmHost = swarmOptions.Host
+
+		if swarmOptions.Mode != "" && currentState == state.Running && url != "" {
+			swarmModeInfo, err = mcndockerclient.SwarmMode(&mcndockerclient.RemoteDocker{
+				HostURL:    url,
+				AuthOption: h.AuthOptions(),
+			})
+			if err != nil {
+				log.Debugf("Unable to get the swarm mode state of %s: %s", h.Name, err)
+			}
+		}
 	}
 
 	activeHost := isActive(currentState, url)
@@ -408,6 +421,7 @@ func attemptGetHostState(h *host.Host, stateQueryChan chan<- HostListItem) {
 		State:         currentState,
 		URL:           url,
 		SwarmOptions:  swarmOptions,
+		SwarmMode:     swarmModeColumn(swarmOptions, swarmModeInfo),
 		EngineOptions: engineOptions,
 		DockerVersion: dockerVersion,
 		Error:         hostError,
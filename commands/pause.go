@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/log"
+)
+
+func cmdPause(c CommandLine, api libmachine.API) error {
+	return runAction("pause", c, api)
+}
+
+func cmdUnpause(c CommandLine, api libmachine.API) error {
+	return runAction("unpause", c, api)
+}
+
+func cmdSuspend(c CommandLine, api libmachine.API) error {
+	return runAction("suspend", c, api)
+}
+
+func cmdResume(c CommandLine, api libmachine.API) error {
+	if err := runAction("resume", c, api); err != nil {
+		return err
+	}
+
+	log.Info("Resumed machines may have new IP addresses. You may need to re-run the `docker-machine env` command.")
+
+	return nil
+}
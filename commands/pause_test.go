@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePauseDriver struct {
+	*fakedriver.Driver
+}
+
+func (d *fakePauseDriver) Pause() error {
+	d.MockState = state.Paused
+	return nil
+}
+
+func (d *fakePauseDriver) Unpause() error {
+	d.MockState = state.Running
+	return nil
+}
+
+func TestCmdPause(t *testing.T) {
+	driver := &fakePauseDriver{&fakedriver.Driver{MockState: state.Running}}
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			{
+				Name:   "foo",
+				Driver: driver,
+			},
+			{
+				Name:   "bar",
+				Driver: &fakedriver.Driver{MockState: state.Running},
+			},
+		},
+	}
+
+	err := cmdPause(&commandstest.FakeCommandLine{
+		CliArgs: []string{"foo", "bar"},
+	}, api)
+
+	assert.EqualError(t, err, `Driver "Driver" does not support pause`)
+	assert.Equal(t, state.Paused, driver.MockState)
+}
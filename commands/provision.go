@@ -1,7 +1,69 @@
 package commands
 
-import "github.com/docker/machine/libmachine"
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/provision"
+)
 
 func cmdProvision(c CommandLine, api libmachine.API) error {
-	return runAction("provision", c, api)
+	if !c.Bool("dry-run") {
+		return runAction("provision", c, api)
+	}
+
+	hosts, err := loadTargetHosts(c, api)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range hosts {
+		plan, err := h.PlanProvision()
+		if err != nil {
+			return fmt.Errorf("Error planning the provisioning of %s: %s", h.Name, err)
+		}
+
+		if err := printProvisionPlan(os.Stdout, h.Name, plan); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func printProvisionPlan(w io.Writer, name string, plan *provision.ProvisionPlan) error {
+	fmt.Fprintf(w, "Provisioning plan for %s (provisioner: %s)\n", name, plan.Provisioner)
+
+	fmt.Fprintf(w, "\nServer certificate SANs: %s\n", strings.Join(plan.CertSANs, ", "))
+
+	fmt.Fprintln(w, "\nFiles:")
+	for _, file := range plan.Files {
+		switch {
+		case file.Generated:
+			fmt.Fprintf(w, "  %s (regenerated)\n", file.Path)
+		case !file.Changed():
+			fmt.Fprintf(w, "  %s (unchanged)\n", file.Path)
+		default:
+			fmt.Fprintf(w, "  %s (changed)\n", file.Path)
+			diff, err := file.Diff()
+			if err != nil {
+				return err
+			}
+			for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "\nCommands:")
+	for _, command := range plan.Commands {
+		fmt.Fprintf(w, "  %s\n", command)
+	}
+
+	fmt.Fprintln(w)
+
+	return nil
 }
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/docker/machine/commands/commandstest"
@@ -65,3 +66,28 @@ func TestCmdProvision(t *testing.T) {
 		assert.Equal(t, tc.expectedErr, cmdProvision(tc.commandLine, tc.api))
 	}
 }
+
+func TestPrintProvisionPlan(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	err := printProvisionPlan(out, "foo", &provision.ProvisionPlan{
+		Provisioner: "ubuntu(systemd)",
+		CertSANs:    []string{"1.2.3.4", "localhost"},
+		Files: []provision.PlannedFile{
+			{Path: "/etc/docker/ca.pem", Current: "CA\n", Desired: "CA\n"},
+			{Path: "/etc/docker/server.pem", Generated: true},
+			{Path: "/etc/default/docker", Current: "a\n", Desired: "b\n"},
+		},
+		Commands: []string{"stop service docker", "start service docker"},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Provisioning plan for foo (provisioner: ubuntu(systemd))")
+	assert.Contains(t, out.String(), "Server certificate SANs: 1.2.3.4, localhost")
+	assert.Contains(t, out.String(), "  /etc/docker/ca.pem (unchanged)\n")
+	assert.Contains(t, out.String(), "  /etc/docker/server.pem (regenerated)\n")
+	assert.Contains(t, out.String(), "  /etc/default/docker (changed)\n")
+	assert.Contains(t, out.String(), "    -a\n")
+	assert.Contains(t, out.String(), "    +b\n")
+	assert.Contains(t, out.String(), "  stop service docker\n")
+}
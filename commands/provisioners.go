@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/provision"
+)
+
+func cmdProvisioners(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) > 1 {
+		return ErrExpectedOneMachine
+	}
+
+	if len(c.Args()) == 0 {
+		printProvisioners(os.Stdout, provision.RegisteredNames())
+		return nil
+	}
+
+	h, err := api.Load(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	osReleaseInfo, err := provision.OsReleaseFromDriver(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	selected := h.HostOptions.Provisioner
+	matches := provision.MatchProvisioners(h.Driver, osReleaseInfo)
+	if selected == "" && len(matches) > 0 && matches[0].Compatible {
+		selected = matches[0].Name
+	}
+
+	printProvisionerMatches(os.Stdout, matches, selected)
+
+	return nil
+}
+
+func printProvisioners(out io.Writer, names []string) {
+	w := tabwriter.NewWriter(out, 5, 1, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "NAME")
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+}
+
+func printProvisionerMatches(out io.Writer, matches []provision.ProvisionerMatch, selected string) {
+	w := tabwriter.NewWriter(out, 5, 1, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "NAME\tPRIORITY\tMATCH\tSELECTED")
+	for _, match := range matches {
+		compatible := "-"
+		if match.Compatible {
+			compatible = "yes"
+			if match.IDLike != "" {
+				compatible = fmt.Sprintf("yes (ID_LIKE %s)", match.IDLike)
+			}
+		}
+
+		isSelected := ""
+		if match.Name == selected {
+			isSelected = "*"
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", match.Name, match.Priority, compatible, isSelected)
+	}
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintProvisioners(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printProvisioners(out, []string{"Debian", "Fedora"})
+
+	assert.Equal(t, "NAME\nDebian\nFedora\n", out.String())
+}
+
+func TestPrintProvisionerMatches(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printProvisionerMatches(out, []provision.ProvisionerMatch{
+		{Name: "Ubuntu-SystemD", Compatible: true, IDLike: "ubuntu"},
+		{Name: "Debian", Compatible: true, IDLike: "debian"},
+		{Name: "Fedora", Priority: 1},
+	}, "Ubuntu-SystemD")
+
+	assert.Equal(t, "NAME             PRIORITY   MATCH                  SELECTED\n"+
+		"Ubuntu-SystemD   0          yes (ID_LIKE ubuntu)   *\n"+
+		"Debian           0          yes (ID_LIKE debian)   \n"+
+		"Fedora           1          -                      \n", out.String())
+}
+
+func TestCmdProvisionersTooManyArgs(t *testing.T) {
+	commandLine := &commandstest.FakeCommandLine{
+		CliArgs: []string{"foo", "bar"},
+	}
+
+	err := cmdProvisioners(commandLine, &libmachinetest.FakeAPI{})
+
+	assert.Equal(t, ErrExpectedOneMachine, err)
+}
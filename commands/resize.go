@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/log"
+)
+
+func cmdResize(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) > 1 {
+		return ErrExpectedOneMachine
+	}
+
+	target, err := targetHost(c, api)
+	if err != nil {
+		return err
+	}
+
+	h, err := api.Load(target)
+	if err != nil {
+		return err
+	}
+
+	resizeErr := h.Resize(drivers.ResizeOptions{
+		CPUs:        c.Int("cpus"),
+		Memory:      c.Int("memory"),
+		DiskSize:    c.Int("disk"),
+		MachineType: c.String("machine-type"),
+	})
+	if _, ok := resizeErr.(host.ErrResizeIncomplete); resizeErr != nil && !ok {
+		return resizeErr
+	}
+
+	// the driver resized the machine, its new size is saved whatever
+	// happened next
+	if err := api.Save(h); err != nil {
+		return err
+	}
+
+	if resizeErr != nil {
+		return resizeErr
+	}
+
+	log.Infof("Machine %q was resized.", h.Name)
+
+	return nil
+}
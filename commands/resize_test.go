@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdResizeNotSupported(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			{
+				Name:   "default",
+				Driver: &fakedriver.Driver{},
+			},
+		},
+	}
+
+	err := cmdResize(&commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{"cpus": 2},
+		},
+	}, api)
+
+	assert.Equal(t, drivers.ErrCapabilityNotSupported{DriverName: "Driver", Capability: drivers.CapabilityResize}, err)
+}
+
+func TestCmdResizeTooManyArgs(t *testing.T) {
+	err := cmdResize(&commandstest.FakeCommandLine{
+		CliArgs: []string{"foo", "bar"},
+	}, &libmachinetest.FakeAPI{})
+
+	assert.Equal(t, ErrExpectedOneMachine, err)
+}
+
+type failingStartResizeDriver struct {
+	*fakedriver.Driver
+	resized bool
+}
+
+func (d *failingStartResizeDriver) Resize(opts drivers.ResizeOptions) error {
+	d.resized = true
+	return nil
+}
+
+func (d *failingStartResizeDriver) Start() error {
+	return errors.New("Unable to start")
+}
+
+type savingAPI struct {
+	*libmachinetest.FakeAPI
+	saved []string
+}
+
+func (api *savingAPI) Save(h *host.Host) error {
+	api.saved = append(api.saved, h.Name)
+	return nil
+}
+
+func TestCmdResizeSavesIncompleteResize(t *testing.T) {
+	driver := &failingStartResizeDriver{Driver: &fakedriver.Driver{MockState: state.Stopped}}
+	api := &savingAPI{
+		FakeAPI: &libmachinetest.FakeAPI{
+			Hosts: []*host.Host{
+				{
+					Name:   "default",
+					Driver: driver,
+				},
+			},
+		},
+	}
+
+	err := cmdResize(&commandstest.FakeCommandLine{
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{"cpus": 2},
+		},
+	}, api)
+
+	assert.IsType(t, host.ErrResizeIncomplete{}, err)
+	assert.True(t, driver.resized)
+	assert.Equal(t, []string{"default"}, api.saved)
+}
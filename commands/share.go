@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+)
+
+var (
+	errNoSharedFolder = errors.New("Error: Expected a machine name and a shared folder as host:guestpath[:ro]")
+	errNoSharedGuest  = errors.New("Error: Expected a machine name and the guest path of a shared folder")
+)
+
+// loadShareHost loads the machine named by the first argument, and returns
+// the second argument.
+func loadShareHost(c CommandLine, api libmachine.API, errMissing error) (*host.Host, string, error) {
+	if len(c.Args()) != 2 {
+		return nil, "", errMissing
+	}
+
+	h, err := api.Load(c.Args().First())
+	if err != nil {
+		return nil, "", err
+	}
+
+	return h, c.Args().Get(1), nil
+}
+
+func cmdShareAdd(c CommandLine, api libmachine.API) error {
+	h, arg, err := loadShareHost(c, api, errNoSharedFolder)
+	if err != nil {
+		return err
+	}
+
+	folder, err := drivers.ParseSharedFolder(arg)
+	if err != nil {
+		return err
+	}
+
+	// the driver may run in another directory
+	if folder.HostPath, err = filepath.Abs(folder.HostPath); err != nil {
+		return err
+	}
+
+	if err := h.AddSharedFolder(folder); err != nil {
+		return err
+	}
+
+	return api.Save(h)
+}
+
+func cmdShareRm(c CommandLine, api libmachine.API) error {
+	h, guestPath, err := loadShareHost(c, api, errNoSharedGuest)
+	if err != nil {
+		return err
+	}
+
+	if err := h.RemoveSharedFolder(path.Join("/", guestPath)); err != nil {
+		return err
+	}
+
+	return api.Save(h)
+}
+
+func cmdShareLs(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) > 1 {
+		return ErrExpectedOneMachine
+	}
+
+	target, err := targetHost(c, api)
+	if err != nil {
+		return err
+	}
+
+	h, err := api.Load(target)
+	if err != nil {
+		return err
+	}
+
+	folders, err := h.ListSharedFolders()
+	if err != nil {
+		return err
+	}
+
+	printSharedFolders(os.Stdout, folders)
+
+	return nil
+}
+
+func printSharedFolders(out io.Writer, folders []drivers.SharedFolder) {
+	w := tabwriter.NewWriter(out, 5, 1, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "GUEST PATH\tHOST PATH\tMODE")
+	for _, folder := range folders {
+		mode := "rw"
+		if folder.ReadOnly {
+			mode = "ro"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", folder.GuestPath, folder.HostPath, mode)
+	}
+}
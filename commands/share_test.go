@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeShareDriver struct {
+	*fakedriver.Driver
+	folders []drivers.SharedFolder
+}
+
+func (d *fakeShareDriver) AddSharedFolder(folder drivers.SharedFolder) error {
+	d.folders = append(d.folders, folder)
+	return nil
+}
+
+func (d *fakeShareDriver) RemoveSharedFolder(guestPath string) error {
+	kept := []drivers.SharedFolder{}
+	for _, folder := range d.folders {
+		if folder.GuestPath != guestPath {
+			kept = append(kept, folder)
+		}
+	}
+	if len(kept) == len(d.folders) {
+		return fmt.Errorf("No shared folder at %s", guestPath)
+	}
+	d.folders = kept
+	return nil
+}
+
+func (d *fakeShareDriver) ListSharedFolders() ([]drivers.SharedFolder, error) {
+	return d.folders, nil
+}
+
+func newShareAPI(folders ...drivers.SharedFolder) (*libmachinetest.FakeAPI, *fakeShareDriver) {
+	driver := &fakeShareDriver{
+		Driver:  &fakedriver.Driver{},
+		folders: folders,
+	}
+
+	return &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			{
+				Name:   "default",
+				Driver: driver,
+			},
+		},
+	}, driver
+}
+
+func TestCmdShareAdd(t *testing.T) {
+	api, driver := newShareAPI()
+
+	err := cmdShareAdd(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default", "src:/src:ro"},
+	}, api)
+
+	hostPath, _ := filepath.Abs("src")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.SharedFolder{{HostPath: hostPath, GuestPath: "/src", ReadOnly: true}}, driver.folders)
+}
+
+func TestCmdShareAddInvalid(t *testing.T) {
+	api, _ := newShareAPI()
+
+	err := cmdShareAdd(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default"},
+	}, api)
+
+	assert.Equal(t, errNoSharedFolder, err)
+
+	err = cmdShareAdd(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default", "/src"},
+	}, api)
+
+	assert.Error(t, err)
+}
+
+func TestCmdShareAddNotSupported(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			{
+				Name:   "default",
+				Driver: &fakedriver.Driver{},
+			},
+		},
+	}
+
+	err := cmdShareAdd(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default", "/src:/src"},
+	}, api)
+
+	assert.Equal(t, drivers.ErrCapabilityNotSupported{
+		DriverName: "Driver",
+		Capability: drivers.CapabilityShare,
+	}, err)
+}
+
+func TestCmdShareRm(t *testing.T) {
+	api, driver := newShareAPI(
+		drivers.SharedFolder{HostPath: "/src", GuestPath: "/src"},
+		drivers.SharedFolder{HostPath: "/data", GuestPath: "/data"},
+	)
+
+	err := cmdShareRm(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default", "src/"},
+	}, api)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.SharedFolder{{HostPath: "/data", GuestPath: "/data"}}, driver.folders)
+
+	err = cmdShareRm(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default", "/src"},
+	}, api)
+
+	assert.Error(t, err)
+}
+
+func TestPrintSharedFolders(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printSharedFolders(out, []drivers.SharedFolder{
+		{HostPath: "/src", GuestPath: "/src"},
+		{HostPath: "/data", GuestPath: "/mnt/data", ReadOnly: true},
+	})
+
+	assert.Equal(t, "GUEST PATH   HOST PATH   MODE\n/src         /src        rw\n/mnt/data    /data       ro\n", out.String())
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const snapshotTimeFormat = "2006-01-02 15:04:05 MST"
+
+var (
+	errNoSnapshotName = errors.New("Error: Expected a machine name and a snapshot name as arguments")
+	errNoSnapshots    = errors.New("Error: The machine has no snapshot to restore")
+
+	snapshotNow = time.Now
+)
+
+// loadSnapshotHost loads the machine named by the first argument, or the
+// default machine, and returns the snapshot name given as second argument.
+func loadSnapshotHost(c CommandLine, api libmachine.API) (*host.Host, string, error) {
+	if len(c.Args()) > 2 {
+		return nil, "", ErrTooManyArguments
+	}
+
+	target, err := targetHost(c, api)
+	if err != nil {
+		return nil, "", err
+	}
+
+	h, err := api.Load(target)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return h, c.Args().Get(1), nil
+}
+
+// defaultSnapshotName returns a name valid for the snapshots of every
+// driver, which sorts by creation time.
+func defaultSnapshotName() string {
+	return "snapshot-" + snapshotNow().UTC().Format("20060102-150405")
+}
+
+func cmdSnapshotCreate(c CommandLine, api libmachine.API) error {
+	h, name, err := loadSnapshotHost(c, api)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = defaultSnapshotName()
+	}
+
+	if err := h.CreateSnapshot(name); err != nil {
+		return err
+	}
+
+	fmt.Println(name)
+
+	return nil
+}
+
+func cmdSnapshotLs(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) > 1 {
+		return ErrExpectedOneMachine
+	}
+
+	h, _, err := loadSnapshotHost(c, api)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := h.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	printSnapshots(os.Stdout, snapshots)
+
+	return nil
+}
+
+func cmdSnapshotRestore(c CommandLine, api libmachine.API) error {
+	h, name, err := loadSnapshotHost(c, api)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		snapshots, err := h.ListSnapshots()
+		if err != nil {
+			return err
+		}
+
+		if len(snapshots) == 0 {
+			return errNoSnapshots
+		}
+
+		name = snapshots[len(snapshots)-1].Name
+	}
+
+	if err := h.RestoreSnapshot(name); err != nil {
+		return err
+	}
+
+	log.Infof("Machine %q was restored to snapshot %q.", h.Name, name)
+
+	return api.Save(h)
+}
+
+func cmdSnapshotRm(c CommandLine, api libmachine.API) error {
+	if len(c.Args()) != 2 {
+		return errNoSnapshotName
+	}
+
+	h, name, err := loadSnapshotHost(c, api)
+	if err != nil {
+		return err
+	}
+
+	return h.RemoveSnapshot(name)
+}
+
+func printSnapshots(out io.Writer, snapshots []drivers.Snapshot) {
+	w := tabwriter.NewWriter(out, 5, 1, 3, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "NAME\tCREATED")
+	for _, snapshot := range snapshots {
+		created := "-"
+		if !snapshot.Created.IsZero() {
+			created = snapshot.Created.Local().Format(snapshotTimeFormat)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\n", snapshot.Name, created)
+	}
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSnapshotDriver struct {
+	*fakedriver.Driver
+	snapshots []drivers.Snapshot
+}
+
+func (d *fakeSnapshotDriver) CreateSnapshot(name string) error {
+	d.snapshots = append(d.snapshots, drivers.Snapshot{Name: name})
+	return nil
+}
+
+func (d *fakeSnapshotDriver) ListSnapshots() ([]drivers.Snapshot, error) {
+	return d.snapshots, nil
+}
+
+func (d *fakeSnapshotDriver) RestoreSnapshot(name string) error {
+	return nil
+}
+
+func (d *fakeSnapshotDriver) RemoveSnapshot(name string) error {
+	kept := []drivers.Snapshot{}
+	for _, snapshot := range d.snapshots {
+		if snapshot.Name != name {
+			kept = append(kept, snapshot)
+		}
+	}
+	d.snapshots = kept
+	return nil
+}
+
+func newSnapshotAPI(snapshots ...string) (*libmachinetest.FakeAPI, *fakeSnapshotDriver) {
+	driver := &fakeSnapshotDriver{
+		Driver: &fakedriver.Driver{},
+	}
+	for _, name := range snapshots {
+		driver.snapshots = append(driver.snapshots, drivers.Snapshot{Name: name})
+	}
+
+	return &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			{
+				Name:   "default",
+				Driver: driver,
+			},
+		},
+	}, driver
+}
+
+func TestCmdSnapshotCreateDefaultName(t *testing.T) {
+	defer func(now func() time.Time) { snapshotNow = now }(snapshotNow)
+	snapshotNow = func() time.Time { return time.Date(2018, 10, 2, 13, 4, 5, 0, time.UTC) }
+
+	api, driver := newSnapshotAPI()
+
+	err := cmdSnapshotCreate(&commandstest.FakeCommandLine{}, api)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.Snapshot{{Name: "snapshot-20181002-130405"}}, driver.snapshots)
+}
+
+func TestCmdSnapshotRm(t *testing.T) {
+	api, driver := newSnapshotAPI("first", "second")
+
+	err := cmdSnapshotRm(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default", "first"},
+	}, api)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.Snapshot{{Name: "second"}}, driver.snapshots)
+}
+
+func TestCmdSnapshotRmNoSnapshotName(t *testing.T) {
+	api, _ := newSnapshotAPI("first")
+
+	err := cmdSnapshotRm(&commandstest.FakeCommandLine{
+		CliArgs: []string{"default"},
+	}, api)
+
+	assert.Equal(t, errNoSnapshotName, err)
+}
+
+func TestCmdSnapshotRestoreNoSnapshots(t *testing.T) {
+	api, _ := newSnapshotAPI()
+
+	err := cmdSnapshotRestore(&commandstest.FakeCommandLine{}, api)
+
+	assert.Equal(t, errNoSnapshots, err)
+}
+
+func TestCmdSnapshotNotSupported(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			{
+				Name:   "default",
+				Driver: &fakedriver.Driver{},
+			},
+		},
+	}
+
+	err := cmdSnapshotLs(&commandstest.FakeCommandLine{}, api)
+
+	assert.Equal(t, drivers.ErrCapabilityNotSupported{DriverName: "Driver", Capability: drivers.CapabilitySnapshot}, err)
+}
+
+func TestPrintSnapshots(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	printSnapshots(out, []drivers.Snapshot{
+		{Name: "before-upgrade"},
+	})
+
+	assert.Equal(t, "NAME             CREATED\nbefore-upgrade   -\n", out.String())
+}
@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcndockerclient"
+	"github.com/docker/machine/libmachine/persist"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/docker/machine/libmachine/swarm"
+)
+
+var (
+	errSwarmModeRole    = errors.New("--swarm-mode-manager and --swarm-mode-worker are mutually exclusive")
+	errSwarmModeCluster = errors.New("--swarm-mode-cluster is required for swarm mode")
+	errSwarmModeNoRole  = errors.New("--swarm-mode-cluster requires --swarm-mode-manager or --swarm-mode-worker")
+	errSwarmModeLegacy  = errors.New("Swarm mode can't be combined with the legacy --swarm and --swarm-master flags")
+)
+
+// ErrNoSwarmModeManager is returned when no manager of a swarm mode cluster
+// is found in the store.
+type ErrNoSwarmModeManager struct {
+	Cluster string
+}
+
+func (e ErrNoSwarmModeManager) Error() string {
+	return fmt.Sprintf("No manager found for swarm mode cluster %q", e.Cluster)
+}
+
+// ErrSwarmModeManagersNotRunning is returned when the managers of a swarm
+// mode cluster are in the store, but none of them is running.
+type ErrSwarmModeManagersNotRunning struct {
+	Cluster  string
+	Managers []string
+}
+
+func (e ErrSwarmModeManagersNotRunning) Error() string {
+	return fmt.Sprintf("No manager of swarm mode cluster %q is running, start one of: %s", e.Cluster, strings.Join(e.Managers, ", "))
+}
+
+// swarmModeFromFlags returns the swarm mode role requested on the command
+// line, if any.
+func swarmModeFromFlags(c CommandLine) (string, error) {
+	manager, worker := c.Bool("swarm-mode-manager"), c.Bool("swarm-mode-worker")
+
+	if manager && worker {
+		return "", errSwarmModeRole
+	}
+
+	if !manager && !worker {
+		if c.String("swarm-mode-cluster") != "" {
+			return "", errSwarmModeNoRole
+		}
+		return "", nil
+	}
+
+	if c.String("swarm-mode-cluster") == "" {
+		return "", errSwarmModeCluster
+	}
+
+	if c.Bool("swarm") || c.Bool("swarm-master") {
+		return "", errSwarmModeLegacy
+	}
+
+	if manager {
+		return swarm.ModeManager, nil
+	}
+
+	return swarm.ModeWorker, nil
+}
+
+// findSwarmModeManager returns a running manager of the swarm mode cluster,
+// other than the machine named exclude.
+func findSwarmModeManager(api libmachine.API, cluster, exclude string) (*host.Host, error) {
+	hosts, _, err := persist.LoadAllHosts(api)
+	if err != nil {
+		return nil, err
+	}
+
+	stopped := []string{}
+	for _, h := range hosts {
+		if h.Name == exclude || h.HostOptions == nil || h.HostOptions.SwarmOptions == nil {
+			continue
+		}
+
+		swarmOptions := h.HostOptions.SwarmOptions
+		if swarmOptions.Mode != swarm.ModeManager || swarmOptions.ModeCluster != cluster {
+			continue
+		}
+
+		if currentState, err := h.Driver.GetState(); err != nil || currentState != state.Running {
+			log.Debugf("Skipping swarm mode manager %s which isn't running", h.Name)
+			stopped = append(stopped, h.Name)
+			continue
+		}
+
+		return h, nil
+	}
+
+	if len(stopped) > 0 {
+		return nil, ErrSwarmModeManagersNotRunning{
+			Cluster:  cluster,
+			Managers: stopped,
+		}
+	}
+
+	return nil, ErrNoSwarmModeManager{
+		Cluster: cluster,
+	}
+}
+
+// setSwarmModeJoinOptions fills in the address and token to join the swarm
+// mode cluster from an existing manager. The first manager of a cluster
+// initializes it instead.
+func setSwarmModeJoinOptions(api libmachine.API, swarmOptions *swarm.Options) error {
+	if swarmOptions.Mode == "" {
+		return nil
+	}
+
+	manager, err := findSwarmModeManager(api, swarmOptions.ModeCluster, "")
+	if err != nil {
+		if _, ok := err.(ErrNoSwarmModeManager); ok && swarmOptions.Mode == swarm.ModeManager {
+			log.Infof("No manager found for swarm mode cluster %s, a new cluster will be initialized", swarmOptions.ModeCluster)
+			return nil
+		}
+		return err
+	}
+
+	ip, err := manager.Driver.GetIP()
+	if err != nil {
+		return err
+	}
+
+	token, err := manager.RunSSHCommand(fmt.Sprintf("sudo docker swarm join-token -q %s", swarmOptions.Mode))
+	if err != nil {
+		return fmt.Errorf("Error getting the swarm mode join token from %s: %s", manager.Name, err)
+	}
+
+	swarmOptions.ModeJoinAddr = net.JoinHostPort(ip, strconv.Itoa(swarm.ModePort))
+	swarmOptions.ModeJoinToken = strings.TrimSpace(token)
+
+	return nil
+}
+
+func cmdPromote(c CommandLine, api libmachine.API) error {
+	return changeSwarmModeRole(c, api, swarm.ModeWorker, swarm.ModeManager, "promote")
+}
+
+func cmdDemote(c CommandLine, api libmachine.API) error {
+	return changeSwarmModeRole(c, api, swarm.ModeManager, swarm.ModeWorker, "demote")
+}
+
+// changeSwarmModeRole runs docker node promote or demote for the given
+// machines on another manager of their cluster and persists the new role.
+func changeSwarmModeRole(c CommandLine, api libmachine.API, from, to, action string) error {
+	if len(c.Args()) == 0 {
+		return ErrNoMachineSpecified
+	}
+
+	hosts, err := loadTargetHosts(c, api)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range hosts {
+		swarmOptions := h.HostOptions.SwarmOptions
+		if swarmOptions == nil || swarmOptions.Mode == "" {
+			return fmt.Errorf("%s is not part of a swarm mode cluster", h.Name)
+		}
+
+		if swarmOptions.Mode != from {
+			return fmt.Errorf("%s is already a swarm mode %s", h.Name, to)
+		}
+
+		manager, err := findSwarmModeManager(api, swarmOptions.ModeCluster, h.Name)
+		if err != nil {
+			return err
+		}
+
+		nodeID, err := h.RunSSHCommand("sudo docker info --format '{{.Swarm.NodeID}}'")
+		if err != nil {
+			return fmt.Errorf("Error getting the swarm mode node ID of %s: %s", h.Name, err)
+		}
+
+		if _, err := manager.RunSSHCommand(fmt.Sprintf("sudo docker node %s %s", action, strings.TrimSpace(nodeID))); err != nil {
+			return fmt.Errorf("Error running docker node %s for %s on %s: %s", action, h.Name, manager.Name, err)
+		}
+
+		swarmOptions.Mode = to
+		if err := api.Save(h); err != nil {
+			return err
+		}
+
+		log.Infof("%s is now a swarm mode %s", h.Name, to)
+	}
+
+	return nil
+}
+
+// swarmModeColumn describes the role and node state of a machine in its
+// swarm mode cluster for ls.
+func swarmModeColumn(swarmOptions *swarm.Options, info *mcndockerclient.SwarmModeInfo) string {
+	if swarmOptions == nil || swarmOptions.Mode == "" {
+		return ""
+	}
+
+	if info == nil {
+		return fmt.Sprintf("%s (%s)", swarmOptions.ModeCluster, swarmOptions.Mode)
+	}
+
+	// report the role the engine has, which may differ from the persisted
+	// one if the node was promoted or demoted by hand
+	role := swarm.ModeWorker
+	if info.ControlAvailable {
+		role = swarm.ModeManager
+	}
+
+	return fmt.Sprintf("%s (%s, %s)", swarmOptions.ModeCluster, role, info.LocalNodeState)
+}
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/docker/machine/libmachine/mcndockerclient"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/docker/machine/libmachine/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func swarmModeHost(name, mode, cluster string, currentState state.State) *host.Host {
+	return &host.Host{
+		Name:   name,
+		Driver: &fakedriver.Driver{MockState: currentState},
+		HostOptions: &host.Options{
+			SwarmOptions: &swarm.Options{
+				Mode:        mode,
+				ModeCluster: cluster,
+			},
+		},
+	}
+}
+
+func TestSwarmModeFromFlags(t *testing.T) {
+	testCases := []struct {
+		flags        map[string]interface{}
+		expectedMode string
+		expectedErr  error
+	}{
+		{map[string]interface{}{}, "", nil},
+		{map[string]interface{}{"swarm-mode-manager": true, "swarm-mode-cluster": "prod"}, swarm.ModeManager, nil},
+		{map[string]interface{}{"swarm-mode-worker": true, "swarm-mode-cluster": "prod"}, swarm.ModeWorker, nil},
+		{map[string]interface{}{"swarm-mode-manager": true, "swarm-mode-worker": true, "swarm-mode-cluster": "prod"}, "", errSwarmModeRole},
+		{map[string]interface{}{"swarm-mode-worker": true}, "", errSwarmModeCluster},
+		{map[string]interface{}{"swarm-mode-cluster": "prod"}, "", errSwarmModeNoRole},
+		{map[string]interface{}{"swarm-mode-worker": true, "swarm-mode-cluster": "prod", "swarm": true}, "", errSwarmModeLegacy},
+	}
+
+	for _, tc := range testCases {
+		mode, err := swarmModeFromFlags(&commandstest.FakeCommandLine{
+			LocalFlags: &commandstest.FakeFlagger{Data: tc.flags},
+		})
+
+		assert.Equal(t, tc.expectedMode, mode)
+		assert.Equal(t, tc.expectedErr, err)
+	}
+}
+
+func TestFindSwarmModeManager(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			swarmModeHost("stopped-manager", swarm.ModeManager, "prod", state.Stopped),
+			swarmModeHost("other-cluster", swarm.ModeManager, "staging", state.Running),
+			swarmModeHost("worker", swarm.ModeWorker, "prod", state.Running),
+			swarmModeHost("manager", swarm.ModeManager, "prod", state.Running),
+		},
+	}
+
+	manager, err := findSwarmModeManager(api, "prod", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "manager", manager.Name)
+
+	_, err = findSwarmModeManager(api, "prod", "manager")
+	assert.Equal(t, ErrSwarmModeManagersNotRunning{Cluster: "prod", Managers: []string{"stopped-manager"}}, err)
+
+	_, err = findSwarmModeManager(api, "staging", "other-cluster")
+	assert.Equal(t, ErrNoSwarmModeManager{Cluster: "staging"}, err)
+}
+
+func TestSetSwarmModeJoinOptionsFirstManager(t *testing.T) {
+	swarmOptions := &swarm.Options{
+		Mode:        swarm.ModeManager,
+		ModeCluster: "prod",
+	}
+
+	err := setSwarmModeJoinOptions(&libmachinetest.FakeAPI{}, swarmOptions)
+
+	assert.NoError(t, err)
+	assert.Empty(t, swarmOptions.ModeJoinAddr)
+}
+
+func TestSetSwarmModeJoinOptionsStoppedManager(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			swarmModeHost("manager", swarm.ModeManager, "prod", state.Stopped),
+		},
+	}
+
+	swarmOptions := &swarm.Options{
+		Mode:        swarm.ModeManager,
+		ModeCluster: "prod",
+	}
+
+	err := setSwarmModeJoinOptions(api, swarmOptions)
+
+	assert.EqualError(t, err, `No manager of swarm mode cluster "prod" is running, start one of: manager`)
+	assert.Empty(t, swarmOptions.ModeJoinAddr)
+}
+
+func TestSetSwarmModeJoinOptionsWorkerWithoutManager(t *testing.T) {
+	err := setSwarmModeJoinOptions(&libmachinetest.FakeAPI{}, &swarm.Options{
+		Mode:        swarm.ModeWorker,
+		ModeCluster: "prod",
+	})
+
+	assert.Equal(t, ErrNoSwarmModeManager{Cluster: "prod"}, err)
+}
+
+func TestCmdPromoteAlreadyManager(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			swarmModeHost("manager", swarm.ModeManager, "prod", state.Running),
+		},
+	}
+
+	err := cmdPromote(&commandstest.FakeCommandLine{CliArgs: []string{"manager"}}, api)
+
+	assert.EqualError(t, err, "manager is already a swarm mode manager")
+}
+
+func TestCmdDemoteLastManager(t *testing.T) {
+	api := &libmachinetest.FakeAPI{
+		Hosts: []*host.Host{
+			swarmModeHost("manager", swarm.ModeManager, "prod", state.Running),
+		},
+	}
+
+	err := cmdDemote(&commandstest.FakeCommandLine{CliArgs: []string{"manager"}}, api)
+
+	assert.Equal(t, ErrNoSwarmModeManager{Cluster: "prod"}, err)
+}
+
+func TestSwarmModeColumn(t *testing.T) {
+	swarmOptions := &swarm.Options{Mode: swarm.ModeWorker, ModeCluster: "prod"}
+
+	assert.Equal(t, "", swarmModeColumn(&swarm.Options{}, nil))
+	assert.Equal(t, "prod (worker)", swarmModeColumn(swarmOptions, nil))
+	assert.Equal(t, "prod (manager, active)", swarmModeColumn(swarmOptions, &mcndockerclient.SwarmModeInfo{
+		LocalNodeState:   "active",
+		ControlAvailable: true,
+	}))
+}
@@ -1,7 +1,38 @@
 package commands
 
-import "github.com/docker/machine/libmachine"
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/engine"
+)
+
+// latestVersion unpins the engine version, later upgrades then install the
+// latest release again.
+const latestVersion = "latest"
 
 func cmdUpgrade(c CommandLine, api libmachine.API) error {
-	return runAction("upgrade", c, api)
+	version := c.String("to")
+	if version == "" {
+		return runAction("upgrade", c, api)
+	}
+
+	if version == latestVersion {
+		version = ""
+	} else if !engine.IsValidVersion(version) {
+		return fmt.Errorf("Invalid engine version %q, expected a release like 18.09.1 or %s", version, latestVersion)
+	}
+
+	hosts, err := loadTargetHosts(c, api)
+	if err != nil {
+		return err
+	}
+
+	// The requested version is persisted so that later provisioning keeps
+	// installing the same engine.
+	for _, h := range hosts {
+		h.HostOptions.EngineOptions.InstallVersion = version
+	}
+
+	return runActionOnHosts("upgrade", hosts, api)
 }
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/docker/machine/commands/commandstest"
+	"github.com/docker/machine/libmachine/libmachinetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdUpgradeInvalidVersion(t *testing.T) {
+	commandLine := &commandstest.FakeCommandLine{
+		CliArgs: []string{"default"},
+		LocalFlags: &commandstest.FakeFlagger{
+			Data: map[string]interface{}{
+				"to": "18.09; reboot",
+			},
+		},
+	}
+
+	err := cmdUpgrade(commandLine, &libmachinetest.FakeAPI{})
+
+	assert.EqualError(t, err, `Invalid engine version "18.09; reboot", expected a release like 18.09.1 or latest`)
+}
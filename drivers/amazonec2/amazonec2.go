@@ -143,6 +143,7 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Name:   "amazonec2-secret-key",
 			Usage:  "AWS Secret Key",
 			EnvVar: "AWS_SECRET_ACCESS_KEY",
+			Secret: true,
 		},
 		mcnflag.StringFlag{
 			Name:   "amazonec2-session-token",
@@ -463,6 +464,15 @@ func (d *Driver) DriverName() string {
 	return driverName
 }
 
+// ConcurrencyPolicy retries the calls throttled by the EC2 API.
+func (d *Driver) ConcurrencyPolicy() drivers.ConcurrencyPolicy {
+	return drivers.ConcurrencyPolicy{
+		MaxRetries:       5,
+		RetryBackoff:     2 * time.Second,
+		ThrottlingErrors: []string{"RequestLimitExceeded", "Throttling"},
+	}
+}
+
 func (d *Driver) checkPrereqs() error {
 	regionZone := d.getRegionZone()
 	if d.SubnetId == "" {
@@ -51,4 +51,28 @@ type Ec2Client interface {
 
 	WaitUntilSpotInstanceRequestFulfilled(input *ec2.DescribeSpotInstanceRequestsInput) error
 	CancelSpotInstanceRequests(input *ec2.CancelSpotInstanceRequestsInput) (*ec2.CancelSpotInstanceRequestsOutput, error)
+
+	//Snapshots
+
+	CreateSnapshot(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error)
+
+	DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error)
+
+	DeleteSnapshot(input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error)
+
+	//Volumes
+
+	CreateVolume(input *ec2.CreateVolumeInput) (*ec2.Volume, error)
+
+	AttachVolume(input *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error)
+
+	DetachVolume(input *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error)
+
+	DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error)
+
+	WaitUntilVolumeAvailable(input *ec2.DescribeVolumesInput) error
+
+	WaitUntilInstanceStopped(input *ec2.DescribeInstancesInput) error
+
+	ModifyInstanceAttribute(input *ec2.ModifyInstanceAttributeInput) (*ec2.ModifyInstanceAttributeOutput, error)
 }
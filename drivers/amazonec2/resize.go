@@ -0,0 +1,49 @@
+package amazonec2
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/docker/machine/libmachine/drivers"
+)
+
+var (
+	errResizeNoInstanceType = errors.New("Amazon EC2 instances are resized by instance type, use a machine type")
+	errResizeCPUsMemory     = errors.New("Amazon EC2 instances get the CPUs and memory of their instance type, give only a machine type")
+	errResizeDisk           = errors.New("Resizing the EBS volume of an Amazon EC2 instance is not supported")
+)
+
+// Resize changes the instance type of the stopped instance.
+func (d *Driver) Resize(opts drivers.ResizeOptions) error {
+	if opts.DiskSize > 0 {
+		return errResizeDisk
+	}
+
+	if opts.MachineType == "" {
+		return errResizeNoInstanceType
+	}
+
+	if opts.CPUs > 0 || opts.Memory > 0 {
+		return errResizeCPUsMemory
+	}
+
+	if err := d.getClient().WaitUntilInstanceStopped(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{&d.InstanceId},
+	}); err != nil {
+		return err
+	}
+
+	if _, err := d.getClient().ModifyInstanceAttribute(&ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(d.InstanceId),
+		InstanceType: &ec2.AttributeValue{
+			Value: aws.String(opts.MachineType),
+		},
+	}); err != nil {
+		return err
+	}
+
+	d.InstanceType = opts.MachineType
+
+	return nil
+}
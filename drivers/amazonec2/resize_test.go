@@ -0,0 +1,17 @@
+package amazonec2
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeOptions(t *testing.T) {
+	driver := NewDriver("machineFoo", "path")
+
+	assert.Equal(t, errResizeDisk, driver.Resize(drivers.ResizeOptions{DiskSize: 51200}))
+	assert.Equal(t, errResizeNoInstanceType, driver.Resize(drivers.ResizeOptions{CPUs: 2}))
+	assert.Equal(t, errResizeCPUsMemory, driver.Resize(drivers.ResizeOptions{MachineType: "t2.large", Memory: 8192}))
+	assert.Equal(t, defaultInstanceType, driver.InstanceType)
+}
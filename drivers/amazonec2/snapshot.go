@@ -0,0 +1,279 @@
+package amazonec2
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	snapshotMachineTag = "docker-machine-name"
+	snapshotNameTag    = "docker-machine-snapshot"
+)
+
+// CreateSnapshot takes an EBS snapshot of the root volume of the instance.
+func (d *Driver) CreateSnapshot(name string) error {
+	inst, err := d.getInstance()
+	if err != nil {
+		return err
+	}
+
+	volumeID, err := rootVolumeID(inst)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := d.getClient().CreateSnapshot(&ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(volumeID),
+		Description: aws.String(fmt.Sprintf("Snapshot %s of machine %s", name, d.MachineName)),
+	})
+	if err != nil {
+		return err
+	}
+
+	// snapshots are only found through their tags, an untagged one would be
+	// left behind unnoticed
+	if _, err := d.getClient().CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{snapshot.SnapshotId},
+		Tags: []*ec2.Tag{
+			{Key: aws.String(snapshotMachineTag), Value: aws.String(d.MachineName)},
+			{Key: aws.String(snapshotNameTag), Value: aws.String(name)},
+		},
+	}); err != nil {
+		if _, deleteErr := d.getClient().DeleteSnapshot(&ec2.DeleteSnapshotInput{
+			SnapshotId: snapshot.SnapshotId,
+		}); deleteErr != nil {
+			log.Warnf("Unable to delete the untagged snapshot %s, delete it by hand: %s", *snapshot.SnapshotId, deleteErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ListSnapshots returns the EBS snapshots tagged with the machine name,
+// oldest first.
+func (d *Driver) ListSnapshots() ([]drivers.Snapshot, error) {
+	ec2Snapshots, err := d.describeSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []drivers.Snapshot{}
+	for _, s := range ec2Snapshots {
+		snapshot := drivers.Snapshot{
+			Name: tagValue(s.Tags, snapshotNameTag),
+		}
+		if s.StartTime != nil {
+			snapshot.Created = *s.StartTime
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot replaces the root volume of the stopped instance with a
+// volume created from the snapshot. The replaced volume is deleted.
+func (d *Driver) RestoreSnapshot(name string) error {
+	snapshotID, err := d.snapshotID(name)
+	if err != nil {
+		return err
+	}
+
+	inst, err := d.getInstance()
+	if err != nil {
+		return err
+	}
+
+	oldVolumeID, err := rootVolumeID(inst)
+	if err != nil {
+		return err
+	}
+
+	if err := d.getClient().WaitUntilInstanceStopped(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{&d.InstanceId},
+	}); err != nil {
+		return err
+	}
+
+	log.Debugf("Creating a volume from snapshot %s", snapshotID)
+	volume, err := d.getClient().CreateVolume(&ec2.CreateVolumeInput{
+		AvailabilityZone: inst.Placement.AvailabilityZone,
+		SnapshotId:       aws.String(snapshotID),
+		VolumeType:       aws.String(d.VolumeType),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := d.getClient().WaitUntilVolumeAvailable(&ec2.DescribeVolumesInput{
+		VolumeIds: []*string{volume.VolumeId},
+	}); err != nil {
+		return d.rollbackRestore(inst, oldVolumeID, *volume.VolumeId, false, false, err)
+	}
+
+	log.Debugf("Replacing root volume %s with %s", oldVolumeID, *volume.VolumeId)
+	if _, err := d.getClient().DetachVolume(&ec2.DetachVolumeInput{
+		VolumeId: aws.String(oldVolumeID),
+	}); err != nil {
+		return d.rollbackRestore(inst, oldVolumeID, *volume.VolumeId, false, false, err)
+	}
+
+	if err := d.getClient().WaitUntilVolumeAvailable(&ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(oldVolumeID)},
+	}); err != nil {
+		return d.rollbackRestore(inst, oldVolumeID, *volume.VolumeId, true, false, err)
+	}
+
+	if _, err := d.getClient().AttachVolume(&ec2.AttachVolumeInput{
+		Device:     inst.RootDeviceName,
+		InstanceId: aws.String(d.InstanceId),
+		VolumeId:   volume.VolumeId,
+	}); err != nil {
+		return d.rollbackRestore(inst, oldVolumeID, *volume.VolumeId, true, false, err)
+	}
+
+	// volumes attached after launch survive the instance by default
+	if _, err := d.getClient().ModifyInstanceAttribute(&ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(d.InstanceId),
+		BlockDeviceMappings: []*ec2.InstanceBlockDeviceMappingSpecification{
+			{
+				DeviceName: inst.RootDeviceName,
+				Ebs: &ec2.EbsInstanceBlockDeviceSpecification{
+					DeleteOnTermination: aws.Bool(true),
+					VolumeId:            volume.VolumeId,
+				},
+			},
+		},
+	}); err != nil {
+		return d.rollbackRestore(inst, oldVolumeID, *volume.VolumeId, true, true, err)
+	}
+
+	// the snapshot is restored, a volume left behind only costs money
+	if _, err := d.getClient().DeleteVolume(&ec2.DeleteVolumeInput{
+		VolumeId: aws.String(oldVolumeID),
+	}); err != nil {
+		log.Warnf("Unable to delete the replaced root volume %s, delete it by hand: %s", oldVolumeID, err)
+	}
+
+	return nil
+}
+
+// rollbackRestore puts the old root volume of the instance back in place of
+// the new one when restoring a snapshot failed, depending on whether the old
+// one was detached and the new one attached already, and deletes the new
+// volume. The rollback is best effort, cause is returned whatever happens.
+func (d *Driver) rollbackRestore(inst *ec2.Instance, oldVolumeID, newVolumeID string, detached, attached bool, cause error) error {
+	log.Warnf("Unable to restore the snapshot, putting root volume %s back: %s", oldVolumeID, cause)
+
+	if attached {
+		if _, err := d.getClient().DetachVolume(&ec2.DetachVolumeInput{
+			VolumeId: aws.String(newVolumeID),
+		}); err != nil {
+			log.Warnf("Unable to detach volume %s: %s", newVolumeID, err)
+			return cause
+		}
+
+		if err := d.getClient().WaitUntilVolumeAvailable(&ec2.DescribeVolumesInput{
+			VolumeIds: []*string{aws.String(newVolumeID)},
+		}); err != nil {
+			log.Warnf("Unable to detach volume %s: %s", newVolumeID, err)
+			return cause
+		}
+	}
+
+	if detached {
+		if _, err := d.getClient().AttachVolume(&ec2.AttachVolumeInput{
+			Device:     inst.RootDeviceName,
+			InstanceId: aws.String(d.InstanceId),
+			VolumeId:   aws.String(oldVolumeID),
+		}); err != nil {
+			log.Warnf("Unable to reattach root volume %s, attach it by hand as %s: %s", oldVolumeID, *inst.RootDeviceName, err)
+			return cause
+		}
+	}
+
+	if _, err := d.getClient().DeleteVolume(&ec2.DeleteVolumeInput{
+		VolumeId: aws.String(newVolumeID),
+	}); err != nil {
+		log.Warnf("Unable to delete volume %s: %s", newVolumeID, err)
+	}
+
+	return cause
+}
+
+// RemoveSnapshot deletes the EBS snapshot.
+func (d *Driver) RemoveSnapshot(name string) error {
+	snapshotID, err := d.snapshotID(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.getClient().DeleteSnapshot(&ec2.DeleteSnapshotInput{
+		SnapshotId: aws.String(snapshotID),
+	})
+	return err
+}
+
+func (d *Driver) describeSnapshots() ([]*ec2.Snapshot, error) {
+	output, err := d.getClient().DescribeSnapshots(&ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{aws.String("self")},
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:" + snapshotMachineTag),
+				Values: []*string{aws.String(d.MachineName)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Snapshots, nil
+}
+
+func (d *Driver) snapshotID(name string) (string, error) {
+	snapshots, err := d.describeSnapshots()
+	if err != nil {
+		return "", err
+	}
+
+	for _, s := range snapshots {
+		if tagValue(s.Tags, snapshotNameTag) == name {
+			return *s.SnapshotId, nil
+		}
+	}
+
+	return "", fmt.Errorf("Snapshot %s not found", name)
+}
+
+func rootVolumeID(inst *ec2.Instance) (string, error) {
+	for _, mapping := range inst.BlockDeviceMappings {
+		if mapping.Ebs == nil || mapping.DeviceName == nil || inst.RootDeviceName == nil {
+			continue
+		}
+		if *mapping.DeviceName == *inst.RootDeviceName {
+			return *mapping.Ebs.VolumeId, nil
+		}
+	}
+
+	return "", fmt.Errorf("No EBS root volume for instance %s", *inst.InstanceId)
+}
+
+func tagValue(tags []*ec2.Tag, key string) string {
+	for _, tag := range tags {
+		if *tag.Key == key {
+			return *tag.Value
+		}
+	}
+	return ""
+}
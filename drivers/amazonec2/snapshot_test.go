@@ -0,0 +1,125 @@
+package amazonec2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRestoreRecorder returns a client for which restoring the snapshot named
+// snap of instance i-1234 replaces root volume vol-old with vol-new.
+func newRestoreRecorder() *fakeEC2SnapshotTestRecorder {
+	recorder := &fakeEC2SnapshotTestRecorder{}
+
+	recorder.On("DescribeSnapshots", mock.Anything).Return(&ec2.DescribeSnapshotsOutput{
+		Snapshots: []*ec2.Snapshot{{
+			SnapshotId: aws.String("snap-1234"),
+			Tags:       []*ec2.Tag{{Key: aws.String(snapshotNameTag), Value: aws.String("snap")}},
+		}},
+	}, nil)
+	recorder.On("DescribeInstances", mock.Anything).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{
+			Instances: []*ec2.Instance{{
+				InstanceId:     aws.String("i-1234"),
+				RootDeviceName: aws.String("/dev/sda1"),
+				Placement:      &ec2.Placement{AvailabilityZone: aws.String("us-east-1a")},
+				BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{{
+					DeviceName: aws.String("/dev/sda1"),
+					Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-old")},
+				}},
+			}},
+		}},
+	}, nil)
+	recorder.On("WaitUntilInstanceStopped", mock.Anything).Return(nil)
+	recorder.On("CreateVolume", mock.Anything).Return(&ec2.Volume{VolumeId: aws.String("vol-new")}, nil)
+	recorder.On("WaitUntilVolumeAvailable", mock.Anything).Return(nil)
+
+	return recorder
+}
+
+func newRestoreDriver(recorder *fakeEC2SnapshotTestRecorder) *Driver {
+	driver := NewCustomTestDriver(recorder)
+	driver.InstanceId = "i-1234"
+	return driver
+}
+
+func attachVolumeInput(volumeID string) *ec2.AttachVolumeInput {
+	return &ec2.AttachVolumeInput{
+		Device:     aws.String("/dev/sda1"),
+		InstanceId: aws.String("i-1234"),
+		VolumeId:   aws.String(volumeID),
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	recorder := newRestoreRecorder()
+	recorder.On("DetachVolume", &ec2.DetachVolumeInput{VolumeId: aws.String("vol-old")}).Return(nil)
+	recorder.On("AttachVolume", attachVolumeInput("vol-new")).Return(nil)
+	recorder.On("ModifyInstanceAttribute", mock.Anything).Return(nil)
+	recorder.On("DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-old")}).Return(nil)
+
+	err := newRestoreDriver(recorder).RestoreSnapshot("snap")
+
+	assert.NoError(t, err)
+	recorder.AssertExpectations(t)
+	recorder.AssertNotCalled(t, "DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-new")})
+}
+
+func TestRestoreSnapshotAttachFailure(t *testing.T) {
+	recorder := newRestoreRecorder()
+	recorder.On("DetachVolume", &ec2.DetachVolumeInput{VolumeId: aws.String("vol-old")}).Return(nil)
+	recorder.On("AttachVolume", attachVolumeInput("vol-new")).Return(errors.New("attach failed"))
+	recorder.On("AttachVolume", attachVolumeInput("vol-old")).Return(nil)
+	recorder.On("DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-new")}).Return(nil)
+
+	err := newRestoreDriver(recorder).RestoreSnapshot("snap")
+
+	assert.EqualError(t, err, "attach failed")
+	recorder.AssertExpectations(t)
+	recorder.AssertNotCalled(t, "ModifyInstanceAttribute", mock.Anything)
+	recorder.AssertNotCalled(t, "DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-old")})
+}
+
+func TestRestoreSnapshotModifyFailure(t *testing.T) {
+	recorder := newRestoreRecorder()
+	recorder.On("DetachVolume", &ec2.DetachVolumeInput{VolumeId: aws.String("vol-old")}).Return(nil)
+	recorder.On("AttachVolume", attachVolumeInput("vol-new")).Return(nil)
+	recorder.On("ModifyInstanceAttribute", mock.Anything).Return(errors.New("modify failed"))
+	recorder.On("DetachVolume", &ec2.DetachVolumeInput{VolumeId: aws.String("vol-new")}).Return(nil)
+	recorder.On("AttachVolume", attachVolumeInput("vol-old")).Return(nil)
+	recorder.On("DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-new")}).Return(nil)
+
+	err := newRestoreDriver(recorder).RestoreSnapshot("snap")
+
+	assert.EqualError(t, err, "modify failed")
+	recorder.AssertExpectations(t)
+	recorder.AssertNotCalled(t, "DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-old")})
+}
+
+func TestRestoreSnapshotDetachFailure(t *testing.T) {
+	recorder := newRestoreRecorder()
+	recorder.On("DetachVolume", &ec2.DetachVolumeInput{VolumeId: aws.String("vol-old")}).Return(errors.New("detach failed"))
+	recorder.On("DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-new")}).Return(nil)
+
+	err := newRestoreDriver(recorder).RestoreSnapshot("snap")
+
+	assert.EqualError(t, err, "detach failed")
+	recorder.AssertExpectations(t)
+	recorder.AssertNotCalled(t, "AttachVolume", mock.Anything)
+}
+
+func TestCreateSnapshotTagFailure(t *testing.T) {
+	recorder := newRestoreRecorder()
+	recorder.On("CreateSnapshot", mock.Anything).Return(&ec2.Snapshot{SnapshotId: aws.String("snap-5678")}, nil)
+	recorder.On("CreateTags", mock.Anything).Return(errors.New("tag failed"))
+	recorder.On("DeleteSnapshot", &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-5678")}).Return(nil)
+
+	err := newRestoreDriver(recorder).CreateSnapshot("other")
+
+	assert.EqualError(t, err, "tag failed")
+	recorder.AssertCalled(t, "DeleteSnapshot", &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-5678")})
+}
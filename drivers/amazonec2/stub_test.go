@@ -147,3 +147,66 @@ func NewCustomTestDriver(ec2Client Ec2Client) *Driver {
 	}
 	return driver
 }
+
+type fakeEC2SnapshotTestRecorder struct {
+	*fakeEC2
+	mock.Mock
+}
+
+func (f *fakeEC2SnapshotTestRecorder) DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	result := f.Called(input)
+	return result.Get(0).(*ec2.DescribeSnapshotsOutput), result.Error(1)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	result := f.Called(input)
+	return result.Get(0).(*ec2.DescribeInstancesOutput), result.Error(1)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) WaitUntilInstanceStopped(input *ec2.DescribeInstancesInput) error {
+	return f.Called(input).Error(0)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) CreateVolume(input *ec2.CreateVolumeInput) (*ec2.Volume, error) {
+	result := f.Called(input)
+	return result.Get(0).(*ec2.Volume), result.Error(1)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) WaitUntilVolumeAvailable(input *ec2.DescribeVolumesInput) error {
+	return f.Called(input).Error(0)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) AttachVolume(input *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+	result := f.Called(input)
+	return &ec2.VolumeAttachment{}, result.Error(0)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) DetachVolume(input *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error) {
+	result := f.Called(input)
+	return &ec2.VolumeAttachment{}, result.Error(0)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) ModifyInstanceAttribute(input *ec2.ModifyInstanceAttributeInput) (*ec2.ModifyInstanceAttributeOutput, error) {
+	result := f.Called(input)
+	return &ec2.ModifyInstanceAttributeOutput{}, result.Error(0)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
+	result := f.Called(input)
+	return &ec2.DeleteVolumeOutput{}, result.Error(0)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) CreateSnapshot(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	result := f.Called(input)
+	return result.Get(0).(*ec2.Snapshot), result.Error(1)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) CreateTags(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+	result := f.Called(input)
+	return &ec2.CreateTagsOutput{}, result.Error(0)
+}
+
+func (f *fakeEC2SnapshotTestRecorder) DeleteSnapshot(input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	result := f.Called(input)
+	return &ec2.DeleteSnapshotOutput{}, result.Error(0)
+}
@@ -52,9 +52,11 @@ const (
 func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 	return []mcnflag.Flag{
 		mcnflag.StringFlag{
-			EnvVar: "DIGITALOCEAN_ACCESS_TOKEN",
-			Name:   "digitalocean-access-token",
-			Usage:  "Digital Ocean access token",
+			EnvVar:   "DIGITALOCEAN_ACCESS_TOKEN",
+			Name:     "digitalocean-access-token",
+			Usage:    "Digital Ocean access token",
+			Secret:   true,
+			Required: true,
 		},
 		mcnflag.StringFlag{
 			EnvVar: "DIGITALOCEAN_SSH_USER",
@@ -150,6 +152,16 @@ func (d *Driver) DriverName() string {
 	return "digitalocean"
 }
 
+// ConcurrencyPolicy retries the calls over the rate limit of the DigitalOcean
+// API.
+func (d *Driver) ConcurrencyPolicy() drivers.ConcurrencyPolicy {
+	return drivers.ConcurrencyPolicy{
+		MaxRetries:       5,
+		RetryBackoff:     2 * time.Second,
+		ThrottlingErrors: []string{"429 Too many requests", "429 Too Many Requests"},
+	}
+}
+
 func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
 	d.AccessToken = flags.String("digitalocean-access-token")
 	d.Image = flags.String("digitalocean-image")
@@ -0,0 +1,76 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+)
+
+var errResizeSize = errors.New("A DigitalOcean size needs both CPUs and memory, in multiples of 1024 MB")
+
+// resizeSlug returns the size the droplet is resized to: the one given, or
+// the standard size with the CPUs and memory given.
+func resizeSlug(opts drivers.ResizeOptions) (string, error) {
+	if opts.MachineType != "" || (opts.CPUs == 0 && opts.Memory == 0) {
+		return opts.MachineType, nil
+	}
+
+	if opts.CPUs == 0 || opts.Memory == 0 || opts.Memory%1024 != 0 {
+		return "", errResizeSize
+	}
+
+	return fmt.Sprintf("s-%dvcpu-%dgb", opts.CPUs, opts.Memory/1024), nil
+}
+
+// Resize changes the size of the stopped droplet. The disk of a droplet
+// can only grow to the disk of its size, which can't be undone: it's grown
+// when a disk size is given.
+func (d *Driver) Resize(opts drivers.ResizeOptions) error {
+	size, err := resizeSlug(opts)
+	if err != nil {
+		return err
+	}
+	if size == "" {
+		size = d.Size
+	}
+
+	resizeDisk := opts.DiskSize > 0
+	if resizeDisk {
+		log.Infof("The disk of the droplet grows to the disk of size %s, the droplet can't be resized to a smaller size anymore", size)
+	}
+
+	action, _, err := d.getClient().DropletActions.Resize(context.TODO(), d.DropletID, size, resizeDisk)
+	if err != nil {
+		return err
+	}
+
+	if err := d.waitForAction(action); err != nil {
+		return err
+	}
+
+	d.Size = size
+
+	return nil
+}
+
+func (d *Driver) waitForAction(action *godo.Action) error {
+	for action.Status == godo.ActionInProgress {
+		time.Sleep(1 * time.Second)
+
+		var err error
+		if action, _, err = d.getClient().DropletActions.Get(context.TODO(), d.DropletID, action.ID); err != nil {
+			return err
+		}
+	}
+
+	if action.Status != godo.ActionCompleted {
+		return fmt.Errorf("Action %s of droplet %d ended with status %s", action.Type, d.DropletID, action.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package digitalocean
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeSlug(t *testing.T) {
+	cases := []struct {
+		opts drivers.ResizeOptions
+		slug string
+		err  error
+	}{
+		{drivers.ResizeOptions{MachineType: "c-2"}, "c-2", nil},
+		{drivers.ResizeOptions{CPUs: 2, Memory: 4096}, "s-2vcpu-4gb", nil},
+		{drivers.ResizeOptions{CPUs: 2, Memory: 1500}, "", errResizeSize},
+		{drivers.ResizeOptions{DiskSize: 51200}, "", nil},
+	}
+
+	for _, c := range cases {
+		slug, err := resizeSlug(c.opts)
+
+		assert.Equal(t, c.slug, slug)
+		assert.Equal(t, c.err, err)
+	}
+}
@@ -75,6 +75,7 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			EnvVar: "EXOSCALE_API_SECRET",
 			Name:   "exoscale-api-secret-key",
 			Usage:  "exoscale API secret key",
+			Secret: true,
 		},
 		mcnflag.StringFlag{
 			EnvVar: "EXOSCALE_INSTANCE_PROFILE",
@@ -34,11 +34,13 @@ type ComputeUtil struct {
 	useInternalIP     bool
 	useInternalIPOnly bool
 	service           *raw.Service
+	client            *http.Client
 	zoneURL           string
 	globalURL         string
 	SwarmMaster       bool
 	SwarmHost         string
 	openPorts         []string
+	bootDisk          string
 }
 
 const (
@@ -73,15 +75,22 @@ func newComputeUtil(driver *Driver) (*ComputeUtil, error) {
 		useInternalIP:     driver.UseInternalIP,
 		useInternalIPOnly: driver.UseInternalIPOnly,
 		service:           service,
+		client:            client,
 		zoneURL:           apiURL + driver.Project + "/zones/" + driver.Zone,
 		globalURL:         apiURL + driver.Project + "/global",
 		SwarmMaster:       driver.SwarmMaster,
 		SwarmHost:         driver.SwarmHost,
 		openPorts:         driver.OpenPorts,
+		bootDisk:          driver.DiskName,
 	}, nil
 }
 
+// diskName returns the name of the persistent disk, which changes once a
+// snapshot is restored.
 func (c *ComputeUtil) diskName() string {
+	if c.bootDisk != "" {
+		return c.bootDisk
+	}
 	return c.instanceName + "-disk"
 }
 
@@ -101,8 +110,13 @@ func (c *ComputeUtil) deleteDisk() error {
 		return nil
 	}
 
+	return c.deleteDiskNamed(c.diskName())
+}
+
+// deleteDiskNamed deletes the disk name.
+func (c *ComputeUtil) deleteDiskNamed(name string) error {
 	log.Infof("Deleting disk.")
-	op, err := c.service.Disks.Delete(c.project, c.zone, c.diskName()).Do()
+	op, err := c.service.Disks.Delete(c.project, c.zone, name).Do()
 	if err != nil {
 		return err
 	}
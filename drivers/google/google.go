@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/log"
@@ -32,6 +33,7 @@ type Driver struct {
 	Tags              string
 	UseExisting       bool
 	OpenPorts         []string
+	DiskName          string
 }
 
 const (
@@ -184,6 +186,15 @@ func (d *Driver) DriverName() string {
 	return "google"
 }
 
+// ConcurrencyPolicy retries the calls over the rate limits of the GCE API.
+func (d *Driver) ConcurrencyPolicy() drivers.ConcurrencyPolicy {
+	return drivers.ConcurrencyPolicy{
+		MaxRetries:       5,
+		RetryBackoff:     2 * time.Second,
+		ThrottlingErrors: []string{"rateLimitExceeded", "Error 429"},
+	}
+}
+
 // SetConfigFromFlags initializes the driver based on the command line flags.
 func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
 	d.Project = flags.String("google-project")
@@ -327,6 +338,8 @@ func (d *Driver) GetState() (state.State, error) {
 		return state.Running, nil
 	case "STOPPING", "STOPPED", "TERMINATED":
 		return state.Stopped, nil
+	case "SUSPENDING", "SUSPENDED":
+		return state.Saved, nil
 	}
 	return state.None, nil
 }
@@ -0,0 +1,90 @@
+package google
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	raw "google.golang.org/api/compute/v1"
+)
+
+var (
+	errResizeCustomMachineType = errors.New("A custom GCE machine type needs both CPUs and memory")
+	errDiskShrink              = errors.New("The disk of a GCE instance can't shrink")
+)
+
+// resizeMachineType returns the machine type the instance is resized to: the
+// one given, or a custom machine type with the CPUs and memory given.
+func resizeMachineType(opts drivers.ResizeOptions) (string, error) {
+	if opts.MachineType != "" || (opts.CPUs == 0 && opts.Memory == 0) {
+		return opts.MachineType, nil
+	}
+
+	if opts.CPUs == 0 || opts.Memory == 0 {
+		return "", errResizeCustomMachineType
+	}
+
+	return fmt.Sprintf("custom-%d-%d", opts.CPUs, opts.Memory), nil
+}
+
+// setMachineType changes the machine type of the stopped instance.
+func (c *ComputeUtil) setMachineType(machineType string) error {
+	op, err := c.service.Instances.SetMachineType(c.project, c.zone, c.instanceName, &raw.InstancesSetMachineTypeRequest{
+		MachineType: c.zoneURL + "/machineTypes/" + machineType,
+	}).Do()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Waiting for machine type to change.")
+	return c.waitForRegionalOp(op.Name)
+}
+
+// resizeDisk grows the persistent disk.
+func (c *ComputeUtil) resizeDisk(sizeGb int) error {
+	op, err := c.service.Disks.Resize(c.project, c.zone, c.diskName(), &raw.DisksResizeRequest{
+		SizeGb: int64(sizeGb),
+	}).Do()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Waiting for disk to resize.")
+	return c.waitForRegionalOp(op.Name)
+}
+
+// Resize changes the machine type and the disk size of the stopped instance.
+func (d *Driver) Resize(opts drivers.ResizeOptions) error {
+	machineType, err := resizeMachineType(opts)
+	if err != nil {
+		return err
+	}
+
+	// the disk size of GCE instances is set in GB
+	diskSize := (opts.DiskSize + 1023) / 1024
+	if diskSize > 0 && diskSize < d.DiskSize {
+		return errDiskShrink
+	}
+
+	c, err := newComputeUtil(d)
+	if err != nil {
+		return err
+	}
+
+	if machineType != "" {
+		if err := c.setMachineType(machineType); err != nil {
+			return err
+		}
+		d.MachineType = machineType
+	}
+
+	if diskSize > d.DiskSize {
+		if err := c.resizeDisk(diskSize); err != nil {
+			return err
+		}
+		d.DiskSize = diskSize
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package google
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeMachineType(t *testing.T) {
+	cases := []struct {
+		opts        drivers.ResizeOptions
+		machineType string
+		err         error
+	}{
+		{drivers.ResizeOptions{MachineType: "n1-standard-2"}, "n1-standard-2", nil},
+		{drivers.ResizeOptions{CPUs: 2, Memory: 4096}, "custom-2-4096", nil},
+		{drivers.ResizeOptions{DiskSize: 20480}, "", nil},
+		{drivers.ResizeOptions{CPUs: 2}, "", errResizeCustomMachineType},
+	}
+
+	for _, c := range cases {
+		machineType, err := resizeMachineType(c.opts)
+
+		assert.Equal(t, c.machineType, machineType)
+		assert.Equal(t, c.err, err)
+	}
+}
@@ -0,0 +1,246 @@
+package google
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"golang.org/x/net/context"
+	raw "google.golang.org/api/compute/v1"
+)
+
+const snapshotMachineLabel = "docker-machine"
+
+// snapshotName returns the GCE name of the snapshot name of the instance, as
+// the names of snapshots are unique in a project.
+func (c *ComputeUtil) snapshotName(name string) string {
+	return c.instanceName + "-" + name
+}
+
+// createSnapshot takes a snapshot of the persistent disk.
+func (c *ComputeUtil) createSnapshot(name string) error {
+	op, err := c.service.Disks.CreateSnapshot(c.project, c.zone, c.diskName(), &raw.Snapshot{
+		Name:        c.snapshotName(name),
+		Description: fmt.Sprintf("Snapshot %s of machine %s", name, c.instanceName),
+		Labels: map[string]string{
+			snapshotMachineLabel: c.instanceName,
+		},
+	}).Do()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Waiting for snapshot to be taken.")
+	return c.waitForRegionalOp(op.Name)
+}
+
+// snapshots returns the snapshots of the persistent disk.
+func (c *ComputeUtil) snapshots() ([]*raw.Snapshot, error) {
+	snapshots := []*raw.Snapshot{}
+
+	err := c.service.Snapshots.List(c.project).
+		Filter(fmt.Sprintf("name eq %s-.*", c.instanceName)).
+		Pages(context.Background(), func(list *raw.SnapshotList) error {
+			for _, s := range list.Items {
+				if s.Labels[snapshotMachineLabel] == c.instanceName {
+					snapshots = append(snapshots, s)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// restoreSnapshot replaces the persistent disk of the stopped instance with a
+// disk created from the snapshot, and returns the name of the new disk. The
+// replaced disk is only deleted once the new one is attached, it is attached
+// back when the new one can't be.
+func (c *ComputeUtil) restoreSnapshot(name string) (string, error) {
+	snapshot, err := c.service.Snapshots.Get(c.project, c.snapshotName(name)).Do()
+	if err != nil {
+		return "", unwrapGoogleError(err)
+	}
+
+	instance, err := c.instance()
+	if err != nil {
+		return "", err
+	}
+
+	var boot *raw.AttachedDisk
+	for _, disk := range instance.Disks {
+		if disk.Boot {
+			boot = disk
+		}
+	}
+	if boot == nil {
+		return "", fmt.Errorf("No boot disk for instance %s", c.instanceName)
+	}
+
+	restoredDisk := fmt.Sprintf("%s-disk-%d", c.instanceName, time.Now().Unix())
+
+	log.Infof("Creating disk from snapshot %s.", snapshot.Name)
+	op, err := c.service.Disks.Insert(c.project, c.zone, &raw.Disk{
+		Name:           restoredDisk,
+		SourceSnapshot: snapshot.SelfLink,
+		Type:           c.diskType(),
+	}).Do()
+	if err != nil {
+		return "", err
+	}
+	if err := c.waitForRegionalOp(op.Name); err != nil {
+		return "", c.rollbackRestore(boot, restoredDisk, false, err)
+	}
+
+	log.Infof("Detaching disk.")
+	op, err = c.service.Instances.DetachDisk(c.project, c.zone, c.instanceName, boot.DeviceName).Do()
+	if err != nil {
+		return "", c.rollbackRestore(boot, restoredDisk, false, err)
+	}
+	if err := c.waitForRegionalOp(op.Name); err != nil {
+		return "", c.rollbackRestore(boot, restoredDisk, false, err)
+	}
+
+	log.Infof("Attaching disk.")
+	op, err = c.service.Instances.AttachDisk(c.project, c.zone, c.instanceName, c.bootAttachment(boot.DeviceName, restoredDisk)).Do()
+	if err != nil {
+		return "", c.rollbackRestore(boot, restoredDisk, true, err)
+	}
+	if err := c.waitForRegionalOp(op.Name); err != nil {
+		return "", c.rollbackRestore(boot, restoredDisk, true, err)
+	}
+
+	// the snapshot is restored, a disk left behind only costs money
+	if err := c.deleteDiskNamed(c.diskName()); err != nil {
+		log.Warnf("Unable to delete the replaced disk %s, delete it by hand: %s", c.diskName(), err)
+	}
+
+	return restoredDisk, nil
+}
+
+// bootAttachment returns the attachment of the disk as the boot disk.
+func (c *ComputeUtil) bootAttachment(deviceName, disk string) *raw.AttachedDisk {
+	return &raw.AttachedDisk{
+		Boot:       true,
+		AutoDelete: true,
+		DeviceName: deviceName,
+		Type:       "PERSISTENT",
+		Mode:       "READ_WRITE",
+		Source:     c.zoneURL + "/disks/" + disk,
+	}
+}
+
+// rollbackRestore attaches the original boot disk back when restoring a
+// snapshot failed after detaching it, and deletes the restored disk. The
+// rollback is best effort, cause is returned whatever happens.
+func (c *ComputeUtil) rollbackRestore(boot *raw.AttachedDisk, restoredDisk string, detached bool, cause error) error {
+	log.Warnf("Unable to restore the snapshot, putting disk %s back: %s", c.diskName(), cause)
+
+	if detached {
+		// the restored disk may have been attached before the failure
+		if op, err := c.service.Instances.DetachDisk(c.project, c.zone, c.instanceName, boot.DeviceName).Do(); err == nil {
+			c.waitForRegionalOp(op.Name)
+		}
+
+		op, err := c.service.Instances.AttachDisk(c.project, c.zone, c.instanceName, c.bootAttachment(boot.DeviceName, c.diskName())).Do()
+		if err == nil {
+			err = c.waitForRegionalOp(op.Name)
+		}
+		if err != nil {
+			log.Warnf("Unable to reattach disk %s, attach it by hand as the boot disk: %s", c.diskName(), err)
+			return cause
+		}
+	}
+
+	if err := c.deleteDiskNamed(restoredDisk); err != nil {
+		log.Warnf("Unable to delete disk %s: %s", restoredDisk, err)
+	}
+
+	return cause
+}
+
+// deleteSnapshot deletes the snapshot.
+func (c *ComputeUtil) deleteSnapshot(name string) error {
+	op, err := c.service.Snapshots.Delete(c.project, c.snapshotName(name)).Do()
+	if err != nil {
+		return unwrapGoogleError(err)
+	}
+
+	log.Infof("Waiting for snapshot to delete.")
+	return c.waitForGlobalOp(op.Name)
+}
+
+// CreateSnapshot takes a snapshot of the persistent disk of the instance.
+func (d *Driver) CreateSnapshot(name string) error {
+	c, err := newComputeUtil(d)
+	if err != nil {
+		return err
+	}
+
+	return c.createSnapshot(name)
+}
+
+// ListSnapshots returns the snapshots of the instance, oldest first.
+func (d *Driver) ListSnapshots() ([]drivers.Snapshot, error) {
+	c, err := newComputeUtil(d)
+	if err != nil {
+		return nil, err
+	}
+
+	rawSnapshots, err := c.snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []drivers.Snapshot{}
+	for _, s := range rawSnapshots {
+		created, err := time.Parse(time.RFC3339, s.CreationTimestamp)
+		if err != nil {
+			log.Debugf("Invalid creation time of snapshot %s: %s", s.Name, err)
+		}
+
+		snapshots = append(snapshots, drivers.Snapshot{
+			Name:    strings.TrimPrefix(s.Name, c.snapshotName("")),
+			Created: created,
+		})
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot brings the persistent disk of the stopped instance back to
+// the snapshot.
+func (d *Driver) RestoreSnapshot(name string) error {
+	c, err := newComputeUtil(d)
+	if err != nil {
+		return err
+	}
+
+	disk, err := c.restoreSnapshot(name)
+	if err != nil {
+		return err
+	}
+
+	d.DiskName = disk
+	return nil
+}
+
+// RemoveSnapshot deletes a snapshot of the instance.
+func (d *Driver) RemoveSnapshot(name string) error {
+	c, err := newComputeUtil(d)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteSnapshot(name)
+}
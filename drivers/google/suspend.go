@@ -0,0 +1,72 @@
+package google
+
+import (
+	"encoding/json"
+
+	"github.com/docker/machine/libmachine/log"
+	raw "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// instanceAction posts an action on the instance to the API directly, the
+// vendored API predates suspend and resume.
+func (c *ComputeUtil) instanceAction(action string) error {
+	resp, err := c.client.Post(c.zoneURL+"/instances/"+c.instanceName+"/"+action, "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return err
+	}
+
+	op := &raw.Operation{}
+	if err := json.NewDecoder(resp.Body).Decode(op); err != nil {
+		return err
+	}
+
+	return c.waitForRegionalOp(op.Name)
+}
+
+// suspendInstance suspends the instance.
+func (c *ComputeUtil) suspendInstance() error {
+	log.Infof("Waiting for instance to suspend.")
+	return c.instanceAction("suspend")
+}
+
+// resumeInstance resumes the suspended instance.
+func (c *ComputeUtil) resumeInstance() error {
+	log.Infof("Waiting for instance to resume.")
+	return c.instanceAction("resume")
+}
+
+// Suspend saves the memory of the instance and stops it.
+func (d *Driver) Suspend() error {
+	c, err := newComputeUtil(d)
+	if err != nil {
+		return err
+	}
+
+	if err := c.suspendInstance(); err != nil {
+		return err
+	}
+
+	d.IPAddress = ""
+	return nil
+}
+
+// Resume starts the suspended instance where it was suspended.
+func (d *Driver) Resume() error {
+	c, err := newComputeUtil(d)
+	if err != nil {
+		return err
+	}
+
+	if err := c.resumeInstance(); err != nil {
+		return err
+	}
+
+	d.IPAddress, err = d.GetIP()
+	return err
+}
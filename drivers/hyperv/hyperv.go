@@ -152,6 +152,10 @@ func (d *Driver) GetState() (state.State, error) {
 		return state.Running, nil
 	case "Off":
 		return state.Stopped, nil
+	case "Paused":
+		return state.Paused, nil
+	case "Saved":
+		return state.Saved, nil
 	default:
 		return state.None, nil
 	}
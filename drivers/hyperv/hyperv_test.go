@@ -69,3 +69,8 @@ func TestSetConfigFromCustomFlags(t *testing.T) {
 	assert.Equal(t, "docker", driver.GetSSHUsername())
 	assert.Equal(t, true, driver.DisableDynamicMemory)
 }
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, "'before update'", quote("before update"))
+	assert.Equal(t, "'it''s done'", quote("it's done"))
+}
@@ -0,0 +1,27 @@
+package hyperv
+
+// Pause freezes the running VM
+func (d *Driver) Pause() error {
+	return cmd("Hyper-V\\Suspend-VM", d.MachineName)
+}
+
+// Unpause lets the paused VM run again
+func (d *Driver) Unpause() error {
+	return cmd("Hyper-V\\Resume-VM", d.MachineName)
+}
+
+// Suspend saves the state of the running VM and stops it
+func (d *Driver) Suspend() error {
+	if err := cmd("Hyper-V\\Save-VM", d.MachineName); err != nil {
+		return err
+	}
+
+	d.IPAddress = ""
+
+	return nil
+}
+
+// Resume starts the saved VM where it was saved
+func (d *Driver) Resume() error {
+	return d.Start()
+}
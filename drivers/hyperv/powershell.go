@@ -105,8 +105,10 @@ func isWindowsAdministrator() (bool, error) {
 	return resp[0] == "True", nil
 }
 
+// quote returns text as a single-quoted PowerShell string, in which single
+// quotes are escaped by doubling them.
 func quote(text string) string {
-	return fmt.Sprintf("'%s'", text)
+	return fmt.Sprintf("'%s'", strings.Replace(text, "'", "''", -1))
 }
 
 func toMb(value int) string {
@@ -0,0 +1,65 @@
+package hyperv
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+)
+
+// CreateSnapshot takes a checkpoint of the VM
+func (d *Driver) CreateSnapshot(name string) error {
+	return cmd("Hyper-V\\Checkpoint-VM", "-Name", d.MachineName, "-SnapshotName", quote(name))
+}
+
+// ListSnapshots returns the checkpoints of the VM, oldest first
+func (d *Driver) ListSnapshots() ([]drivers.Snapshot, error) {
+	stdout, err := cmdOut("Hyper-V\\Get-VMSnapshot", "-VMName", d.MachineName,
+		"|", "Sort-Object", "CreationTime",
+		"|", "ForEach-Object", "{", "$_.CreationTime.ToUniversalTime().ToString('o')", "+", "' '", "+", "$_.Name", "}")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSnapshots(stdout)
+}
+
+// parseSnapshots parses lines holding the creation time of a checkpoint then
+// its name.
+func parseSnapshots(stdout string) ([]drivers.Snapshot, error) {
+	snapshots := []drivers.Snapshot{}
+
+	for _, line := range parseLines(stdout) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("Unexpected checkpoint: %q", line)
+		}
+
+		created, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, drivers.Snapshot{
+			Name:    fields[1],
+			Created: created,
+		})
+	}
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot applies a checkpoint to the stopped VM
+func (d *Driver) RestoreSnapshot(name string) error {
+	return cmd("Hyper-V\\Restore-VMSnapshot", "-VMName", d.MachineName, "-Name", quote(name), "-Confirm:$false")
+}
+
+// RemoveSnapshot deletes a checkpoint of the VM
+func (d *Driver) RemoveSnapshot(name string) error {
+	return cmd("Hyper-V\\Remove-VMSnapshot", "-VMName", d.MachineName, "-Name", quote(name))
+}
@@ -102,6 +102,7 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Name:   "openstack-password",
 			Usage:  "OpenStack password",
 			Value:  "",
+			Secret: true,
 		},
 		mcnflag.StringFlag{
 			EnvVar: "OS_TENANT_NAME",
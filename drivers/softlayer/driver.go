@@ -108,6 +108,7 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			EnvVar: "SOFTLAYER_API_KEY",
 			Name:   "softlayer-api-key",
 			Usage:  "softlayer user API key",
+			Secret: true,
 		},
 		mcnflag.StringFlag{
 			EnvVar: "SOFTLAYER_REGION",
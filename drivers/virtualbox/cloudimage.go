@@ -0,0 +1,77 @@
+package virtualbox
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	cloudImageFilename = "cloudimage"
+	seedISOFilename    = "seed.iso"
+	consoleLogFilename = "console.log"
+)
+
+var (
+	ErrCloudImageWithBoot2Docker = errors.New("A cloud image can't be used with a boot2docker ISO or VM")
+)
+
+// createCloudImageDisk makes the disk of the VM from the cloud image, and
+// the cloud-init seed giving it its hostname and the SSH key.
+func (d *Driver) createCloudImageDisk() error {
+	log.Infof("Creating SSH key...")
+	if err := d.sshKeyGenerator.Generate(d.GetSSHKeyPath()); err != nil {
+		return err
+	}
+
+	imagePath := d.ResolveStorePath(cloudImageFilename + cloudImageExt(d.CloudImage))
+	if err := d.cloudImageProvider.Download(d.CloudImage, imagePath); err != nil {
+		return err
+	}
+
+	log.Infof("Converting the cloud image...")
+	if cloudImageExt(d.CloudImage) == ".raw" {
+		if err := d.vbm("convertfromraw", imagePath, d.diskPath(), "--format", "VDI"); err != nil {
+			return err
+		}
+		if err := os.Remove(imagePath); err != nil {
+			return err
+		}
+	} else {
+		// VirtualBox reads qcow2, vmdk, vdi and vhd images
+		if err := d.vbm("clonemedium", "disk", imagePath, d.diskPath(), "--format", "VDI"); err != nil {
+			return err
+		}
+		if err := d.vbm("closemedium", "disk", imagePath, "--delete"); err != nil {
+			return err
+		}
+	}
+
+	// the partitions are grown by cloud-init on first boot
+	if err := d.vbm("modifymedium", "disk", d.diskPath(), "--resize", strconv.Itoa(d.DiskSize)); err != nil {
+		return err
+	}
+
+	log.Debugf("Creating cloud-init seed...")
+	return d.cloudImageProvider.MakeSeed(d.MachineName, d.GetSSHUsername(), d.publicSSHKeyPath(), d.seedISOPath())
+}
+
+// cloudImageExt returns the extension of the image in lower case. Raw images
+// have to be named .raw, .img is used for qcow2 images too.
+func cloudImageExt(imageURL string) string {
+	if u, err := url.Parse(imageURL); err == nil && len(u.Scheme) > 1 {
+		return strings.ToLower(path.Ext(u.Path))
+	}
+
+	return strings.ToLower(filepath.Ext(imageURL))
+}
+
+func (d *Driver) seedISOPath() string {
+	return d.ResolveStorePath(seedISOFilename)
+}
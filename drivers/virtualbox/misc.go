@@ -2,11 +2,16 @@ package virtualbox
 
 import (
 	"bufio"
+	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"time"
 
+	"github.com/docker/machine/libmachine/cloudinit"
+	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnutils"
 	"github.com/docker/machine/libmachine/ssh"
 )
@@ -31,6 +36,40 @@ func (u *b2dUtilsUpdater) UpdateISOCache(storePath, isoURL string) error {
 	return mcnutils.NewB2dUtils(storePath).UpdateISOCache(isoURL)
 }
 
+// CloudImageProvider describes the retrieval of the cloud images and the
+// making of the cloud-init seeds configuring them.
+type CloudImageProvider interface {
+	Download(imageURL, dest string) error
+	MakeSeed(hostname, user, publicSSHKeyPath, dest string) error
+}
+
+func NewCloudImageProvider() CloudImageProvider {
+	return &defaultCloudImageProvider{}
+}
+
+type defaultCloudImageProvider struct{}
+
+func (p *defaultCloudImageProvider) Download(imageURL, dest string) error {
+	log.Infof("Downloading %s...", imageURL)
+	return mcnutils.DownloadFile(filepath.Dir(dest), filepath.Base(dest), imageURL)
+}
+
+func (p *defaultCloudImageProvider) MakeSeed(hostname, user, publicSSHKeyPath, dest string) error {
+	publicKey, err := ioutil.ReadFile(publicSSHKeyPath)
+	if err != nil {
+		return err
+	}
+
+	seed := &cloudinit.NoCloudSeed{
+		InstanceID:        hostname,
+		Hostname:          hostname,
+		User:              user,
+		SSHAuthorizedKeys: []string{strings.TrimSpace(string(publicKey))},
+	}
+
+	return seed.WriteISO(dest)
+}
+
 // SSHKeyGenerator describes the generation of ssh keys.
 type SSHKeyGenerator interface {
 	Generate(path string) error
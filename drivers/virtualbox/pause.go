@@ -0,0 +1,27 @@
+package virtualbox
+
+// Pause freezes the running VM.
+func (d *Driver) Pause() error {
+	return d.vbm("controlvm", d.MachineName, "pause")
+}
+
+// Unpause lets the paused VM run again.
+func (d *Driver) Unpause() error {
+	return d.vbm("controlvm", d.MachineName, "resume")
+}
+
+// Suspend saves the state of the running VM to disk and stops it.
+func (d *Driver) Suspend() error {
+	if err := d.vbm("controlvm", d.MachineName, "savestate"); err != nil {
+		return err
+	}
+
+	d.IPAddress = ""
+
+	return nil
+}
+
+// Resume starts the saved VM, Start knows how to start a saved VM.
+func (d *Driver) Resume() error {
+	return d.Start()
+}
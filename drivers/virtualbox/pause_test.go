@@ -0,0 +1,30 @@
+package virtualbox
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPause(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{
+		args: "controlvm default pause",
+	}
+
+	assert.NoError(t, driver.Pause())
+	assert.True(t, drivers.HasCapability(driver, drivers.CapabilityPause))
+}
+
+func TestSuspend(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.IPAddress = "192.168.99.100"
+	driver.VBoxManager = &VBoxManagerMock{
+		args: "controlvm default savestate",
+	}
+
+	assert.NoError(t, driver.Suspend())
+	assert.Empty(t, driver.IPAddress)
+	assert.True(t, drivers.HasCapability(driver, drivers.CapabilitySuspend))
+}
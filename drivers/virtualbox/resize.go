@@ -0,0 +1,110 @@
+package virtualbox
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+)
+
+var (
+	ErrDiskShrink        = errors.New("The disk of a VirtualBox machine can't shrink")
+	ErrResizeMachineType = errors.New("VirtualBox machines are resized by CPUs and memory, not by machine type")
+	ErrResizeSnapshots   = errors.New("VirtualBox can't resize the disk of a VM with snapshots, remove them first")
+)
+
+// Resize changes the CPUs, the memory and the disk size of the stopped VM.
+func (d *Driver) Resize(opts drivers.ResizeOptions) error {
+	if opts.MachineType != "" {
+		return ErrResizeMachineType
+	}
+
+	if opts.DiskSize > 0 && opts.DiskSize < d.DiskSize {
+		return ErrDiskShrink
+	}
+
+	// the disk of a VM with snapshots is a chain of differencing images
+	if opts.DiskSize > d.DiskSize {
+		snapshots, err := d.ListSnapshots()
+		if err != nil {
+			return err
+		}
+		if len(snapshots) > 0 {
+			return ErrResizeSnapshots
+		}
+	}
+
+	args := []string{"modifyvm", d.MachineName}
+	if opts.CPUs > 0 {
+		args = append(args, "--cpus", strconv.Itoa(opts.CPUs))
+	}
+	if opts.Memory > 0 {
+		args = append(args, "--memory", strconv.Itoa(opts.Memory))
+	}
+
+	if len(args) > 2 {
+		if err := d.vbm(args...); err != nil {
+			return err
+		}
+
+		if opts.CPUs > 0 {
+			d.CPU = opts.CPUs
+		}
+		if opts.Memory > 0 {
+			d.Memory = opts.Memory
+		}
+	}
+
+	if opts.DiskSize > d.DiskSize {
+		if err := d.resizeDisk(opts.DiskSize); err != nil {
+			return err
+		}
+		d.DiskSize = opts.DiskSize
+	}
+
+	return nil
+}
+
+// resizeDisk grows the disk of the VM, the partitions on it are left as is.
+// VirtualBox can't resize VMDK disks, so the disk created with the VM is
+// converted to VDI first.
+func (d *Driver) resizeDisk(size int) error {
+	if d.diskPath() != d.vdiDiskPath() {
+		if err := d.convertDiskToVDI(); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Resizing the disk to %d MB...", size)
+	return d.vbm("modifymedium", "disk", d.diskPath(), "--resize", strconv.Itoa(size))
+}
+
+func (d *Driver) convertDiskToVDI() error {
+	vmdk := d.diskPath()
+	vdi := d.vdiDiskPath()
+
+	log.Infof("Converting the disk to VDI...")
+	if err := d.vbm("clonemedium", "disk", vmdk, vdi, "--format", "VDI"); err != nil {
+		return fmt.Errorf("Unable to convert the disk: %s", err)
+	}
+
+	if err := d.vbm("storageattach", d.MachineName,
+		"--storagectl", "SATA",
+		"--port", "1",
+		"--device", "0",
+		"--type", "hdd",
+		"--medium", vdi); err != nil {
+		if closeErr := d.vbm("closemedium", "disk", vdi, "--delete"); closeErr != nil {
+			log.Warnf("Unable to remove %s: %s", vdi, closeErr)
+		}
+		return err
+	}
+
+	return d.vbm("closemedium", "disk", vmdk, "--delete")
+}
+
+func (d *Driver) vdiDiskPath() string {
+	return d.ResolveStorePath("disk.vdi")
+}
@@ -0,0 +1,67 @@
+package virtualbox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeCPUsAndMemory(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{
+		args: "modifyvm default --cpus 4 --memory 4096",
+	}
+
+	err := driver.Resize(drivers.ResizeOptions{CPUs: 4, Memory: 4096})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, driver.CPU)
+	assert.Equal(t, 4096, driver.Memory)
+	assert.Equal(t, defaultDiskSize, driver.DiskSize)
+}
+
+func TestResizeDiskShrink(t *testing.T) {
+	driver := newTestDriver("default")
+
+	err := driver.Resize(drivers.ResizeOptions{DiskSize: defaultDiskSize / 2})
+
+	assert.Equal(t, ErrDiskShrink, err)
+}
+
+func TestResizeMachineType(t *testing.T) {
+	driver := newTestDriver("default")
+
+	err := driver.Resize(drivers.ResizeOptions{MachineType: "large"})
+
+	assert.Equal(t, ErrResizeMachineType, err)
+}
+
+func TestResizeDiskSnapshots(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{
+		args:   "snapshot default list --machinereadable",
+		stdOut: stdOutSnapshots,
+	}
+
+	err := driver.Resize(drivers.ResizeOptions{DiskSize: defaultDiskSize * 2})
+
+	assert.Equal(t, ErrResizeSnapshots, err)
+	assert.Equal(t, defaultDiskSize, driver.DiskSize)
+}
+
+func TestResizeDiskAttachFailure(t *testing.T) {
+	driver := NewDriver("default", "path")
+	mockCalls(t, driver, []Call{
+		{"vbm snapshot default list --machinereadable", "This machine does not have any snapshots", errors.New("exit status 1")},
+		{"vbm clonemedium disk path/machines/default/disk.vmdk path/machines/default/disk.vdi --format VDI", "", nil},
+		{"vbm storageattach default --storagectl SATA --port 1 --device 0 --type hdd --medium path/machines/default/disk.vdi", "", errors.New("locked")},
+		{"vbm closemedium disk path/machines/default/disk.vdi --delete", "", nil},
+	})
+
+	err := driver.Resize(drivers.ResizeOptions{DiskSize: defaultDiskSize * 2})
+
+	assert.EqualError(t, err, "locked")
+	assert.Equal(t, defaultDiskSize, driver.DiskSize)
+}
@@ -0,0 +1,363 @@
+package virtualbox
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/nfs"
+	"github.com/docker/machine/libmachine/state"
+)
+
+const (
+	shareTypeVboxsf = "vboxsf"
+	shareTypeNFS    = "nfs"
+)
+
+var (
+	ErrShareState = errors.New("The shared folders of a VirtualBox machine can only be changed while it's running or stopped")
+
+	ErrNoGuestAdditions = errors.New("The VM can't mount vboxsf shares without the VirtualBox Guest Additions, install them in the image or use --virtualbox-share-type nfs")
+)
+
+// vboxsfCommand succeeds when the guest supports vboxsf, loading the module
+// of the Guest Additions if needed. Cloud images usually lack them.
+const vboxsfCommand = "grep -qsw vboxsf /proc/filesystems || sudo modprobe vboxsf"
+
+// AddSharedFolder shares a directory of the host with the VM. A running VM
+// gets a transient share mounted right away, which is made permanent the
+// next time the VM starts. NFS shares are exported in any state.
+func (d *Driver) AddSharedFolder(folder drivers.SharedFolder) error {
+	if _, err := os.Stat(folder.HostPath); err != nil {
+		return err
+	}
+
+	name := sharedFolderName(folder.GuestPath)
+	for _, existing := range d.sharedFolders() {
+		if existing.GuestPath == folder.GuestPath || sharedFolderName(existing.GuestPath) == name {
+			return fmt.Errorf("A directory is already shared at %s", existing.GuestPath)
+		}
+	}
+
+	s, err := d.GetState()
+	if err != nil {
+		return err
+	}
+
+	d.materializeSharedFolders()
+
+	if d.ShareType == shareTypeNFS {
+		folders := append(append([]drivers.SharedFolder{}, d.SharedFolders...), folder)
+		if err := d.exportSharedFolders(folders); err != nil {
+			return err
+		}
+		if s == state.Running {
+			if err := d.mountSharedFolders([]drivers.SharedFolder{folder}); err != nil {
+				return err
+			}
+		}
+
+		d.SharedFolders = folders
+		return nil
+	}
+
+	switch s {
+	case state.Stopped:
+		if err := d.addSharedFolder(folder, false); err != nil {
+			return err
+		}
+	case state.Running:
+		if err := d.addSharedFolder(folder, true); err != nil {
+			return err
+		}
+		if err := d.mountSharedFolders([]drivers.SharedFolder{folder}); err != nil {
+			return err
+		}
+	default:
+		return ErrShareState
+	}
+
+	d.SharedFolders = append(d.SharedFolders, folder)
+	return nil
+}
+
+// RemoveSharedFolder stops sharing the directory mounted at guestPath. A
+// running VM keeps its permanent shares until it's restarted, the directory
+// is unmounted though.
+func (d *Driver) RemoveSharedFolder(guestPath string) error {
+	index := -1
+	for i, folder := range d.sharedFolders() {
+		if folder.GuestPath == guestPath {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("No directory is shared at %s", guestPath)
+	}
+
+	s, err := d.GetState()
+	if err != nil {
+		return err
+	}
+
+	d.materializeSharedFolders()
+
+	if d.ShareType == shareTypeNFS {
+		if s == state.Running {
+			if _, err := drivers.RunSSHCommandFromDriver(d, unmountCommand(guestPath)); err != nil {
+				return err
+			}
+		}
+
+		folders := append([]drivers.SharedFolder{}, d.SharedFolders[:index]...)
+		folders = append(folders, d.SharedFolders[index+1:]...)
+		if err := d.exportSharedFolders(folders); err != nil {
+			return err
+		}
+
+		d.SharedFolders = folders
+		return nil
+	}
+
+	name := sharedFolderName(guestPath)
+	switch s {
+	case state.Stopped:
+		if err := d.vbm("sharedfolder", "remove", d.MachineName, "--name", name); err != nil {
+			return err
+		}
+	case state.Running:
+		if _, err := drivers.RunSSHCommandFromDriver(d, unmountCommand(guestPath)); err != nil {
+			return err
+		}
+		if err := d.vbm("sharedfolder", "remove", d.MachineName, "--name", name, "--transient"); err != nil {
+			log.Debugf("The share %s will be removed on the next start: %s", name, err)
+		}
+	default:
+		return ErrShareState
+	}
+
+	d.SharedFolders = append(d.SharedFolders[:index], d.SharedFolders[index+1:]...)
+	return nil
+}
+
+// exportSharedFolders exports the folders over NFS to the host-only network
+// of the VM.
+func (d *Driver) exportSharedFolders(folders []drivers.SharedFolder) error {
+	_, network, err := d.hostOnlyAddress()
+	if err != nil {
+		return err
+	}
+
+	return nfs.Export(d.MachineName, network, folders)
+}
+
+// hostOnlyAddress returns the address of the host on the host-only network
+// the VM is attached to, and that network. It's the adapter set up when the
+// VM was created or started, whose address may differ from HostOnlyCIDR.
+func (d *Driver) hostOnlyAddress() (net.IP, *net.IPNet, error) {
+	stdout, err := d.vbmOut("showvminfo", d.MachineName, "--machinereadable")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := ""
+	err = parseKeyValues(stdout, reEqualLine, func(key, val string) error {
+		if strings.HasPrefix(key, "hostonlyadapter") {
+			name = strings.Trim(val, `"`)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if name == "" {
+		return nil, nil, errors.New("Machine does not have a host-only adapter")
+	}
+
+	nets, err := listHostOnlyAdapters(d.VBoxManager)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, n := range nets {
+		if n.Name == name {
+			return n.IPv4.IP, &net.IPNet{IP: n.IPv4.IP.Mask(n.IPv4.Mask), Mask: n.IPv4.Mask}, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("Unable to find the host-only adapter %q of the machine", name)
+}
+
+func (d *Driver) ListSharedFolders() ([]drivers.SharedFolder, error) {
+	return d.sharedFolders(), nil
+}
+
+// sharedFolders returns the directories shared with the VM. Machines that
+// never had their shared folders changed share the home directory, or the
+// dir:name folder they were created with, and let boot2docker mount it.
+func (d *Driver) sharedFolders() []drivers.SharedFolder {
+	if d.SharedFolders != nil {
+		return d.SharedFolders
+	}
+
+	folders := []drivers.SharedFolder{}
+	if d.NoShare {
+		return folders
+	}
+
+	shareName, shareDir := getShareDriveAndName()
+	if d.ShareFolder != "" {
+		shareDir, shareName = parseShareFolder(d.ShareFolder)
+	}
+
+	if shareDir == "" {
+		return folders
+	}
+	if _, err := os.Stat(shareDir); err != nil {
+		return folders
+	}
+
+	if shareName == "" {
+		// parts of the VBox internal code are buggy with share names that start with "/"
+		shareName = strings.TrimLeft(shareDir, "/")
+		// TODO do some basic Windows -> MSYS path conversion
+		// ie, s!^([a-z]+):[/\\]+!\1/!; s!\\!/!g
+	}
+
+	return append(folders, drivers.SharedFolder{
+		HostPath:  shareDir,
+		GuestPath: path.Join("/", shareName),
+	})
+}
+
+// materializeSharedFolders turns the default share into an explicit one, so
+// that it's mounted and synced like the others from now on.
+func (d *Driver) materializeSharedFolders() {
+	if d.SharedFolders == nil {
+		d.SharedFolders = d.sharedFolders()
+		d.ShareFolder = ""
+	}
+}
+
+// addSharedFolder adds the share of the folder to the VM, for the current
+// run only if transient.
+func (d *Driver) addSharedFolder(folder drivers.SharedFolder, transient bool) error {
+	name := sharedFolderName(folder.GuestPath)
+
+	args := []string{"sharedfolder", "add", d.MachineName, "--name", name, "--hostpath", folder.HostPath}
+	if folder.ReadOnly {
+		args = append(args, "--readonly")
+	}
+	if transient {
+		args = append(args, "--transient")
+	}
+
+	if err := d.vbm(args...); err != nil {
+		return err
+	}
+
+	// enable symlinks
+	return d.vbm("setextradata", d.MachineName, "VBoxInternal2/SharedFoldersEnableSymlinksCreate/"+name, "1")
+}
+
+// syncSharedFolders replaces the permanent shares of the stopped VM with the
+// shared folders of the machine. It's a no-op for machines that still use
+// the default share, or share over NFS.
+func (d *Driver) syncSharedFolders() error {
+	if d.SharedFolders == nil || d.ShareType == shareTypeNFS {
+		return nil
+	}
+
+	stdout, err := d.vbmOut("showvminfo", d.MachineName, "--machinereadable")
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	err = parseKeyValues(stdout, reEqualLine, func(key, val string) error {
+		if strings.HasPrefix(key, "SharedFolderNameMachineMapping") {
+			names = append(names, strings.Trim(val, `"`))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := d.vbm("sharedfolder", "remove", d.MachineName, "--name", name); err != nil {
+			return err
+		}
+	}
+
+	for _, folder := range d.SharedFolders {
+		if err := d.addSharedFolder(folder, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mountSharedFolders mounts the folders in the running VM, those already
+// mounted are left alone.
+func (d *Driver) mountSharedFolders(folders []drivers.SharedFolder) error {
+	if d.ShareType != shareTypeNFS {
+		if _, err := drivers.RunSSHCommandFromDriver(d, vboxsfCommand); err != nil {
+			log.Debugf("vboxsf isn't supported by the VM: %s", err)
+			return ErrNoGuestAdditions
+		}
+	}
+
+	// the NFS server is reached at the address of the host on the
+	// host-only network
+	var hostIP net.IP
+	if d.ShareType == shareTypeNFS {
+		var err error
+		if hostIP, _, err = d.hostOnlyAddress(); err != nil {
+			return err
+		}
+	}
+
+	for _, folder := range folders {
+		command := mountCommand(folder)
+		if d.ShareType == shareTypeNFS {
+			command = nfs.MountCommand(hostIP, folder)
+		}
+
+		log.Debugf("Mounting %s at %s...", folder.HostPath, folder.GuestPath)
+		if _, err := drivers.RunSSHCommandFromDriver(d, command); err != nil {
+			return fmt.Errorf("Unable to mount %s: %s", folder.GuestPath, err)
+		}
+	}
+
+	return nil
+}
+
+// sharedFolderName returns the name of the VirtualBox share mounted at
+// guestPath. It's the one boot2docker automounted for the default share.
+func sharedFolderName(guestPath string) string {
+	return strings.TrimLeft(guestPath, "/")
+}
+
+func mountCommand(folder drivers.SharedFolder) string {
+	options := "uid=$(id -u),gid=$(id -g)"
+	if folder.ReadOnly {
+		options += ",ro"
+	}
+
+	guestPath := mcnutils.ShellQuote(folder.GuestPath)
+
+	return fmt.Sprintf("sudo mkdir -p %s && (%s || sudo mount -t vboxsf -o %s %s %s)",
+		guestPath, drivers.MountedCommand(folder.GuestPath), options, mcnutils.ShellQuote(sharedFolderName(folder.GuestPath)), guestPath)
+}
+
+func unmountCommand(guestPath string) string {
+	return fmt.Sprintf("! %s || sudo umount %s", drivers.MountedCommand(guestPath), mcnutils.ShellQuote(guestPath))
+}
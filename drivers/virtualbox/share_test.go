@@ -0,0 +1,217 @@
+package virtualbox
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSharedFolderStopped(t *testing.T) {
+	hostPath, _ := ioutil.TempDir("", "share")
+	defer os.RemoveAll(hostPath)
+
+	driver := newTestDriver("default")
+	driver.SharedFolders = []drivers.SharedFolder{}
+	mockCalls(t, driver, []Call{
+		{"vbm showvminfo default --machinereadable", `VMState="poweroff"`, nil},
+		{"vbm sharedfolder add default --name mnt/src --hostpath " + hostPath + " --readonly", "", nil},
+		{"vbm setextradata default VBoxInternal2/SharedFoldersEnableSymlinksCreate/mnt/src 1", "", nil},
+	})
+
+	folder := drivers.SharedFolder{HostPath: hostPath, GuestPath: "/mnt/src", ReadOnly: true}
+	err := driver.AddSharedFolder(folder)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.SharedFolder{folder}, driver.SharedFolders)
+	assert.True(t, drivers.HasCapability(driver, drivers.CapabilityShare))
+}
+
+func TestAddSharedFolderAlreadyShared(t *testing.T) {
+	hostPath, _ := ioutil.TempDir("", "share")
+	defer os.RemoveAll(hostPath)
+
+	driver := newTestDriver("default")
+	driver.SharedFolders = []drivers.SharedFolder{{HostPath: "/data", GuestPath: "/src"}}
+
+	err := driver.AddSharedFolder(drivers.SharedFolder{HostPath: hostPath, GuestPath: "/src"})
+
+	assert.EqualError(t, err, "A directory is already shared at /src")
+}
+
+func TestAddSharedFolderSaved(t *testing.T) {
+	hostPath, _ := ioutil.TempDir("", "share")
+	defer os.RemoveAll(hostPath)
+
+	driver := newTestDriver("default")
+	driver.SharedFolders = []drivers.SharedFolder{}
+	mockCalls(t, driver, []Call{
+		{"vbm showvminfo default --machinereadable", `VMState="saved"`, nil},
+	})
+
+	err := driver.AddSharedFolder(drivers.SharedFolder{HostPath: hostPath, GuestPath: "/src"})
+
+	assert.Equal(t, ErrShareState, err)
+	assert.Empty(t, driver.SharedFolders)
+}
+
+func TestRemoveSharedFolderStopped(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.SharedFolders = []drivers.SharedFolder{
+		{HostPath: "/src", GuestPath: "/src"},
+		{HostPath: "/data", GuestPath: "/data"},
+	}
+	mockCalls(t, driver, []Call{
+		{"vbm showvminfo default --machinereadable", `VMState="poweroff"`, nil},
+		{"vbm sharedfolder remove default --name src", "", nil},
+	})
+
+	err := driver.RemoveSharedFolder("/src")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.SharedFolder{{HostPath: "/data", GuestPath: "/data"}}, driver.SharedFolders)
+}
+
+func TestRemoveSharedFolderNotShared(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.SharedFolders = []drivers.SharedFolder{}
+
+	err := driver.RemoveSharedFolder("/src")
+
+	assert.EqualError(t, err, "No directory is shared at /src")
+}
+
+func TestListSharedFolders(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.NoShare = true
+
+	folders, err := driver.ListSharedFolders()
+
+	assert.NoError(t, err)
+	assert.Empty(t, folders)
+
+	driver.NoShare = false
+	driver.ShareFolder = os.TempDir() + ":tmp"
+
+	folders, err = driver.ListSharedFolders()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.SharedFolder{{HostPath: os.TempDir(), GuestPath: "/tmp"}}, folders)
+}
+
+func TestSyncSharedFolders(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.SharedFolders = []drivers.SharedFolder{{HostPath: "/data", GuestPath: "/data", ReadOnly: true}}
+	mockCalls(t, driver, []Call{
+		{"vbm showvminfo default --machinereadable", `VMState="poweroff"
+SharedFolderNameMachineMapping1="hosthome"
+SharedFolderPathMachineMapping1="/home"
+SharedFolderNameMachineMapping2="data"
+SharedFolderPathMachineMapping2="/data"`, nil},
+		{"vbm sharedfolder remove default --name hosthome", "", nil},
+		{"vbm sharedfolder remove default --name data", "", nil},
+		{"vbm sharedfolder add default --name data --hostpath /data --readonly", "", nil},
+		{"vbm setextradata default VBoxInternal2/SharedFoldersEnableSymlinksCreate/data 1", "", nil},
+	})
+
+	assert.NoError(t, driver.syncSharedFolders())
+}
+
+func TestSyncSharedFoldersDefaultShare(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{}
+
+	assert.NoError(t, driver.syncSharedFolders())
+}
+
+func TestMountCommand(t *testing.T) {
+	assert.Equal(t,
+		"sudo mkdir -p '/mnt/src' && (grep -qsF ' /mnt/src ' /proc/mounts || sudo mount -t vboxsf -o uid=$(id -u),gid=$(id -g) 'mnt/src' '/mnt/src')",
+		mountCommand(drivers.SharedFolder{HostPath: "/src", GuestPath: "/mnt/src"}))
+	assert.Equal(t,
+		"sudo mkdir -p '/data' && (grep -qsF ' /data ' /proc/mounts || sudo mount -t vboxsf -o uid=$(id -u),gid=$(id -g),ro 'data' '/data')",
+		mountCommand(drivers.SharedFolder{HostPath: "/data", GuestPath: "/data", ReadOnly: true}))
+	assert.Equal(t,
+		"sudo mkdir -p '/my data;$(reboot)' && (grep -qsF ' /my\\040data;$(reboot) ' /proc/mounts || sudo mount -t vboxsf -o uid=$(id -u),gid=$(id -g) 'my data;$(reboot)' '/my data;$(reboot)')",
+		mountCommand(drivers.SharedFolder{HostPath: "/data", GuestPath: "/my data;$(reboot)"}))
+}
+
+func TestUnmountCommand(t *testing.T) {
+	assert.Equal(t, "! grep -qsF ' /mnt/src ' /proc/mounts || sudo umount '/mnt/src'", unmountCommand("/mnt/src"))
+	assert.Equal(t, `! grep -qsF ' /it'\''s ' /proc/mounts || sudo umount '/it'\''s'`, unmountCommand("/it's"))
+}
+
+func TestSetConfigFromFlagsSharedFolders(t *testing.T) {
+	driver := newTestDriver("default")
+
+	checkFlags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"virtualbox-share-folder": []string{"/src:/mnt/src", "/data:/data:ro"},
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(checkFlags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.SharedFolder{
+		{HostPath: "/src", GuestPath: "/mnt/src"},
+		{HostPath: "/data", GuestPath: "/data", ReadOnly: true},
+	}, driver.SharedFolders)
+	assert.Equal(t, shareTypeVboxsf, driver.ShareType)
+}
+
+func TestSetConfigFromFlagsNFSDefaultShare(t *testing.T) {
+	driver := newTestDriver("default")
+
+	checkFlags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"virtualbox-share-type": "nfs",
+			"virtualbox-no-share":   true,
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(checkFlags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, shareTypeNFS, driver.ShareType)
+	assert.Equal(t, []drivers.SharedFolder{}, driver.SharedFolders)
+}
+
+func TestSyncSharedFoldersNFS(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.ShareType = shareTypeNFS
+	driver.SharedFolders = []drivers.SharedFolder{{HostPath: "/data", GuestPath: "/data"}}
+	driver.VBoxManager = &VBoxManagerMock{}
+
+	assert.NoError(t, driver.syncSharedFolders())
+}
+
+func TestHostOnlyAddress(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.HostOnlyCIDR = "192.168.42.1/24"
+	mockCalls(t, driver, []Call{
+		{"vbm showvminfo default --machinereadable", "nic2=\"hostonly\"\nhostonlyadapter2=\"vboxnet0\"\n", nil},
+		{"vbm list hostonlyifs", stdOutOneHostOnlyNetwork, nil},
+	})
+
+	ip, network, err := driver.hostOnlyAddress()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.99.1", ip.String())
+	assert.Equal(t, "192.168.99.0/24", network.String())
+}
+
+func TestHostOnlyAddressNoAdapter(t *testing.T) {
+	driver := newTestDriver("default")
+	mockCalls(t, driver, []Call{
+		{"vbm showvminfo default --machinereadable", "nic2=\"none\"\n", nil},
+	})
+
+	_, _, err := driver.hostOnlyAddress()
+
+	assert.Error(t, err)
+}
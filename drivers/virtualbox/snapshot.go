@@ -0,0 +1,133 @@
+package virtualbox
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+)
+
+const (
+	snapshotDescriptionPrefix = "Created by Docker Machine at "
+	snapshotTimeFormat        = time.RFC3339
+)
+
+var (
+	reSnapshotKey = regexp.MustCompile(`^Snapshot(Name|Description)((-\d+)*)$`)
+	reNoSnapshots = regexp.MustCompile(`does not have any snapshots`)
+
+	// reSnapshotNodeLast matches the last part of the node of a snapshot,
+	// removing it gives the node of the snapshot it was taken from.
+	reSnapshotNodeLast = regexp.MustCompile(`-\d+$`)
+)
+
+// CreateSnapshot takes a snapshot of the VM. The creation time is kept in its
+// description, VBoxManage doesn't list it.
+func (d *Driver) CreateSnapshot(name string) error {
+	description := snapshotDescriptionPrefix + time.Now().UTC().Format(snapshotTimeFormat)
+
+	if err := d.vbm("snapshot", d.MachineName, "take", name, "--description", description); err != nil {
+		return fmt.Errorf("Unable to take snapshot %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// ListSnapshots returns the snapshots of the VM, oldest first.
+func (d *Driver) ListSnapshots() ([]drivers.Snapshot, error) {
+	stdout, stderr, err := d.vbmOutErr("snapshot", d.MachineName, "list", "--machinereadable")
+	if err != nil {
+		if reNoSnapshots.MatchString(stdout + stderr) {
+			return []drivers.Snapshot{}, nil
+		}
+		return nil, err
+	}
+
+	return parseSnapshots(stdout)
+}
+
+// parseSnapshots parses the snapshots listed by VBoxManage, which lists a
+// snapshot before the ones taken from it, branch after branch. They're sorted
+// by creation time, those taken outside of Docker Machine being deemed as old
+// as the snapshot they were taken from.
+func parseSnapshots(stdout string) ([]drivers.Snapshot, error) {
+	snapshots := []drivers.Snapshot{}
+	nodes := []string{}
+	indexes := map[string]int{}
+
+	err := parseKeyValues(stdout, reEqualLine, func(key, val string) error {
+		res := reSnapshotKey.FindStringSubmatch(key)
+		if res == nil {
+			return nil
+		}
+
+		node := res[2]
+		i, ok := indexes[node]
+		if !ok {
+			i = len(snapshots)
+			indexes[node] = i
+			snapshots = append(snapshots, drivers.Snapshot{})
+			nodes = append(nodes, node)
+		}
+
+		val = strings.Trim(val, `"`)
+
+		switch res[1] {
+		case "Name":
+			snapshots[i].Name = val
+		case "Description":
+			if created, err := time.Parse(snapshotTimeFormat, strings.TrimPrefix(val, snapshotDescriptionPrefix)); err == nil {
+				snapshots[i].Created = created
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ages := map[string]time.Time{}
+	for i, node := range nodes {
+		ages[node] = snapshots[i].Created
+		if ages[node].IsZero() {
+			ages[node] = ages[reSnapshotNodeLast.ReplaceAllString(node, "")]
+		}
+	}
+
+	sorted := make([]int, len(snapshots))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return ages[nodes[sorted[i]]].Before(ages[nodes[sorted[j]]])
+	})
+
+	result := []drivers.Snapshot{}
+	for _, i := range sorted {
+		result = append(result, snapshots[i])
+	}
+
+	return result, nil
+}
+
+// RestoreSnapshot restores the snapshot of the stopped VM.
+func (d *Driver) RestoreSnapshot(name string) error {
+	if err := d.vbm("snapshot", d.MachineName, "restore", name); err != nil {
+		return fmt.Errorf("Unable to restore snapshot %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// RemoveSnapshot deletes the snapshot, merging its differencing disks.
+func (d *Driver) RemoveSnapshot(name string) error {
+	if err := d.vbm("snapshot", d.MachineName, "delete", name); err != nil {
+		return fmt.Errorf("Unable to delete snapshot %s: %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package virtualbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+var stdOutSnapshots = `SnapshotName="base"
+SnapshotUUID="6e4f3ed6-0b1a-4ac8-a8b2-6a1c0a3e8a4f"
+SnapshotDescription="Created by Docker Machine at 2018-10-02T13:04:05Z"
+SnapshotName-1="manual"
+SnapshotUUID-1="1c9a6a8e-3a56-4b0f-9a0b-8d9d9f1f7e1e"
+SnapshotDescription-1="taken from the GUI"
+CurrentSnapshotName="manual"
+CurrentSnapshotUUID="1c9a6a8e-3a56-4b0f-9a0b-8d9d9f1f7e1e"
+CurrentSnapshotNode="SnapshotName-1"
+`
+
+func TestListSnapshots(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{
+		args:   "snapshot default list --machinereadable",
+		stdOut: stdOutSnapshots,
+	}
+
+	snapshots, err := driver.ListSnapshots()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.Snapshot{
+		{Name: "base", Created: time.Date(2018, 10, 2, 13, 4, 5, 0, time.UTC)},
+		{Name: "manual"},
+	}, snapshots)
+}
+
+func TestListSnapshotsBranches(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{
+		args: "snapshot default list --machinereadable",
+		stdOut: `SnapshotName="base"
+SnapshotDescription="Created by Docker Machine at 2018-10-02T10:00:00Z"
+SnapshotName-1="upgrade"
+SnapshotDescription-1="Created by Docker Machine at 2018-10-02T12:00:00Z"
+SnapshotName-1-1="manual"
+SnapshotDescription-1-1="taken from the GUI"
+SnapshotName-2="config"
+SnapshotDescription-2="Created by Docker Machine at 2018-10-02T11:00:00Z"
+`,
+	}
+
+	snapshots, err := driver.ListSnapshots()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.Snapshot{
+		{Name: "base", Created: time.Date(2018, 10, 2, 10, 0, 0, 0, time.UTC)},
+		{Name: "config", Created: time.Date(2018, 10, 2, 11, 0, 0, 0, time.UTC)},
+		{Name: "upgrade", Created: time.Date(2018, 10, 2, 12, 0, 0, 0, time.UTC)},
+		{Name: "manual"},
+	}, snapshots)
+}
+
+func TestListSnapshotsNone(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{
+		args:   "snapshot default list --machinereadable",
+		stdOut: "This machine does not have any snapshots\n",
+		err:    errors.New("exit status 1"),
+	}
+
+	snapshots, err := driver.ListSnapshots()
+
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	driver := newTestDriver("default")
+	driver.VBoxManager = &VBoxManagerMock{
+		args: "snapshot default restore base",
+	}
+
+	assert.NoError(t, driver.RestoreSnapshot("base"))
+	assert.True(t, drivers.HasCapability(driver, drivers.CapabilitySnapshot))
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnflag"
 	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/nfs"
 	"github.com/docker/machine/libmachine/state"
 )
 
@@ -29,12 +30,21 @@ const (
 	defaultHostOnlyNictype     = "82540EM"
 	defaultHostOnlyPromiscMode = "deny"
 	defaultUIType              = "headless"
+	defaultShareType           = shareTypeVboxsf
 	defaultHostOnlyNoDHCP      = false
 	defaultDiskSize            = 20000
 	defaultDNSProxy            = true
 	defaultDNSResolver         = false
 )
 
+var (
+	// nicTypes are the network adapter types VirtualBox emulates.
+	nicTypes     = []string{"Am79C970A", "Am79C973", "82540EM", "82543GC", "82545EM", "virtio"}
+	promiscModes = []string{"deny", "allow-vms", "allow-all"}
+	uiTypes      = []string{"gui", "sdl", "headless", "separate"}
+	shareTypes   = []string{shareTypeVboxsf, shareTypeNFS}
+)
+
 var (
 	ErrUnableToGenerateRandomIP = errors.New("unable to generate random IP")
 	ErrMustEnableVTX            = errors.New("This computer doesn't have VT-X/AMD-v enabled. Enabling it in the BIOS is mandatory")
@@ -50,6 +60,7 @@ type Driver struct {
 	b2dUpdater          B2DUpdater
 	sshKeyGenerator     SSHKeyGenerator
 	diskCreator         DiskCreator
+	cloudImageProvider  CloudImageProvider
 	logsReader          LogsReader
 	ipWaiter            IPWaiter
 	randomInter         RandomInter
@@ -60,6 +71,7 @@ type Driver struct {
 	NatNicType          string
 	Boot2DockerURL      string
 	Boot2DockerImportVM string
+	CloudImage          string
 	HostDNSResolver     bool
 	HostOnlyCIDR        string
 	HostOnlyNicType     string
@@ -70,6 +82,8 @@ type Driver struct {
 	DNSProxy            bool
 	NoVTXCheck          bool
 	ShareFolder         string
+	SharedFolders       []drivers.SharedFolder
+	ShareType           string
 }
 
 // NewDriver creates a new VirtualBox driver with default settings.
@@ -79,6 +93,7 @@ func NewDriver(hostName, storePath string) *Driver {
 		b2dUpdater:          NewB2DUpdater(),
 		sshKeyGenerator:     NewSSHKeyGenerator(),
 		diskCreator:         NewDiskCreator(),
+		cloudImageProvider:  NewCloudImageProvider(),
 		logsReader:          NewLogsReader(),
 		ipWaiter:            NewIPWaiter(),
 		randomInter:         NewRandomInter(),
@@ -92,6 +107,7 @@ func NewDriver(hostName, storePath string) *Driver {
 		HostOnlyNicType:     defaultHostOnlyNictype,
 		HostOnlyPromiscMode: defaultHostOnlyPromiscMode,
 		UIType:              defaultUIType,
+		ShareType:           defaultShareType,
 		HostOnlyNoDHCP:      defaultHostOnlyNoDHCP,
 		DNSProxy:            defaultDNSProxy,
 		HostDNSResolver:     defaultDNSResolver,
@@ -136,16 +152,22 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Value:  defaultBoot2DockerImportVM,
 			EnvVar: "VIRTUALBOX_BOOT2DOCKER_IMPORT_VM",
 		},
+		mcnflag.StringFlag{
+			Name:   "virtualbox-cloud-image",
+			Usage:  "The URL or path of a cloud image (qcow2, vmdk, vdi or raw) to boot with cloud-init instead of boot2docker",
+			EnvVar: "VIRTUALBOX_CLOUD_IMAGE",
+		},
 		mcnflag.BoolFlag{
 			Name:   "virtualbox-host-dns-resolver",
 			Usage:  "Use the host DNS resolver",
 			EnvVar: "VIRTUALBOX_HOST_DNS_RESOLVER",
 		},
-		mcnflag.StringFlag{
+		mcnflag.EnumFlag{
 			Name:   "virtualbox-nat-nictype",
 			Usage:  "Specify the Network Adapter Type",
 			Value:  defaultHostOnlyNictype,
 			EnvVar: "VIRTUALBOX_NAT_NICTYPE",
+			Values: nicTypes,
 		},
 		mcnflag.StringFlag{
 			Name:   "virtualbox-hostonly-cidr",
@@ -153,23 +175,26 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Value:  defaultHostOnlyCIDR,
 			EnvVar: "VIRTUALBOX_HOSTONLY_CIDR",
 		},
-		mcnflag.StringFlag{
+		mcnflag.EnumFlag{
 			Name:   "virtualbox-hostonly-nictype",
 			Usage:  "Specify the Host Only Network Adapter Type",
 			Value:  defaultHostOnlyNictype,
 			EnvVar: "VIRTUALBOX_HOSTONLY_NIC_TYPE",
+			Values: nicTypes,
 		},
-		mcnflag.StringFlag{
+		mcnflag.EnumFlag{
 			Name:   "virtualbox-hostonly-nicpromisc",
 			Usage:  "Specify the Host Only Network Adapter Promiscuous Mode",
 			Value:  defaultHostOnlyPromiscMode,
 			EnvVar: "VIRTUALBOX_HOSTONLY_NIC_PROMISC",
+			Values: promiscModes,
 		},
-		mcnflag.StringFlag{
+		mcnflag.EnumFlag{
 			Name:   "virtualbox-ui-type",
-			Usage:  "Specify the UI Type: (gui|sdl|headless|separate)",
+			Usage:  "Specify the UI Type",
 			Value:  defaultUIType,
 			EnvVar: "VIRTUALBOX_UI_TYPE",
+			Values: uiTypes,
 		},
 		mcnflag.BoolFlag{
 			Name:   "virtualbox-hostonly-no-dhcp",
@@ -191,10 +216,17 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			Usage:  "Disable checking for the availability of hardware virtualization before the vm is started",
 			EnvVar: "VIRTUALBOX_NO_VTX_CHECK",
 		},
-		mcnflag.StringFlag{
+		mcnflag.StringSliceFlag{
 			EnvVar: "VIRTUALBOX_SHARE_FOLDER",
 			Name:   "virtualbox-share-folder",
-			Usage:  "Mount the specified directory instead of the default home location. Format: dir:name",
+			Usage:  "Mount the specified directory instead of the default home location, can be repeated. Format: host:guestpath[:ro]",
+		},
+		mcnflag.EnumFlag{
+			Name:   "virtualbox-share-type",
+			Usage:  "Share the folders with VirtualBox shared folders or over NFS, which needs sudo to update /etc/exports without a password",
+			Value:  defaultShareType,
+			EnvVar: "VIRTUALBOX_SHARE_TYPE",
+			Values: shareTypes,
 		},
 	}
 }
@@ -216,6 +248,15 @@ func (d *Driver) DriverName() string {
 	return "virtualbox"
 }
 
+// ConcurrencyPolicy serializes the calls to the driver, VirtualBox scrapes up
+// against its own locking mechanisms when it's driven by several processes
+// at once.
+func (d *Driver) ConcurrencyPolicy() drivers.ConcurrencyPolicy {
+	return drivers.ConcurrencyPolicy{
+		MaxParallel: 1,
+	}
+}
+
 func (d *Driver) GetURL() (string, error) {
 	ip, err := d.GetIP()
 	if err != nil {
@@ -235,6 +276,7 @@ func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
 	d.SetSwarmConfigFromFlags(flags)
 	d.SSHUser = "docker"
 	d.Boot2DockerImportVM = flags.String("virtualbox-import-boot2docker-vm")
+	d.CloudImage = flags.String("virtualbox-cloud-image")
 	d.HostDNSResolver = flags.Bool("virtualbox-host-dns-resolver")
 	d.NatNicType = flags.String("virtualbox-nat-nictype")
 	d.HostOnlyCIDR = flags.String("virtualbox-hostonly-cidr")
@@ -245,7 +287,27 @@ func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
 	d.NoShare = flags.Bool("virtualbox-no-share")
 	d.DNSProxy = !flags.Bool("virtualbox-no-dns-proxy")
 	d.NoVTXCheck = flags.Bool("virtualbox-no-vtx-check")
-	d.ShareFolder = flags.String("virtualbox-share-folder")
+
+	for _, share := range flags.StringSlice("virtualbox-share-folder") {
+		folder, err := drivers.ParseSharedFolder(share)
+		if err != nil {
+			return err
+		}
+		if folder.HostPath, err = filepath.Abs(folder.HostPath); err != nil {
+			return err
+		}
+		d.SharedFolders = append(d.SharedFolders, folder)
+	}
+
+	// boot2docker only automounts vboxsf shares
+	d.ShareType = flags.String("virtualbox-share-type")
+	if d.ShareType == shareTypeNFS {
+		d.materializeSharedFolders()
+	}
+
+	if d.CloudImage != "" && (d.Boot2DockerURL != "" || d.Boot2DockerImportVM != "") {
+		return ErrCloudImageWithBoot2Docker
+	}
 
 	return nil
 }
@@ -273,10 +335,24 @@ func (d *Driver) PreCreateCheck() error {
 		}
 	}
 
+	if d.ShareType == shareTypeNFS {
+		if err := nfs.CheckSupported(); err != nil {
+			return err
+		}
+	}
+
+	for _, folder := range d.SharedFolders {
+		if _, err := os.Stat(folder.HostPath); err != nil {
+			return fmt.Errorf("Unable to share %s: %s", folder.HostPath, err)
+		}
+	}
+
 	// Downloading boot2docker to cache should be done here to make sure
 	// that a download failure will not leave a machine half created.
-	if err := d.b2dUpdater.UpdateISOCache(d.StorePath, d.Boot2DockerURL); err != nil {
-		return err
+	if d.CloudImage == "" {
+		if err := d.b2dUpdater.UpdateISOCache(d.StorePath, d.Boot2DockerURL); err != nil {
+			return err
+		}
 	}
 
 	// Check that Host-only interfaces are ok
@@ -297,8 +373,10 @@ func (d *Driver) Create() error {
 }
 
 func (d *Driver) CreateVM() error {
-	if err := d.b2dUpdater.CopyIsoToMachineDir(d.StorePath, d.MachineName, d.Boot2DockerURL); err != nil {
-		return err
+	if d.CloudImage == "" {
+		if err := d.b2dUpdater.CopyIsoToMachineDir(d.StorePath, d.MachineName, d.Boot2DockerURL); err != nil {
+			return err
+		}
 	}
 
 	log.Info("Creating VirtualBox VM...")
@@ -337,6 +415,10 @@ func (d *Driver) CreateVM() error {
 		if err := mcnutils.CopyFile(keyPath, d.GetSSHKeyPath()); err != nil {
 			return err
 		}
+	} else if d.CloudImage != "" {
+		if err := d.createCloudImageDisk(); err != nil {
+			return err
+		}
 	} else {
 		log.Infof("Creating SSH key...")
 		if err := d.sshKeyGenerator.Generate(d.GetSSHKeyPath()); err != nil {
@@ -377,6 +459,11 @@ func (d *Driver) CreateVM() error {
 		dnsProxy = "on"
 	}
 
+	bootDevice := "dvd"
+	if d.CloudImage != "" {
+		bootDevice = "disk"
+	}
+
 	var modifyFlags = []string{
 		"modifyvm", d.MachineName,
 		"--firmware", "bios",
@@ -400,12 +487,17 @@ func (d *Driver) CreateVM() error {
 		"--largepages", "on",
 		"--vtxvpid", "on",
 		"--accelerate3d", "off",
-		"--boot1", "dvd"}
+		"--boot1", bootDevice}
 
 	if runtime.GOOS == "windows" && runtime.GOARCH == "386" {
 		modifyFlags = append(modifyFlags, "--longmode", "on")
 	}
 
+	if d.CloudImage != "" {
+		// cloud images log to the serial console, some hang on boot without one
+		modifyFlags = append(modifyFlags, "--uart1", "0x3F8", "4", "--uartmode1", "file", d.ResolveStorePath(consoleLogFilename))
+	}
+
 	if err := d.vbm(modifyFlags...); err != nil {
 		return err
 	}
@@ -429,7 +521,7 @@ func (d *Driver) CreateVM() error {
 		"--port", "0",
 		"--device", "0",
 		"--type", "dvddrive",
-		"--medium", d.ResolveStorePath("boot2docker.iso")); err != nil {
+		"--medium", d.dvdPath()); err != nil {
 		return err
 	}
 
@@ -450,33 +542,32 @@ func (d *Driver) CreateVM() error {
 		return err
 	}
 
-	shareName, shareDir := getShareDriveAndName()
-
-	if d.ShareFolder != "" {
-		shareDir, shareName = parseShareFolder(d.ShareFolder)
+	// the NFS exports are updated on start
+	if d.ShareType == shareTypeNFS {
+		return nil
 	}
 
-	if shareDir != "" && !d.NoShare {
-		log.Debugf("setting up shareDir '%s' -> '%s'", shareDir, shareName)
-		if _, err := os.Stat(shareDir); err != nil && !os.IsNotExist(err) {
-			return err
-		} else if !os.IsNotExist(err) {
-			if shareName == "" {
-				// parts of the VBox internal code are buggy with share names that start with "/"
-				shareName = strings.TrimLeft(shareDir, "/")
-				// TODO do some basic Windows -> MSYS path conversion
-				// ie, s!^([a-z]+):[/\\]+!\1/!; s!\\!/!g
-			}
-
-			// woo, shareDir exists!  let's carry on!
-			if err := d.vbm("sharedfolder", "add", d.MachineName, "--name", shareName, "--hostpath", shareDir, "--automount"); err != nil {
+	if d.SharedFolders != nil {
+		for _, folder := range d.SharedFolders {
+			log.Debugf("setting up shared folder %s", folder)
+			if err := d.addSharedFolder(folder, false); err != nil {
 				return err
 			}
+		}
+		return nil
+	}
 
-			// enable symlinks
-			if err := d.vbm("setextradata", d.MachineName, "VBoxInternal2/SharedFoldersEnableSymlinksCreate/"+shareName, "1"); err != nil {
-				return err
-			}
+	for _, folder := range d.sharedFolders() {
+		log.Debugf("setting up shareDir '%s' -> '%s'", folder.HostPath, sharedFolderName(folder.GuestPath))
+
+		// woo, shareDir exists!  let's carry on!
+		if err := d.vbm("sharedfolder", "add", d.MachineName, "--name", sharedFolderName(folder.GuestPath), "--hostpath", folder.HostPath, "--automount"); err != nil {
+			return err
+		}
+
+		// enable symlinks
+		if err := d.vbm("setextradata", d.MachineName, "VBoxInternal2/SharedFoldersEnableSymlinksCreate/"+sharedFolderName(folder.GuestPath), "1"); err != nil {
+			return err
 		}
 	}
 
@@ -506,6 +597,30 @@ func (d *Driver) hostOnlyIPAvailable() bool {
 }
 
 func (d *Driver) Start() error {
+	if err := d.start(); err != nil {
+		return err
+	}
+
+	if d.ShareType == shareTypeNFS {
+		if err := d.exportSharedFolders(d.SharedFolders); err != nil {
+			return err
+		}
+	}
+
+	// boot2docker only automounts the default share
+	if len(d.SharedFolders) > 0 {
+		if err := drivers.WaitForSSH(d); err != nil {
+			return err
+		}
+
+		log.Infof("Mounting the shared folders...")
+		return d.mountSharedFolders(d.SharedFolders)
+	}
+
+	return nil
+}
+
+func (d *Driver) start() error {
 	s, err := d.GetState()
 	if err != nil {
 		return err
@@ -518,6 +633,10 @@ func (d *Driver) Start() error {
 		if hostOnlyAdapter, err = d.setupHostOnlyNetwork(d.MachineName); err != nil {
 			return fmt.Errorf("Error setting up host only network on machine start: %s", err)
 		}
+
+		if err := d.syncSharedFolders(); err != nil {
+			return err
+		}
 	}
 
 	switch s {
@@ -664,6 +783,12 @@ func (d *Driver) Kill() error {
 }
 
 func (d *Driver) Remove() error {
+	if d.ShareType == shareTypeNFS {
+		if err := nfs.Unexport(d.MachineName); err != nil {
+			log.Warnf("Unable to remove the NFS exports of the VM: %s", err)
+		}
+	}
+
 	s, err := d.GetState()
 	if err == ErrMachineNotExist {
 		return nil
@@ -805,7 +930,24 @@ func (d *Driver) publicSSHKeyPath() string {
 	return d.GetSSHKeyPath() + ".pub"
 }
 
+// dvdPath returns the boot2docker ISO, or the cloud-init seed of the cloud
+// image.
+func (d *Driver) dvdPath() string {
+	if d.CloudImage != "" {
+		return d.seedISOPath()
+	}
+	return d.ResolveStorePath("boot2docker.iso")
+}
+
 func (d *Driver) diskPath() string {
+	// the disks of cloud images are VDI from the start, the others are
+	// converted to VDI the first time they're resized
+	if d.CloudImage != "" {
+		return d.vdiDiskPath()
+	}
+	if _, err := os.Stat(d.vdiDiskPath()); err == nil {
+		return d.vdiDiskPath()
+	}
 	return d.ResolveStorePath("disk.vmdk")
 }
 
@@ -443,6 +443,16 @@ func (v *MockCreateOperations) Create(size int, publicSSHKeyPath, diskPath strin
 	return err
 }
 
+func (v *MockCreateOperations) Download(imageURL, dest string) error {
+	_, err := v.doCall("Download " + imageURL + " " + dest)
+	return err
+}
+
+func (v *MockCreateOperations) MakeSeed(hostname, user, publicSSHKeyPath, dest string) error {
+	_, err := v.doCall("MakeSeed " + fmt.Sprintf("%s %s %s %s", hostname, user, publicSSHKeyPath, dest))
+	return err
+}
+
 func (v *MockCreateOperations) Read(path string) ([]string, error) {
 	_, err := v.doCall("Read " + path)
 	return []string{}, err
@@ -506,6 +516,7 @@ func mockCalls(t *testing.T, driver *Driver, expectedCalls []Call) {
 	driver.b2dUpdater = mockOperations
 	driver.sshKeyGenerator = mockOperations
 	driver.diskCreator = mockOperations
+	driver.cloudImageProvider = mockOperations
 	driver.logsReader = mockOperations
 	driver.ipWaiter = mockOperations
 	driver.randomInter = mockOperations
@@ -574,6 +585,65 @@ func TestCreateVMWithSpecificNatNicType(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateVMWithCloudImage(t *testing.T) {
+	shareName, shareDir := getShareDriveAndName()
+
+	modifyVMcommand := "vbm modifyvm default --firmware bios --bioslogofadein off --bioslogofadeout off --bioslogodisplaytime 0 --biosbootmenu disabled --ostype Linux26_64 --cpus 1 --memory 1024 --acpi on --ioapic on --rtcuseutc on --natdnshostresolver1 off --natdnsproxy1 on --cpuhotplug off --pae on --hpet on --hwvirtex on --nestedpaging on --largepages on --vtxvpid on --accelerate3d off --boot1 disk"
+	if runtime.GOOS == "windows" && runtime.GOARCH == "386" {
+		modifyVMcommand += " --longmode on"
+	}
+	modifyVMcommand += " --uart1 0x3F8 4 --uartmode1 file path/machines/default/console.log"
+
+	driver := NewDriver("default", "path")
+	mockCalls(t, driver, []Call{
+		{"Generate path/machines/default/id_rsa", "", nil},
+		{"Download https://cloud-images.example.com/focal.img path/machines/default/cloudimage.img", "", nil},
+		{"vbm clonemedium disk path/machines/default/cloudimage.img path/machines/default/disk.vdi --format VDI", "", nil},
+		{"vbm closemedium disk path/machines/default/cloudimage.img --delete", "", nil},
+		{"vbm modifymedium disk path/machines/default/disk.vdi --resize 20000", "", nil},
+		{"MakeSeed default docker path/machines/default/id_rsa.pub path/machines/default/seed.iso", "", nil},
+		{"vbm createvm --basefolder path/machines/default --name default --register", "", nil},
+		{modifyVMcommand, "", nil},
+		{"vbm modifyvm default --nic1 nat --nictype1 82540EM --cableconnected1 on", "", nil},
+		{"vbm storagectl default --name SATA --add sata --hostiocache on", "", nil},
+		{"vbm storageattach default --storagectl SATA --port 0 --device 0 --type dvddrive --medium path/machines/default/seed.iso", "", nil},
+		{"vbm storageattach default --storagectl SATA --port 1 --device 0 --type hdd --medium path/machines/default/disk.vdi", "", nil},
+		{"vbm guestproperty set default /VirtualBox/GuestAdd/SharedFolders/MountPrefix /", "", nil},
+		{"vbm guestproperty set default /VirtualBox/GuestAdd/SharedFolders/MountDir /", "", nil},
+		{"vbm sharedfolder add default --name " + shareName + " --hostpath " + shareDir + " --automount", "", nil},
+		{"vbm setextradata default VBoxInternal2/SharedFoldersEnableSymlinksCreate/" + shareName + " 1", "", nil},
+	})
+	driver.Boot2DockerURL = ""
+	driver.CloudImage = "https://cloud-images.example.com/focal.img"
+
+	err := driver.CreateVM()
+
+	assert.NoError(t, err)
+}
+
+func TestSetConfigFromFlagsCloudImageWithBoot2Docker(t *testing.T) {
+	driver := newTestDriver("default")
+
+	checkFlags := &drivers.CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"virtualbox-cloud-image":     "focal.img",
+			"virtualbox-boot2docker-url": "boot2docker.iso",
+		},
+		CreateFlags: driver.GetCreateFlags(),
+	}
+
+	err := driver.SetConfigFromFlags(checkFlags)
+
+	assert.Equal(t, ErrCloudImageWithBoot2Docker, err)
+}
+
+func TestCloudImageExt(t *testing.T) {
+	assert.Equal(t, ".img", cloudImageExt("https://cloud-images.example.com/focal.img?version=1"))
+	assert.Equal(t, ".raw", cloudImageExt("/images/debian.RAW"))
+	assert.Equal(t, ".qcow2", cloudImageExt(`C:\images\debian.qcow2`))
+	assert.Equal(t, "", cloudImageExt("/images/debian"))
+}
+
 func TestStart(t *testing.T) {
 	driver := NewDriver("default", "path")
 	mockCalls(t, driver, []Call{
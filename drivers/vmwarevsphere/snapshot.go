@@ -0,0 +1,185 @@
+package vmwarevsphere
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+// CreateSnapshot takes a snapshot of the disks of the VM, without its memory.
+func (d *Driver) CreateSnapshot(name string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := d.vsphereLogin(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Logout(ctx)
+
+	vm, err := d.fetchVM(ctx, c, d.MachineName)
+	if err != nil {
+		return err
+	}
+
+	task, err := vm.CreateSnapshot(ctx, name, "Created by Docker Machine", false, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = task.WaitForResult(ctx, nil)
+	return err
+}
+
+// ListSnapshots returns the snapshots of the VM, oldest first.
+func (d *Driver) ListSnapshots() ([]drivers.Snapshot, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := d.vsphereLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Logout(ctx)
+
+	vm, err := d.fetchVM(ctx, c, d.MachineName)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := snapshotTree(ctx, vm)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []drivers.Snapshot{}
+	walkSnapshotTree(tree, func(s types.VirtualMachineSnapshotTree) {
+		snapshots = append(snapshots, drivers.Snapshot{
+			Name:    s.Name,
+			Created: s.CreateTime,
+		})
+	})
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot reverts the stopped VM to a snapshot, leaving it stopped.
+func (d *Driver) RestoreSnapshot(name string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := d.vsphereLogin(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Logout(ctx)
+
+	vm, err := d.fetchVM(ctx, c, d.MachineName)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := findSnapshot(ctx, vm, name)
+	if err != nil {
+		return err
+	}
+
+	res, err := methods.RevertToSnapshot_Task(ctx, vm.Client(), &types.RevertToSnapshot_Task{
+		This:            snapshot,
+		SuppressPowerOn: types.NewBool(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = object.NewTask(vm.Client(), res.Returnval).WaitForResult(ctx, nil)
+	return err
+}
+
+// RemoveSnapshot deletes a snapshot, keeping the snapshots taken from it.
+func (d *Driver) RemoveSnapshot(name string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := d.vsphereLogin(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Logout(ctx)
+
+	vm, err := d.fetchVM(ctx, c, d.MachineName)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := findSnapshot(ctx, vm, name)
+	if err != nil {
+		return err
+	}
+
+	res, err := methods.RemoveSnapshot_Task(ctx, vm.Client(), &types.RemoveSnapshot_Task{
+		This:           snapshot,
+		RemoveChildren: false,
+		Consolidate:    types.NewBool(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = object.NewTask(vm.Client(), res.Returnval).WaitForResult(ctx, nil)
+	return err
+}
+
+func snapshotTree(ctx context.Context, vm *object.VirtualMachine) ([]types.VirtualMachineSnapshotTree, error) {
+	var o mo.VirtualMachine
+
+	if err := vm.Properties(ctx, vm.Reference(), []string{"snapshot"}, &o); err != nil {
+		return nil, err
+	}
+
+	if o.Snapshot == nil {
+		return nil, nil
+	}
+
+	return o.Snapshot.RootSnapshotList, nil
+}
+
+func walkSnapshotTree(tree []types.VirtualMachineSnapshotTree, fn func(s types.VirtualMachineSnapshotTree)) {
+	for _, s := range tree {
+		fn(s)
+		walkSnapshotTree(s.ChildSnapshotList, fn)
+	}
+}
+
+func findSnapshot(ctx context.Context, vm *object.VirtualMachine, name string) (types.ManagedObjectReference, error) {
+	tree, err := snapshotTree(ctx, vm)
+	if err != nil {
+		return types.ManagedObjectReference{}, err
+	}
+
+	var found []types.ManagedObjectReference
+	walkSnapshotTree(tree, func(s types.VirtualMachineSnapshotTree) {
+		if s.Name == name {
+			found = append(found, s.Snapshot)
+		}
+	})
+
+	switch len(found) {
+	case 0:
+		return types.ManagedObjectReference{}, fmt.Errorf("Snapshot %s not found", name)
+	case 1:
+		return found[0], nil
+	default:
+		return types.ManagedObjectReference{}, fmt.Errorf("Several snapshots are named %s", name)
+	}
+}
@@ -0,0 +1,38 @@
+package vmwarevsphere
+
+import "golang.org/x/net/context"
+
+// Suspend saves the memory of the VM and stops it.
+func (d *Driver) Suspend() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := d.vsphereLogin(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Logout(ctx)
+
+	vm, err := d.fetchVM(ctx, c, d.MachineName)
+	if err != nil {
+		return err
+	}
+
+	task, err := vm.Suspend(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := task.WaitForResult(ctx, nil); err != nil {
+		return err
+	}
+
+	d.IPAddress = ""
+
+	return nil
+}
+
+// Resume powers the suspended VM on, Start knows how to start it.
+func (d *Driver) Resume() error {
+	return d.Start()
+}
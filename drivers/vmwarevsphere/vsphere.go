@@ -126,6 +126,7 @@ func (d *Driver) GetCreateFlags() []mcnflag.Flag {
 			EnvVar: "VSPHERE_PASSWORD",
 			Name:   "vmwarevsphere-password",
 			Usage:  "vSphere password",
+			Secret: true,
 		},
 		mcnflag.StringSliceFlag{
 			EnvVar: "VSPHERE_NETWORK",
@@ -318,6 +319,8 @@ func (d *Driver) GetState() (state.State, error) {
 		return state.Running, nil
 	} else if strings.Contains(string(s.Runtime.PowerState), "poweredOff") {
 		return state.Stopped, nil
+	} else if strings.Contains(string(s.Runtime.PowerState), "suspended") {
+		return state.Saved, nil
 	}
 	return state.None, nil
 }
@@ -726,7 +729,7 @@ func (d *Driver) Start() error {
 	case state.Running:
 		log.Infof("VM %s has already been started", d.MachineName)
 		return nil
-	case state.Stopped:
+	case state.Stopped, state.Saved:
 		// TODO add transactional or error handling in the following steps
 		// Create context
 		ctx, cancel := context.WithCancel(context.Background())
@@ -40,7 +40,7 @@ type ConnChecker interface {
 type MachineConnChecker struct{}
 
 func (mcc *MachineConnChecker) Check(h *host.Host, swarm bool) (string, *auth.Options, error) {
-	dockerHost, err := h.Driver.GetURL()
+	dockerHost, err := h.URL()
 	if err != nil {
 		return "", &auth.Options{}, err
 	}
@@ -0,0 +1,283 @@
+package cloudinit
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	isoSectorSize = 2048
+
+	isoPrimaryDescriptorType       = 1
+	isoSupplementaryDescriptorType = 2
+	isoTerminatorDescriptorType    = 255
+
+	// The layout of the ISO: the system area, the volume descriptors, the
+	// path tables and root directory of each volume, then the files.
+	isoPrimaryDescriptorSector = 16
+	isoJolietDescriptorSector  = 17
+	isoTerminatorSector        = 18
+	isoFirstFileSector         = 25
+
+	isoPathTableSize = 10
+
+	// isoPaddingSectors are appended to the image like mkisofs does, the
+	// read-ahead of some readers fails on smaller images.
+	isoPaddingSectors = 150
+)
+
+var (
+	// isoPrimaryVolume has ISO 9660 names, e.g. USER-DATA.;1, shown in
+	// lower case by Linux.
+	isoPrimaryVolume = isoVolume{
+		descriptorType: isoPrimaryDescriptorType,
+		lPathTable:     19,
+		mPathTable:     20,
+		rootDir:        21,
+	}
+
+	// isoJolietVolume has the names as is, in UCS-2.
+	isoJolietVolume = isoVolume{
+		descriptorType: isoSupplementaryDescriptorType,
+		lPathTable:     22,
+		mPathTable:     23,
+		rootDir:        24,
+		joliet:         true,
+	}
+)
+
+// isoFile is a file of the root directory of an ISO.
+type isoFile struct {
+	Name string
+	Data []byte
+}
+
+// isoVolume is a volume descriptor and the directory tree it describes, the
+// root directory only.
+type isoVolume struct {
+	descriptorType byte
+	lPathTable     uint32
+	mPathTable     uint32
+	rootDir        uint32
+	joliet         bool
+}
+
+// writeISO writes an ISO 9660 image with the files in its root directory,
+// with the Joliet extension so the files keep their names.
+func writeISO(w io.Writer, volumeID string, files []isoFile) error {
+	sectors := map[string]uint32{}
+	sector := uint32(isoFirstFileSector)
+	for _, file := range files {
+		sectors[file.Name] = sector
+		sector += isoSectors(len(file.Data))
+	}
+	sector += isoPaddingSectors
+
+	now := time.Now().UTC()
+
+	image := make([]byte, isoFirstFileSector*isoSectorSize)
+	copy(image[isoTerminatorSector*isoSectorSize:], isoDescriptorHeader(isoTerminatorDescriptorType))
+
+	for descriptorSector, volume := range map[uint32]isoVolume{
+		isoPrimaryDescriptorSector: isoPrimaryVolume,
+		isoJolietDescriptorSector:  isoJolietVolume,
+	} {
+		root, err := volume.rootDirectory(files, sectors, now)
+		if err != nil {
+			return err
+		}
+
+		copy(image[descriptorSector*isoSectorSize:], volume.descriptor(volumeID, sector, now))
+		copy(image[volume.lPathTable*isoSectorSize:], volume.pathTable(binary.LittleEndian))
+		copy(image[volume.mPathTable*isoSectorSize:], volume.pathTable(binary.BigEndian))
+		copy(image[volume.rootDir*isoSectorSize:], root)
+	}
+
+	if _, err := w.Write(image); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		padded := make([]byte, isoSectors(len(file.Data))*isoSectorSize)
+		copy(padded, file.Data)
+
+		if _, err := w.Write(padded); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(make([]byte, isoPaddingSectors*isoSectorSize))
+	return err
+}
+
+// filename returns the identifier of a file in the volume.
+func (v isoVolume) filename(name string) []byte {
+	if v.joliet {
+		return ucs2(name)
+	}
+
+	name = strings.ToUpper(name)
+	if !strings.Contains(name, ".") {
+		name += "."
+	}
+
+	return []byte(name + ";1")
+}
+
+// rootDirectory returns the root directory, which has to hold in a sector.
+func (v isoVolume) rootDirectory(files []isoFile, sectors map[string]uint32, now time.Time) ([]byte, error) {
+	files = append([]isoFile{}, files...)
+	sort.Slice(files, func(i, j int) bool {
+		return string(v.filename(files[i].Name)) < string(v.filename(files[j].Name))
+	})
+
+	dir := isoDirRecord(v.rootDir, isoSectorSize, true, []byte{0}, now)
+	dir = append(dir, isoDirRecord(v.rootDir, isoSectorSize, true, []byte{1}, now)...)
+	for _, file := range files {
+		dir = append(dir, isoDirRecord(sectors[file.Name], uint32(len(file.Data)), false, v.filename(file.Name), now)...)
+	}
+
+	if len(dir) > isoSectorSize {
+		return nil, fmt.Errorf("Too many files for the root directory of the ISO")
+	}
+
+	return dir, nil
+}
+
+func (v isoVolume) descriptor(volumeID string, sectors uint32, now time.Time) []byte {
+	d := make([]byte, isoSectorSize)
+	copy(d, isoDescriptorHeader(v.descriptorType))
+
+	copy(d[8:40], v.padded("", 32))
+	copy(d[40:72], v.padded(volumeID, 32))
+	putBothEndian32(d[80:88], sectors)
+	if v.joliet {
+		// UCS-2 level 3
+		copy(d[88:91], "%/E")
+	}
+	putBothEndian16(d[120:124], 1)
+	putBothEndian16(d[124:128], 1)
+	putBothEndian16(d[128:132], isoSectorSize)
+	putBothEndian32(d[132:140], isoPathTableSize)
+	binary.LittleEndian.PutUint32(d[140:144], v.lPathTable)
+	binary.BigEndian.PutUint32(d[148:152], v.mPathTable)
+	copy(d[156:190], isoDirRecord(v.rootDir, isoSectorSize, true, []byte{0}, now))
+
+	// volume set, publisher, data preparer and application
+	copy(d[190:318], v.padded("", 128))
+	copy(d[318:446], v.padded("", 128))
+	copy(d[446:574], v.padded("", 128))
+	copy(d[574:702], v.padded("docker-machine", 128))
+	// copyright, abstract and bibliographic files
+	copy(d[702:813], v.padded("", 111))
+
+	copy(d[813:830], isoDate(now))
+	copy(d[830:847], isoDate(now))
+	copy(d[847:864], isoDate(time.Time{}))
+	copy(d[864:881], isoDate(now))
+	d[881] = 1
+
+	return d
+}
+
+func (v isoVolume) pathTable(order binary.ByteOrder) []byte {
+	t := make([]byte, isoPathTableSize)
+	t[0] = 1
+	order.PutUint32(t[2:6], v.rootDir)
+	order.PutUint16(t[6:8], 1)
+
+	return t
+}
+
+// padded returns the string padded with spaces, in UCS-2 for Joliet.
+func (v isoVolume) padded(s string, length int) []byte {
+	if v.joliet {
+		return ucs2(fmt.Sprintf("%-*s", length/2, s)[:length/2])
+	}
+
+	return []byte(fmt.Sprintf("%-*s", length, s)[:length])
+}
+
+func isoSectors(size int) uint32 {
+	sectors := uint32((size + isoSectorSize - 1) / isoSectorSize)
+	if sectors == 0 {
+		// even empty files get their sector
+		return 1
+	}
+
+	return sectors
+}
+
+func isoDescriptorHeader(descriptorType byte) []byte {
+	header := make([]byte, 7)
+	header[0] = descriptorType
+	copy(header[1:], "CD001")
+	header[6] = 1
+
+	return header
+}
+
+func isoDirRecord(sector, size uint32, dir bool, name []byte, now time.Time) []byte {
+	length := 33 + len(name)
+	if length%2 == 1 {
+		length++
+	}
+
+	r := make([]byte, length)
+	r[0] = byte(length)
+	putBothEndian32(r[2:10], sector)
+	putBothEndian32(r[10:18], size)
+	r[18] = byte(now.Year() - 1900)
+	r[19] = byte(now.Month())
+	r[20] = byte(now.Day())
+	r[21] = byte(now.Hour())
+	r[22] = byte(now.Minute())
+	r[23] = byte(now.Second())
+	if dir {
+		r[25] = 2
+	}
+	putBothEndian16(r[28:32], 1)
+	r[32] = byte(len(name))
+	copy(r[33:], name)
+
+	return r
+}
+
+// isoDate returns a date of a volume descriptor, zero for an unset date.
+func isoDate(t time.Time) []byte {
+	d := make([]byte, 17)
+	if t.IsZero() {
+		copy(d, strings.Repeat("0", 16))
+		return d
+	}
+
+	copy(d, t.Format("20060102150405")+"00")
+	return d
+}
+
+// ucs2 returns the string in big-endian UCS-2.
+func ucs2(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+
+	b := make([]byte, 2*len(encoded))
+	for i, c := range encoded {
+		binary.BigEndian.PutUint16(b[2*i:], c)
+	}
+
+	return b
+}
+
+func putBothEndian16(b []byte, v uint16) {
+	binary.LittleEndian.PutUint16(b[0:2], v)
+	binary.BigEndian.PutUint16(b[2:4], v)
+}
+
+func putBothEndian32(b []byte, v uint32) {
+	binary.LittleEndian.PutUint32(b[0:4], v)
+	binary.BigEndian.PutUint32(b[4:8], v)
+}
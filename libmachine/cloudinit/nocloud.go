@@ -0,0 +1,102 @@
+package cloudinit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	// SeedVolumeID is the label cloud-init looks for to find a NoCloud
+	// seed.
+	SeedVolumeID = "cidata"
+
+	userDataFilename      = "user-data"
+	metaDataFilename      = "meta-data"
+	networkConfigFilename = "network-config"
+)
+
+// NoCloudSeed is the configuration given to cloud-init on first boot by the
+// NoCloud data source: the hostname of the machine and the user it's
+// provisioned with.
+type NoCloudSeed struct {
+	InstanceID        string
+	Hostname          string
+	User              string
+	SSHAuthorizedKeys []string
+}
+
+// UserData returns the cloud-config creating the user, allowed to sudo
+// without password and to log in with the keys only.
+func (s *NoCloudSeed) UserData() []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintln(buf, "#cloud-config")
+	fmt.Fprintf(buf, "hostname: %s\n", quote(s.Hostname))
+	fmt.Fprintln(buf, "ssh_pwauth: false")
+	fmt.Fprintln(buf, "users:")
+	fmt.Fprintf(buf, "  - name: %s\n", quote(s.User))
+	fmt.Fprintln(buf, `    sudo: "ALL=(ALL) NOPASSWD:ALL"`)
+	fmt.Fprintln(buf, "    shell: /bin/bash")
+	fmt.Fprintln(buf, "    lock_passwd: true")
+	fmt.Fprintln(buf, "    ssh_authorized_keys:")
+	for _, key := range s.SSHAuthorizedKeys {
+		fmt.Fprintf(buf, "      - %s\n", quote(key))
+	}
+
+	return buf.Bytes()
+}
+
+// MetaData returns the meta-data identifying the instance.
+func (s *NoCloudSeed) MetaData() []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "instance-id: %s\n", quote(s.InstanceID))
+	fmt.Fprintf(buf, "local-hostname: %s\n", quote(s.Hostname))
+
+	return buf.Bytes()
+}
+
+// NetworkConfig returns the version 2 network configuration running DHCP
+// on every ethernet interface. Without it cloud-init only brings up the
+// first one, leaving a host-only adapter unconfigured.
+func (s *NoCloudSeed) NetworkConfig() []byte {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintln(buf, "version: 2")
+	fmt.Fprintln(buf, "ethernets:")
+	fmt.Fprintln(buf, "  all:")
+	fmt.Fprintln(buf, "    match:")
+	fmt.Fprintln(buf, `      name: "e*"`)
+	fmt.Fprintln(buf, "    dhcp4: true")
+
+	return buf.Bytes()
+}
+
+// WriteISO writes the seed as an ISO labelled cidata, to be attached to the
+// machine as a CD-ROM.
+func (s *NoCloudSeed) WriteISO(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := writeISO(f, SeedVolumeID, []isoFile{
+		{Name: metaDataFilename, Data: s.MetaData()},
+		{Name: userDataFilename, Data: s.UserData()},
+		{Name: networkConfigFilename, Data: s.NetworkConfig()},
+	}); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// quote returns the string as a YAML double-quoted scalar, JSON strings
+// being valid YAML.
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
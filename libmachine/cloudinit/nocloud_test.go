@@ -0,0 +1,125 @@
+package cloudinit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSeed() *NoCloudSeed {
+	return &NoCloudSeed{
+		InstanceID:        "default",
+		Hostname:          "default",
+		User:              "docker",
+		SSHAuthorizedKeys: []string{"ssh-rsa AAAAB3NzaC1yc2E user@host"},
+	}
+}
+
+// readISOFiles returns the files of the root directory of a volume of an
+// ISO, by their names as Linux shows them.
+func readISOFiles(image []byte, descriptorSector int) map[string][]byte {
+	joliet := image[descriptorSector*isoSectorSize] == isoSupplementaryDescriptorType
+	root := binary.LittleEndian.Uint32(image[descriptorSector*isoSectorSize+156+2:])
+	dir := image[root*isoSectorSize : (root+1)*isoSectorSize]
+
+	files := map[string][]byte{}
+	for offset := 0; offset < len(dir) && dir[offset] != 0; offset += int(dir[offset]) {
+		record := dir[offset:]
+		name := string(record[33 : 33+record[32]])
+		if record[25]&2 != 0 {
+			continue
+		}
+
+		sector := binary.LittleEndian.Uint32(record[2:])
+		size := binary.LittleEndian.Uint32(record[10:])
+		if joliet {
+			name = string(utf16.Decode(ucs2Decode(record[33 : 33+record[32]])))
+		} else {
+			name = strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(name, ";1"), "."))
+		}
+		files[name] = image[sector*isoSectorSize : sector*isoSectorSize+size]
+	}
+
+	return files
+}
+
+func ucs2Decode(b []byte) []uint16 {
+	decoded := make([]uint16, len(b)/2)
+	for i := range decoded {
+		decoded[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+
+	return decoded
+}
+
+func TestUserData(t *testing.T) {
+	userData := string(newTestSeed().UserData())
+
+	assert.True(t, strings.HasPrefix(userData, "#cloud-config\n"))
+	assert.Contains(t, userData, `hostname: "default"`)
+	assert.Contains(t, userData, `  - name: "docker"`)
+	assert.Contains(t, userData, `      - "ssh-rsa AAAAB3NzaC1yc2E user@host"`)
+}
+
+func TestMetaData(t *testing.T) {
+	assert.Equal(t, "instance-id: \"default\"\nlocal-hostname: \"default\"\n", string(newTestSeed().MetaData()))
+}
+
+func TestNetworkConfig(t *testing.T) {
+	assert.Equal(t, "version: 2\nethernets:\n  all:\n    match:\n      name: \"e*\"\n    dhcp4: true\n", string(newTestSeed().NetworkConfig()))
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"a \"quoted\": value"`, quote(`a "quoted": value`))
+}
+
+func TestWriteISO(t *testing.T) {
+	dir, err := ioutil.TempDir("", "machine-cloudinit-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	seed := newTestSeed()
+	seedPath := filepath.Join(dir, "seed.iso")
+
+	assert.NoError(t, seed.WriteISO(seedPath))
+
+	image, err := ioutil.ReadFile(seedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(image)%isoSectorSize)
+
+	descriptor := image[isoPrimaryDescriptorSector*isoSectorSize:]
+	assert.Equal(t, "CD001", string(descriptor[1:6]))
+	assert.Equal(t, SeedVolumeID, strings.TrimSpace(string(descriptor[40:72])))
+	assert.Equal(t, uint32(len(image)/isoSectorSize), binary.LittleEndian.Uint32(descriptor[80:]))
+	assert.Equal(t, byte(isoTerminatorDescriptorType), image[isoTerminatorSector*isoSectorSize])
+
+	joliet := image[isoJolietDescriptorSector*isoSectorSize:]
+	assert.Equal(t, "CD001", string(joliet[1:6]))
+	assert.Equal(t, "%/E", string(joliet[88:91]))
+
+	for _, descriptorSector := range []int{isoPrimaryDescriptorSector, isoJolietDescriptorSector} {
+		files := readISOFiles(image, descriptorSector)
+		assert.Len(t, files, 3)
+		assert.Equal(t, seed.UserData(), files[userDataFilename])
+		assert.Equal(t, seed.MetaData(), files[metaDataFilename])
+		assert.Equal(t, seed.NetworkConfig(), files[networkConfigFilename])
+	}
+}
+
+func TestWriteISOTooManyFiles(t *testing.T) {
+	files := []isoFile{}
+	for i := 0; i < 100; i++ {
+		files = append(files, isoFile{Name: strings.Repeat("f", 20) + string(rune('a'+i%26)) + string(rune('a'+i/26))})
+	}
+
+	err := writeISO(&bytes.Buffer{}, SeedVolumeID, files)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package drivers
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Capability names an optional operation, which a driver supports by
+// implementing an interface in addition to Driver. The interface of Driver
+// itself can't grow without breaking every driver plugin built against it.
+type Capability string
+
+// CapabilityReporter is implemented by drivers which stand for another
+// driver, e.g. over the plugin RPC. They implement every optional interface
+// on its behalf, so they report the capabilities of the driver behind them
+// instead of being checked with type assertions.
+type CapabilityReporter interface {
+	Capabilities() []Capability
+}
+
+// ErrCapabilityNotSupported is returned when an optional operation is used
+// on a driver which doesn't support it.
+type ErrCapabilityNotSupported struct {
+	DriverName string
+	Capability Capability
+}
+
+func (e ErrCapabilityNotSupported) Error() string {
+	return fmt.Sprintf("Driver %q does not support %s", e.DriverName, e.Capability)
+}
+
+type capabilityCheck struct {
+	name        Capability
+	implemented func(d Driver) bool
+}
+
+var (
+	capabilityChecks     []capabilityCheck
+	capabilityChecksLock sync.Mutex
+)
+
+// RegisterCapability declares an optional capability. implemented reports
+// whether a driver implements the interface of the capability.
+func RegisterCapability(name Capability, implemented func(d Driver) bool) {
+	capabilityChecksLock.Lock()
+	defer capabilityChecksLock.Unlock()
+
+	capabilityChecks = append(capabilityChecks, capabilityCheck{
+		name:        name,
+		implemented: implemented,
+	})
+}
+
+// Capabilities returns the optional capabilities supported by d, in the
+// order they were registered.
+func Capabilities(d Driver) []Capability {
+	if reporter, ok := d.(CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+
+	capabilityChecksLock.Lock()
+	defer capabilityChecksLock.Unlock()
+
+	capabilities := []Capability{}
+	for _, check := range capabilityChecks {
+		if check.implemented(d) {
+			capabilities = append(capabilities, check.name)
+		}
+	}
+
+	return capabilities
+}
+
+// HasCapability reports whether d supports the capability name. It must be
+// checked before type asserting d to the interface of the capability.
+func HasCapability(d Driver, name Capability) bool {
+	for _, capability := range Capabilities(d) {
+		if capability == name {
+			return true
+		}
+	}
+
+	return false
+}
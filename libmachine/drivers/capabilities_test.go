@@ -0,0 +1,81 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCapability Capability = "test"
+
+type testCapabilityDriver interface {
+	TestCapability() error
+}
+
+type capableDriver struct {
+	*MockDriver
+}
+
+func (d *capableDriver) TestCapability() error {
+	return nil
+}
+
+type reportingDriver struct {
+	*MockDriver
+	capabilities []Capability
+}
+
+func (d *reportingDriver) Capabilities() []Capability {
+	return d.capabilities
+}
+
+func registerTestCapability() func() {
+	saved := capabilityChecks
+	RegisterCapability(testCapability, func(d Driver) bool {
+		_, ok := d.(testCapabilityDriver)
+		return ok
+	})
+
+	return func() {
+		capabilityChecks = saved
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	defer registerTestCapability()()
+
+	assert.Equal(t, []Capability{testCapability}, Capabilities(&capableDriver{&MockDriver{}}))
+	assert.True(t, HasCapability(&capableDriver{&MockDriver{}}, testCapability))
+
+	assert.Equal(t, []Capability{}, Capabilities(&MockDriver{}))
+	assert.False(t, HasCapability(&MockDriver{}, testCapability))
+}
+
+func TestCapabilitiesReporter(t *testing.T) {
+	defer registerTestCapability()()
+
+	driver := &reportingDriver{
+		MockDriver:   &MockDriver{},
+		capabilities: []Capability{"other"},
+	}
+
+	assert.Equal(t, []Capability{"other"}, Capabilities(driver))
+	assert.False(t, HasCapability(driver, testCapability))
+}
+
+func TestSerialDriverCapabilities(t *testing.T) {
+	defer registerTestCapability()()
+
+	driver := newSerialDriverWithLock(&capableDriver{&MockDriver{}}, &MockLocker{calls: &CallRecorder{}})
+
+	assert.Equal(t, []Capability{testCapability}, Capabilities(driver))
+}
+
+func TestErrCapabilityNotSupported(t *testing.T) {
+	err := ErrCapabilityNotSupported{
+		DriverName: "generic",
+		Capability: "snapshot",
+	}
+
+	assert.EqualError(t, err, `Driver "generic" does not support snapshot`)
+}
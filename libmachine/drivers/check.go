@@ -1,6 +1,10 @@
 package drivers
 
-import "github.com/docker/machine/libmachine/mcnflag"
+import (
+	"time"
+
+	"github.com/docker/machine/libmachine/mcnflag"
+)
 
 // CheckDriverOptions implements DriverOptions and is used to validate flag parsing
 type CheckDriverOptions struct {
@@ -12,8 +16,13 @@ type CheckDriverOptions struct {
 func (o *CheckDriverOptions) String(key string) string {
 	for _, flag := range o.CreateFlags {
 		if flag.String() == key {
-			f, ok := flag.(mcnflag.StringFlag)
-			if !ok {
+			var defaultValue string
+			switch f := flag.(type) {
+			case mcnflag.StringFlag:
+				defaultValue = f.Value
+			case mcnflag.EnumFlag:
+				defaultValue = f.Value
+			default:
 				o.InvalidFlags = append(o.InvalidFlags, flag.String())
 			}
 
@@ -21,7 +30,7 @@ func (o *CheckDriverOptions) String(key string) string {
 			if present {
 				return value
 			}
-			return f.Value
+			return defaultValue
 		}
 	}
 
@@ -82,3 +91,60 @@ func (o *CheckDriverOptions) Bool(key string) bool {
 	}
 	return false
 }
+
+func (o *CheckDriverOptions) Duration(key string) time.Duration {
+	for _, flag := range o.CreateFlags {
+		if flag.String() == key {
+			f, ok := flag.(mcnflag.DurationFlag)
+			if !ok {
+				o.InvalidFlags = append(o.InvalidFlags, flag.String())
+			}
+
+			value, present := o.FlagsValues[key].(time.Duration)
+			if present {
+				return value
+			}
+			return f.Value
+		}
+	}
+
+	return 0
+}
+
+func (o *CheckDriverOptions) Float(key string) float64 {
+	for _, flag := range o.CreateFlags {
+		if flag.String() == key {
+			f, ok := flag.(mcnflag.FloatFlag)
+			if !ok {
+				o.InvalidFlags = append(o.InvalidFlags, flag.String())
+			}
+
+			value, present := o.FlagsValues[key].(float64)
+			if present {
+				return value
+			}
+			return f.Value
+		}
+	}
+
+	return 0
+}
+
+func (o *CheckDriverOptions) Map(key string) map[string]string {
+	for _, flag := range o.CreateFlags {
+		if flag.String() == key {
+			f, ok := flag.(mcnflag.MapFlag)
+			if !ok {
+				o.InvalidFlags = append(o.InvalidFlags, flag.String())
+			}
+
+			value, present := o.FlagsValues[key].(map[string]string)
+			if present {
+				return value
+			}
+			return f.Value
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package drivers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryBackoff = time.Second
+
+	// maxRetryBackoff caps the doubling of the retry backoff.
+	maxRetryBackoff = time.Minute
+)
+
+// ConcurrencyPolicy limits the calls made to the drivers of a name, across
+// all the machines using them.
+type ConcurrencyPolicy struct {
+	// MaxParallel is how many calls run at once, unlimited when 0.
+	MaxParallel int
+
+	// RateLimit is how many calls start per second, unlimited when 0.
+	RateLimit float64
+
+	// MaxRetries is how many times a call failing with a throttling
+	// error is retried.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled at each
+	// retry up to a minute. It's a second when 0.
+	RetryBackoff time.Duration
+
+	// ThrottlingErrors are parts of the messages of the errors returned
+	// when the provider throttles the calls.
+	ThrottlingErrors []string
+}
+
+// ConcurrencyOverride holds the fields of a concurrency policy set by the
+// user, which replace the ones declared by the driver. The fields left unset
+// are nil, so that a field can be overridden with 0.
+type ConcurrencyOverride struct {
+	MaxParallel  *int
+	RateLimit    *float64
+	MaxRetries   *int
+	RetryBackoff *time.Duration
+}
+
+// ConcurrencyPolicer is implemented by drivers declaring the concurrency
+// policy they need.
+type ConcurrencyPolicer interface {
+	ConcurrencyPolicy() ConcurrencyPolicy
+}
+
+// DefaultConcurrencyPolicies are the policies of the drivers, by name, when
+// they don't declare one. VirtualBox scrapes up against its own locking
+// mechanisms when it's driven by several processes at once.
+var DefaultConcurrencyPolicies = map[string]ConcurrencyPolicy{
+	"virtualbox": {MaxParallel: 1},
+}
+
+// GetConcurrencyPolicy returns the policy declared by the driver, or the
+// default one of its name.
+func GetConcurrencyPolicy(d Driver) ConcurrencyPolicy {
+	if policer, ok := d.(ConcurrencyPolicer); ok {
+		return policer.ConcurrencyPolicy()
+	}
+
+	return DefaultConcurrencyPolicies[d.DriverName()]
+}
+
+// Override returns the policy with the fields set in override replacing its
+// own.
+func (p ConcurrencyPolicy) Override(override ConcurrencyOverride) ConcurrencyPolicy {
+	if override.MaxParallel != nil {
+		p.MaxParallel = *override.MaxParallel
+	}
+	if override.RateLimit != nil {
+		p.RateLimit = *override.RateLimit
+	}
+	if override.MaxRetries != nil {
+		p.MaxRetries = *override.MaxRetries
+	}
+	if override.RetryBackoff != nil {
+		p.RetryBackoff = *override.RetryBackoff
+	}
+
+	return p
+}
+
+// IsThrottlingError tells whether the error is one of the throttling errors
+// of the policy.
+func (p ConcurrencyPolicy) IsThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, throttlingError := range p.ThrottlingErrors {
+		if strings.Contains(err.Error(), throttlingError) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseConcurrencyOverrides parses overrides of the concurrency policies given
+// as <driver>:<key>=<value>[:<key>=<value>...], with the keys parallel, rate,
+// retries and backoff, e.g. amazonec2:parallel=5:rate=2:retries=3:backoff=2s.
+// The values can't be negative, 0 means unlimited for parallel and rate.
+func ParseConcurrencyOverrides(specs []string) (map[string]ConcurrencyOverride, error) {
+	overrides := map[string]ConcurrencyOverride{}
+
+	for _, spec := range specs {
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid concurrency policy %q, expected <driver>:<key>=<value>", spec)
+		}
+
+		override := overrides[parts[0]]
+		for _, setting := range parts[1:] {
+			keyValue := strings.SplitN(setting, "=", 2)
+			if len(keyValue) != 2 {
+				return nil, fmt.Errorf("Invalid concurrency setting %q, expected <key>=<value>", setting)
+			}
+
+			var err error
+			switch key, value := keyValue[0], keyValue[1]; key {
+			case "parallel":
+				var parallel int
+				parallel, err = parseCount(value)
+				override.MaxParallel = &parallel
+			case "rate":
+				var rate float64
+				if rate, err = strconv.ParseFloat(value, 64); err == nil && (rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0)) {
+					err = fmt.Errorf("the rate must be a positive number or 0")
+				}
+				override.RateLimit = &rate
+			case "retries":
+				var retries int
+				retries, err = parseCount(value)
+				override.MaxRetries = &retries
+			case "backoff":
+				var backoff time.Duration
+				if backoff, err = time.ParseDuration(value); err == nil && backoff < 0 {
+					err = fmt.Errorf("the backoff can't be negative")
+				}
+				override.RetryBackoff = &backoff
+			default:
+				err = fmt.Errorf("unknown key %q, use parallel, rate, retries or backoff", key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Invalid concurrency setting %q: %s", setting, err)
+			}
+		}
+
+		overrides[parts[0]] = override
+	}
+
+	return overrides, nil
+}
+
+// parseCount parses a number of calls, which can't be negative.
+func parseCount(value string) (int, error) {
+	count, err := strconv.Atoi(value)
+	if err == nil && count < 0 {
+		err = fmt.Errorf("%d is negative", count)
+	}
+
+	return count, err
+}
+
+// Limiter applies a concurrency policy to the calls made through it.
+type Limiter struct {
+	Policy ConcurrencyPolicy
+
+	slots chan struct{}
+
+	lock      sync.Mutex
+	nextStart time.Time
+
+	// sleep is replaced in tests
+	sleep func(time.Duration)
+}
+
+func NewLimiter(policy ConcurrencyPolicy) *Limiter {
+	l := &Limiter{
+		Policy: policy,
+		sleep:  time.Sleep,
+	}
+
+	if policy.MaxParallel > 0 {
+		l.slots = make(chan struct{}, policy.MaxParallel)
+	}
+
+	return l
+}
+
+// Acquire waits for one of the parallel calls allowed by the policy.
+func (l *Limiter) Acquire() {
+	if l.slots != nil {
+		l.slots <- struct{}{}
+	}
+}
+
+// Release frees the call acquired.
+func (l *Limiter) Release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// waitForRate waits until the rate limit of the policy lets a call start.
+func (l *Limiter) waitForRate() {
+	if l.Policy.RateLimit <= 0 {
+		return
+	}
+
+	l.lock.Lock()
+	now := time.Now()
+	start := l.nextStart
+	if start.Before(now) {
+		start = now
+	}
+	l.nextStart = start.Add(time.Duration(float64(time.Second) / l.Policy.RateLimit))
+	l.lock.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		l.sleep(wait)
+	}
+}
+
+// DoOnce makes the call once allowed by the policy, without retrying it.
+// It's meant for calls that can't be made twice safely.
+func (l *Limiter) DoOnce(call func() error) error {
+	l.Acquire()
+	defer l.Release()
+
+	l.waitForRate()
+	return call()
+}
+
+// Do makes the call once allowed by the policy, retrying it with a backoff
+// while it fails with a throttling error.
+func (l *Limiter) Do(call func() error) error {
+	backoff := l.Policy.RetryBackoff
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for retry := 0; ; retry++ {
+		l.Acquire()
+		l.waitForRate()
+		err := call()
+		l.Release()
+
+		if retry >= l.Policy.MaxRetries || !l.Policy.IsThrottlingError(err) {
+			return err
+		}
+
+		l.sleep(retryBackoff(backoff, retry))
+	}
+}
+
+// retryBackoff returns the delay before the retry following the given one,
+// doubling backoff at each retry up to maxRetryBackoff, or up to backoff when
+// it's longer already.
+func retryBackoff(backoff time.Duration, retry int) time.Duration {
+	limit := maxRetryBackoff
+	if backoff > limit {
+		limit = backoff
+	}
+
+	for i := 0; i < retry && backoff < limit; i++ {
+		backoff *= 2
+	}
+
+	if backoff > limit {
+		return limit
+	}
+	return backoff
+}
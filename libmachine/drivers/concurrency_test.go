@@ -0,0 +1,206 @@
+package drivers
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type policedDriver struct {
+	*MockDriver
+}
+
+func (d *policedDriver) ConcurrencyPolicy() ConcurrencyPolicy {
+	return ConcurrencyPolicy{MaxParallel: 3}
+}
+
+func TestGetConcurrencyPolicy(t *testing.T) {
+	assert.Equal(t, ConcurrencyPolicy{MaxParallel: 3}, GetConcurrencyPolicy(&policedDriver{&MockDriver{}}))
+	assert.Equal(t, ConcurrencyPolicy{MaxParallel: 1}, GetConcurrencyPolicy(&MockDriver{calls: &CallRecorder{}, driverName: "virtualbox"}))
+	assert.Equal(t, ConcurrencyPolicy{}, GetConcurrencyPolicy(&MockDriver{calls: &CallRecorder{}, driverName: "amazonec2"}))
+}
+
+func TestParseConcurrencyOverrides(t *testing.T) {
+	overrides, err := ParseConcurrencyOverrides([]string{
+		"amazonec2:parallel=5:rate=2.5:retries=3:backoff=2s",
+		"virtualbox:parallel=2",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, overrides, 2)
+	assert.Equal(t, ConcurrencyPolicy{
+		MaxParallel:  5,
+		RateLimit:    2.5,
+		MaxRetries:   3,
+		RetryBackoff: 2 * time.Second,
+	}, ConcurrencyPolicy{}.Override(overrides["amazonec2"]))
+	assert.Equal(t, ConcurrencyPolicy{MaxParallel: 2, MaxRetries: 1}, ConcurrencyPolicy{MaxParallel: 1, MaxRetries: 1}.Override(overrides["virtualbox"]))
+}
+
+func TestParseConcurrencyOverridesUnlimited(t *testing.T) {
+	overrides, err := ParseConcurrencyOverrides([]string{"virtualbox:parallel=0"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ConcurrencyPolicy{}, ConcurrencyPolicy{MaxParallel: 1}.Override(overrides["virtualbox"]))
+}
+
+func TestParseConcurrencyOverridesInvalid(t *testing.T) {
+	for _, spec := range []string{"amazonec2", ":parallel=1", "amazonec2:parallel", "amazonec2:parallel=x", "amazonec2:unknown=1",
+		"amazonec2:parallel=-1", "amazonec2:rate=-2", "amazonec2:rate=NaN", "amazonec2:retries=-3", "amazonec2:backoff=-1s"} {
+		_, err := ParseConcurrencyOverrides([]string{spec})
+
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestConcurrencyPolicyOverride(t *testing.T) {
+	policy := ConcurrencyPolicy{
+		MaxParallel:      1,
+		MaxRetries:       5,
+		ThrottlingErrors: []string{"Throttling"},
+	}
+
+	parallel, rate := 4, 2.0
+	overridden := policy.Override(ConcurrencyOverride{MaxParallel: &parallel, RateLimit: &rate})
+
+	assert.Equal(t, ConcurrencyPolicy{
+		MaxParallel:      4,
+		RateLimit:        2,
+		MaxRetries:       5,
+		ThrottlingErrors: []string{"Throttling"},
+	}, overridden)
+}
+
+func TestIsThrottlingError(t *testing.T) {
+	policy := ConcurrencyPolicy{ThrottlingErrors: []string{"RequestLimitExceeded"}}
+
+	assert.True(t, policy.IsThrottlingError(errors.New("RequestLimitExceeded: Request limit exceeded.")))
+	assert.False(t, policy.IsThrottlingError(errors.New("InvalidAMIID.NotFound")))
+	assert.False(t, policy.IsThrottlingError(nil))
+}
+
+func TestLimiterMaxParallel(t *testing.T) {
+	limiter := NewLimiter(ConcurrencyPolicy{MaxParallel: 2})
+
+	var (
+		lock    sync.Mutex
+		running int
+		maxSeen int
+		wg      sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.Do(func() error {
+				lock.Lock()
+				running++
+				if running > maxSeen {
+					maxSeen = running
+				}
+				lock.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				lock.Lock()
+				running--
+				lock.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, maxSeen)
+}
+
+func TestLimiterRetries(t *testing.T) {
+	limiter := NewLimiter(ConcurrencyPolicy{
+		MaxRetries:       2,
+		RetryBackoff:     time.Second,
+		ThrottlingErrors: []string{"Throttling"},
+	})
+	sleeps := []time.Duration{}
+	limiter.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	}
+
+	calls := 0
+	err := limiter.Do(func() error {
+		calls++
+		return errors.New("Throttling: Rate exceeded")
+	})
+
+	assert.EqualError(t, err, "Throttling: Rate exceeded")
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeps)
+
+	calls = 0
+	err = limiter.Do(func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("Throttling: Rate exceeded")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, retryBackoff(time.Second, 0))
+	assert.Equal(t, 8*time.Second, retryBackoff(time.Second, 3))
+	assert.Equal(t, time.Minute, retryBackoff(time.Second, 100))
+	assert.Equal(t, 2*time.Minute, retryBackoff(2*time.Minute, 100))
+}
+
+func TestLimiterNoRetryOnOtherErrors(t *testing.T) {
+	limiter := NewLimiter(ConcurrencyPolicy{
+		MaxRetries:       2,
+		ThrottlingErrors: []string{"Throttling"},
+	})
+
+	calls := 0
+	err := limiter.Do(func() error {
+		calls++
+		return errors.New("Not found")
+	})
+
+	assert.EqualError(t, err, "Not found")
+	assert.Equal(t, 1, calls)
+}
+
+func TestLimiterDoOnce(t *testing.T) {
+	limiter := NewLimiter(ConcurrencyPolicy{
+		MaxRetries:       2,
+		ThrottlingErrors: []string{"Throttling"},
+	})
+
+	calls := 0
+	err := limiter.DoOnce(func() error {
+		calls++
+		return errors.New("Throttling: Rate exceeded")
+	})
+
+	assert.EqualError(t, err, "Throttling: Rate exceeded")
+	assert.Equal(t, 1, calls)
+}
+
+func TestLimiterRateLimit(t *testing.T) {
+	limiter := NewLimiter(ConcurrencyPolicy{RateLimit: 10})
+	waited := time.Duration(0)
+	limiter.sleep = func(d time.Duration) {
+		waited += d
+	}
+
+	for i := 0; i < 3; i++ {
+		limiter.Do(func() error { return nil })
+	}
+
+	assert.True(t, waited > 150*time.Millisecond, "waited %s", waited)
+}
@@ -0,0 +1,513 @@
+// Package conformance checks that a driver behaves like the core drivers do,
+// either in-process or through the RPC boundary of the driver plugins. It's
+// meant for the authors of third-party drivers, running it against a local
+// fake of the backend of their driver.
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/drivers/rpc"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/state"
+)
+
+const (
+	// MachineName is the name of the machine created by the checks.
+	MachineName = "conformance"
+
+	defaultTimeout = 5 * time.Minute
+)
+
+var (
+	// pollInterval is how often the state of the machine is polled while
+	// it's waited for.
+	pollInterval = time.Second
+
+	// errSkipped is returned by the checks which don't apply to the driver.
+	errSkipped = errors.New("skipped")
+)
+
+// Mode is how the driver is called.
+type Mode string
+
+const (
+	// InProcess calls the driver directly.
+	InProcess Mode = "in-process"
+
+	// RPC calls the driver through rpcdriver, as a plugin binary would be.
+	RPC Mode = "rpc"
+)
+
+// Config describes the driver under test.
+type Config struct {
+	// NewDriver returns a new driver, like the constructor the driver
+	// plugin is registered with.
+	NewDriver func(hostName, storePath string) drivers.Driver
+
+	// Flags override the default values of the create flags of the
+	// driver, typically to point it to a fake backend.
+	Flags map[string]interface{}
+
+	// StorePath is where the driver stores the machines, a temporary
+	// directory by default.
+	StorePath string
+
+	// Timeout is how long the machine is waited for to reach a state,
+	// 5 minutes by default.
+	Timeout time.Duration
+
+	// FailCreate makes the fake backend fail the creation of the named
+	// machine partway, once some of its resources were created, and returns
+	// a function undoing it. Removing a half-created machine isn't checked
+	// without it.
+	FailCreate func(machineName string) (restore func())
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Check   string
+	Err     error
+	Skipped bool
+}
+
+// Report is the outcome of the checks of a driver in a mode.
+type Report struct {
+	Driver  string
+	Mode    Mode
+	Results []Result
+}
+
+// Passed tells whether no check failed.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Write writes the outcome of each check, then a summary.
+func (r *Report) Write(w io.Writer) error {
+	for _, result := range r.Results {
+		var line string
+		switch {
+		case result.Skipped:
+			line = fmt.Sprintf("SKIP  %s", result.Check)
+		case result.Err != nil:
+			line = fmt.Sprintf("FAIL  %s: %s", result.Check, result.Err)
+		default:
+			line = fmt.Sprintf("PASS  %s", result.Check)
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	status := "ok"
+	if !r.Passed() {
+		status = "FAIL"
+	}
+
+	_, err := fmt.Fprintf(w, "%s\t%s (%s)\n", status, r.Driver, r.Mode)
+	return err
+}
+
+func (r *Report) String() string {
+	b := &strings.Builder{}
+	r.Write(b)
+	return b.String()
+}
+
+// Run runs the checks against the driver called directly.
+func Run(cfg Config) *Report {
+	return run(cfg, InProcess)
+}
+
+// RunRPC runs the checks against the driver called through rpcdriver, served
+// in this process.
+func RunRPC(cfg Config) *Report {
+	return run(cfg, RPC)
+}
+
+// TestingT is the part of testing.T the checks are reported to.
+type TestingT interface {
+	Log(args ...interface{})
+	Error(args ...interface{})
+}
+
+// Test runs the checks in both modes from a Go test, failing it if any check
+// fails.
+func Test(t TestingT, cfg Config) {
+	for _, report := range []*Report{Run(cfg), RunRPC(cfg)} {
+		if report.Passed() {
+			t.Log(report.String())
+		} else {
+			t.Error(report.String())
+		}
+	}
+}
+
+// session holds the machine the checks run against.
+type session struct {
+	cfg    Config
+	mode   Mode
+	driver drivers.Driver
+	// closers are called once the checks are done.
+	closers []func()
+}
+
+// newDriver returns a new driver called the way the session calls it.
+func (s *session) newDriver(hostName string) (drivers.Driver, error) {
+	d := s.cfg.NewDriver(hostName, s.cfg.StorePath)
+	if s.mode == InProcess {
+		return d, nil
+	}
+
+	c, closeServer, err := serve(d)
+	if err != nil {
+		return nil, err
+	}
+
+	s.closers = append(s.closers, closeServer)
+
+	return c, nil
+}
+
+// options returns the default values of the create flags of the driver,
+// overridden by the configured ones.
+func (s *session) options(d drivers.Driver) rpcdriver.RPCFlags {
+	opts := rpcdriver.RPCFlags{
+		Values: map[string]interface{}{},
+	}
+
+	for _, f := range d.GetCreateFlags() {
+		opts.Values[f.String()] = f.Default()
+		if f.Default() == nil {
+			opts.Values[f.String()] = false
+		}
+	}
+
+	for name, value := range s.cfg.Flags {
+		opts.Values[name] = value
+	}
+
+	return opts
+}
+
+// waitForState waits for the machine to reach the desired state.
+func (s *session) waitForState(desired state.State) error {
+	deadline := time.Now().Add(s.cfg.Timeout)
+
+	for {
+		current, err := s.driver.GetState()
+		if err == nil && current == desired {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("Machine didn't reach the %s state: %s", desired, err)
+			}
+			return fmt.Errorf("Machine is %s, not %s", current, desired)
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func run(cfg Config, mode Mode) *Report {
+	report := &Report{
+		Mode: mode,
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	if cfg.StorePath == "" {
+		storePath, err := ioutil.TempDir("", "machine-conformance-")
+		if err != nil {
+			report.Results = append(report.Results, Result{Check: "setup", Err: err})
+			return report
+		}
+		defer os.RemoveAll(storePath)
+		cfg.StorePath = storePath
+	}
+
+	s := &session{
+		cfg:  cfg,
+		mode: mode,
+	}
+	defer func() {
+		for _, closeServer := range s.closers {
+			closeServer()
+		}
+	}()
+
+	d, err := s.newDriver(MachineName)
+	if err != nil {
+		report.Results = append(report.Results, Result{Check: "setup", Err: err})
+		return report
+	}
+	s.driver = d
+	report.Driver = d.DriverName()
+
+	created := false
+	for _, c := range checks {
+		result := Result{
+			Check: c.name,
+		}
+
+		if c.needsMachine && !created {
+			result.Skipped = true
+		} else if err := c.run(s); err == errSkipped {
+			result.Skipped = true
+		} else {
+			result.Err = err
+		}
+
+		if c.name == checkCreate {
+			created = result.Err == nil
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+const checkCreate = "create"
+
+type check struct {
+	name string
+	// needsMachine checks are skipped if the machine wasn't created.
+	needsMachine bool
+	run          func(s *session) error
+}
+
+// checks run in order, the ones needing the machine after it's created.
+// Removing it is checked even when it wasn't, since a machine whose creation
+// failed half-way is removed too.
+var checks = []check{
+	{name: "driver-name", run: checkDriverName},
+	{name: "create-flags", run: checkCreateFlags},
+	{name: "config-round-trip", run: checkConfigRoundTrip},
+	{name: "remove-not-created", run: checkRemoveNotCreated},
+	{name: "remove-half-created", run: checkRemoveHalfCreated},
+	{name: checkCreate, run: checkCreateMachine},
+	{name: "get-ip", needsMachine: true, run: checkGetIP},
+	{name: "stop", needsMachine: true, run: checkStop},
+	{name: "stop-stopped", needsMachine: true, run: checkStopStopped},
+	{name: "start", needsMachine: true, run: checkStart},
+	{name: "restart", needsMachine: true, run: checkRestart},
+	{name: "kill", needsMachine: true, run: checkKill},
+	{name: "remove", run: checkRemove},
+}
+
+func checkDriverName(s *session) error {
+	if s.driver.DriverName() == "" {
+		return fmt.Errorf("DriverName is empty")
+	}
+
+	if name := s.driver.GetMachineName(); name != MachineName {
+		return fmt.Errorf("GetMachineName is %q, not %q", name, MachineName)
+	}
+
+	return nil
+}
+
+func checkCreateFlags(s *session) error {
+	names := map[string]bool{}
+	for _, f := range s.driver.GetCreateFlags() {
+		name := f.String()
+		if name == "" {
+			return fmt.Errorf("A create flag has no name")
+		}
+		if names[name] {
+			return fmt.Errorf("The --%s create flag is declared twice", name)
+		}
+		names[name] = true
+	}
+
+	opts := s.options(s.driver)
+	if err := mcnflag.Validate(s.driver.GetCreateFlags(), opts.Values); err != nil {
+		return err
+	}
+
+	return s.driver.SetConfigFromFlags(opts)
+}
+
+// checkConfigRoundTrip checks that the configuration of the driver is the
+// same once loaded by another driver, as when a machine is loaded from the
+// store.
+func checkConfigRoundTrip(s *session) error {
+	rawDriver, err := json.Marshal(s.driver)
+	if err != nil {
+		return fmt.Errorf("Error marshalling the configuration: %s", err)
+	}
+
+	loaded, err := s.newDriver("")
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(rawDriver, loaded); err != nil {
+		return fmt.Errorf("Error unmarshalling the configuration: %s", err)
+	}
+
+	rawLoaded, err := json.Marshal(loaded)
+	if err != nil {
+		return fmt.Errorf("Error marshalling the loaded configuration: %s", err)
+	}
+
+	var before, after interface{}
+	if err := json.Unmarshal(rawDriver, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(rawLoaded, &after); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		return fmt.Errorf("The configuration changed from %s to %s", rawDriver, rawLoaded)
+	}
+
+	if name := loaded.GetMachineName(); name != MachineName {
+		return fmt.Errorf("GetMachineName of the loaded driver is %q, not %q", name, MachineName)
+	}
+
+	return nil
+}
+
+// checkRemoveNotCreated checks that a machine whose creation didn't go
+// through can be removed.
+func checkRemoveNotCreated(s *session) error {
+	d, err := s.newDriver(MachineName + "-not-created")
+	if err != nil {
+		return err
+	}
+
+	if err := d.SetConfigFromFlags(s.options(d)); err != nil {
+		return err
+	}
+
+	return d.Remove()
+}
+
+// checkRemoveHalfCreated checks that a machine whose creation failed partway
+// can be removed.
+func checkRemoveHalfCreated(s *session) error {
+	if s.cfg.FailCreate == nil {
+		return errSkipped
+	}
+
+	name := MachineName + "-half-created"
+	d, err := s.newDriver(name)
+	if err != nil {
+		return err
+	}
+
+	if err := d.SetConfigFromFlags(s.options(d)); err != nil {
+		return err
+	}
+
+	restore := s.cfg.FailCreate(name)
+	err = d.Create()
+	restore()
+
+	if err == nil {
+		return fmt.Errorf("Create didn't fail, check FailCreate")
+	}
+
+	if err := d.Remove(); err != nil {
+		return fmt.Errorf("Remove of a machine whose creation failed partway failed: %s", err)
+	}
+
+	return nil
+}
+
+func checkCreateMachine(s *session) error {
+	if err := s.driver.PreCreateCheck(); err != nil {
+		return fmt.Errorf("Error in PreCreateCheck: %s", err)
+	}
+
+	if err := s.driver.Create(); err != nil {
+		return err
+	}
+
+	return s.waitForState(state.Running)
+}
+
+func checkGetIP(s *session) error {
+	ip, err := s.driver.GetIP()
+	if err != nil {
+		return err
+	}
+	if ip == "" {
+		return fmt.Errorf("GetIP is empty while the machine is running")
+	}
+
+	if _, err := s.driver.GetURL(); err != nil {
+		return fmt.Errorf("Error in GetURL: %s", err)
+	}
+
+	return nil
+}
+
+func checkStop(s *session) error {
+	if err := s.driver.Stop(); err != nil {
+		return err
+	}
+
+	return s.waitForState(state.Stopped)
+}
+
+// checkStopStopped checks that stopping a stopped machine isn't an error.
+func checkStopStopped(s *session) error {
+	if err := s.driver.Stop(); err != nil {
+		return fmt.Errorf("Stop of a stopped machine failed: %s", err)
+	}
+
+	return s.waitForState(state.Stopped)
+}
+
+func checkStart(s *session) error {
+	if err := s.driver.Start(); err != nil {
+		return err
+	}
+
+	return s.waitForState(state.Running)
+}
+
+func checkRestart(s *session) error {
+	if err := s.driver.Restart(); err != nil {
+		return err
+	}
+
+	return s.waitForState(state.Running)
+}
+
+func checkKill(s *session) error {
+	if err := s.driver.Kill(); err != nil {
+		return err
+	}
+
+	return s.waitForState(state.Stopped)
+}
+
+func checkRemove(s *session) error {
+	return s.driver.Remove()
+}
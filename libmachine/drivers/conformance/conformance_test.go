@@ -0,0 +1,229 @@
+package conformance
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/stretchr/testify/assert"
+)
+
+// backend is the fake backend of backendDriver: the disks and the instances
+// of the machines, by machine name.
+type backend struct {
+	disks      map[string]bool
+	instances  map[string]bool
+	failCreate map[string]bool
+}
+
+func newBackend() *backend {
+	return &backend{
+		disks:      map[string]bool{},
+		instances:  map[string]bool{},
+		failCreate: map[string]bool{},
+	}
+}
+
+func (b *backend) FailCreate(machineName string) func() {
+	b.failCreate[machineName] = true
+	return func() { delete(b.failCreate, machineName) }
+}
+
+// backendDriver creates a disk, then an instance running from it.
+type backendDriver struct {
+	*fakedriver.Driver
+	backend *backend
+}
+
+func (d *backendDriver) Create() error {
+	d.backend.disks[d.MachineName] = true
+	if d.backend.failCreate[d.MachineName] {
+		return errors.New("quota exceeded")
+	}
+
+	d.backend.instances[d.MachineName] = true
+	d.MockState = state.Running
+	return nil
+}
+
+func (d *backendDriver) Remove() error {
+	delete(d.backend.instances, d.MachineName)
+	delete(d.backend.disks, d.MachineName)
+	return nil
+}
+
+func newFakeDriver(hostName, storePath string) drivers.Driver {
+	return &fakedriver.Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+		},
+		MockName: hostName,
+		MockIP:   "1.2.3.4",
+	}
+}
+
+func (b *backend) NewDriver(hostName, storePath string) drivers.Driver {
+	return &backendDriver{
+		Driver:  newFakeDriver(hostName, storePath).(*fakedriver.Driver),
+		backend: b,
+	}
+}
+
+// stuckDriver never stops.
+type stuckDriver struct {
+	*backendDriver
+}
+
+func (d *stuckDriver) Stop() error {
+	return nil
+}
+
+// failingDriver fails to create its machines.
+type failingDriver struct {
+	*fakedriver.Driver
+}
+
+func (d *failingDriver) Create() error {
+	return errors.New("quota exceeded")
+}
+
+func resultsByCheck(report *Report) map[string]Result {
+	results := map[string]Result{}
+	for _, result := range report.Results {
+		results[result.Check] = result
+	}
+	return results
+}
+
+func TestRunFakeDriver(t *testing.T) {
+	b := newBackend()
+	cfg := Config{
+		NewDriver:  b.NewDriver,
+		FailCreate: b.FailCreate,
+	}
+
+	for _, report := range []*Report{Run(cfg), RunRPC(cfg)} {
+		results := resultsByCheck(report)
+		assert.True(t, report.Passed(), report.String())
+		assert.Equal(t, "Driver", report.Driver)
+		assert.Len(t, report.Results, len(checks))
+		assert.False(t, results["remove-half-created"].Skipped)
+		assert.Empty(t, b.disks)
+		assert.Empty(t, b.instances)
+	}
+}
+
+func TestRunWithoutFailCreate(t *testing.T) {
+	report := Run(Config{
+		NewDriver: newBackend().NewDriver,
+	})
+
+	assert.True(t, report.Passed(), report.String())
+	assert.True(t, resultsByCheck(report)["remove-half-created"].Skipped)
+}
+
+// leakyDriver can't remove the machines without an instance.
+type leakyDriver struct {
+	*backendDriver
+}
+
+func (d *leakyDriver) Remove() error {
+	if !d.backend.instances[d.MachineName] && d.backend.disks[d.MachineName] {
+		return errors.New("instance not found")
+	}
+
+	return d.backendDriver.Remove()
+}
+
+func TestRunLeakyDriver(t *testing.T) {
+	b := newBackend()
+	report := RunRPC(Config{
+		NewDriver: func(hostName, storePath string) drivers.Driver {
+			return &leakyDriver{b.NewDriver(hostName, storePath).(*backendDriver)}
+		},
+		FailCreate: b.FailCreate,
+	})
+
+	results := resultsByCheck(report)
+	assert.False(t, report.Passed())
+	assert.EqualError(t, results["remove-half-created"].Err, "Remove of a machine whose creation failed partway failed: instance not found")
+	assert.NoError(t, results["remove"].Err)
+}
+
+func TestRunStuckDriver(t *testing.T) {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = time.Millisecond
+
+	report := Run(Config{
+		NewDriver: func(hostName, storePath string) drivers.Driver {
+			return &stuckDriver{newBackend().NewDriver(hostName, storePath).(*backendDriver)}
+		},
+		Timeout: 10 * time.Millisecond,
+	})
+
+	results := resultsByCheck(report)
+	assert.False(t, report.Passed())
+	assert.EqualError(t, results["stop"].Err, "Machine is Running, not Stopped")
+	assert.NoError(t, results["start"].Err)
+	assert.Contains(t, report.String(), "FAIL  stop: Machine is Running, not Stopped")
+	assert.True(t, strings.HasSuffix(report.String(), "FAIL\tDriver (in-process)\n"))
+}
+
+func TestRunFailingDriver(t *testing.T) {
+	report := RunRPC(Config{
+		NewDriver: func(hostName, storePath string) drivers.Driver {
+			return &failingDriver{newFakeDriver(hostName, storePath).(*fakedriver.Driver)}
+		},
+	})
+
+	results := resultsByCheck(report)
+	assert.False(t, report.Passed())
+	assert.EqualError(t, results["create"].Err, "quota exceeded")
+	assert.True(t, results["stop"].Skipped)
+	assert.False(t, results["remove"].Skipped)
+	assert.NoError(t, results["remove"].Err)
+	assert.Contains(t, report.String(), "SKIP  stop")
+}
+
+// flaggedDriver has create flags.
+type flaggedDriver struct {
+	*fakedriver.Driver
+}
+
+func (d *flaggedDriver) GetCreateFlags() []mcnflag.Flag {
+	return []mcnflag.Flag{
+		mcnflag.StringFlag{
+			Name:  "fake-url",
+			Value: "https://api.example.com",
+		},
+		mcnflag.IntFlag{
+			Name:  "fake-cpus",
+			Value: 1,
+		},
+		mcnflag.BoolFlag{
+			Name: "fake-debug",
+		},
+	}
+}
+
+func TestSessionOptions(t *testing.T) {
+	s := &session{
+		cfg: Config{
+			Flags: map[string]interface{}{
+				"fake-url": "http://127.0.0.1:8080",
+			},
+		},
+	}
+
+	opts := s.options(&flaggedDriver{})
+
+	assert.Equal(t, "http://127.0.0.1:8080", opts.String("fake-url"))
+	assert.Equal(t, 1, opts.Int("fake-cpus"))
+	assert.False(t, opts.Bool("fake-debug"))
+}
@@ -0,0 +1,70 @@
+package conformance
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/rpc"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/drivers/rpc"
+)
+
+// serve serves the driver as a plugin binary would, on a local listener, and
+// returns a client driver calling it and a function closing both.
+func serve(d drivers.Driver) (*rpcdriver.RPCClientDriver, func(), error) {
+	rawDriver, err := json.Marshal(d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rpcd := rpcdriver.NewRPCServerDriver(d)
+	server := rpc.NewServer()
+	if err := server.RegisterName(rpcdriver.RPCServiceNameV1, rpcd); err != nil {
+		return nil, nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go http.Serve(listener, server)
+
+	// the plugin binaries wait for the heartbeats and the close calls
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-rpcd.HeartbeatCh:
+			case <-rpcd.CloseCh:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	closeServer := func() {
+		listener.Close()
+		close(done)
+	}
+
+	rpcclient, err := rpc.DialHTTP("tcp", listener.Addr().String())
+	if err != nil {
+		closeServer()
+		return nil, nil, err
+	}
+
+	c, err := rpcdriver.NewRPCClientDriverFromClient(rpcclient, rawDriver)
+	if err != nil {
+		rpcclient.Close()
+		closeServer()
+		return nil, nil, err
+	}
+
+	return c, func() {
+		c.Close()
+		rpcclient.Close()
+		closeServer()
+	}, nil
+}
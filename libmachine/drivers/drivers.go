@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"errors"
+	"time"
 
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnflag"
@@ -81,6 +82,43 @@ type DriverOptions interface {
 	Bool(key string) bool
 }
 
+// TypedDriverOptions is implemented by the DriverOptions holding the values
+// of duration, float and map flags. It's kept apart from DriverOptions so
+// that the implementations of the latter outside of machine still compile.
+type TypedDriverOptions interface {
+	DriverOptions
+	Duration(key string) time.Duration
+	Float(key string) float64
+	Map(key string) map[string]string
+}
+
+// DurationOption returns the value of the duration flag key, or zero when
+// opts doesn't hold durations.
+func DurationOption(opts DriverOptions, key string) time.Duration {
+	if typed, ok := opts.(TypedDriverOptions); ok {
+		return typed.Duration(key)
+	}
+	return 0
+}
+
+// FloatOption returns the value of the float flag key, or zero when opts
+// doesn't hold floats.
+func FloatOption(opts DriverOptions, key string) float64 {
+	if typed, ok := opts.(TypedDriverOptions); ok {
+		return typed.Float(key)
+	}
+	return 0
+}
+
+// MapOption returns the value of the map flag key, or nil when opts doesn't
+// hold maps.
+func MapOption(opts DriverOptions, key string) map[string]string {
+	if typed, ok := opts.(TypedDriverOptions); ok {
+		return typed.Map(key)
+	}
+	return nil
+}
+
 func MachineInState(d Driver, desiredState state.State) func() bool {
 	return func() bool {
 		currentState, err := d.GetState()
@@ -0,0 +1,42 @@
+package drivers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/stretchr/testify/assert"
+)
+
+// untypedOptions only implements DriverOptions, as older implementations do.
+type untypedOptions struct{}
+
+func (o untypedOptions) String(key string) string        { return "" }
+func (o untypedOptions) StringSlice(key string) []string { return nil }
+func (o untypedOptions) Int(key string) int              { return 0 }
+func (o untypedOptions) Bool(key string) bool            { return false }
+
+func TestTypedOptions(t *testing.T) {
+	opts := &CheckDriverOptions{
+		FlagsValues: map[string]interface{}{
+			"timeout": time.Minute,
+			"ratio":   0.5,
+			"labels":  map[string]string{"env": "test"},
+		},
+		CreateFlags: []mcnflag.Flag{
+			mcnflag.DurationFlag{Name: "timeout"},
+			mcnflag.FloatFlag{Name: "ratio"},
+			mcnflag.MapFlag{Name: "labels"},
+		},
+	}
+
+	assert.Equal(t, time.Minute, DurationOption(opts, "timeout"))
+	assert.Equal(t, 0.5, FloatOption(opts, "ratio"))
+	assert.Equal(t, map[string]string{"env": "test"}, MapOption(opts, "labels"))
+}
+
+func TestTypedOptionsNotSupported(t *testing.T) {
+	assert.Equal(t, time.Duration(0), DurationOption(untypedOptions{}, "timeout"))
+	assert.Equal(t, 0.0, FloatOption(untypedOptions{}, "ratio"))
+	assert.Nil(t, MapOption(untypedOptions{}, "labels"))
+}
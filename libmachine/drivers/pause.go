@@ -0,0 +1,65 @@
+package drivers
+
+const (
+	// CapabilityPause is supported by drivers implementing Pauser.
+	CapabilityPause Capability = "pause"
+
+	// CapabilitySuspend is supported by drivers implementing Suspender.
+	CapabilitySuspend Capability = "suspend"
+)
+
+// Pauser is implemented by drivers able to freeze a running machine in
+// memory, i.e. to put it in the state.Paused state.
+type Pauser interface {
+	// Pause freezes the running machine.
+	Pause() error
+
+	// Unpause lets the paused machine run again.
+	Unpause() error
+}
+
+// Suspender is implemented by drivers able to save the memory of a running
+// machine and to stop it, i.e. to put it in the state.Saved state.
+type Suspender interface {
+	// Suspend saves the running machine and stops it.
+	Suspend() error
+
+	// Resume starts the saved machine where it was suspended.
+	Resume() error
+}
+
+func init() {
+	RegisterCapability(CapabilityPause, func(d Driver) bool {
+		_, ok := d.(Pauser)
+		return ok
+	})
+
+	RegisterCapability(CapabilitySuspend, func(d Driver) bool {
+		_, ok := d.(Suspender)
+		return ok
+	})
+}
+
+// AsPauser returns d as a Pauser, or ErrCapabilityNotSupported.
+func AsPauser(d Driver) (Pauser, error) {
+	if !HasCapability(d, CapabilityPause) {
+		return nil, ErrCapabilityNotSupported{
+			DriverName: d.DriverName(),
+			Capability: CapabilityPause,
+		}
+	}
+
+	return d.(Pauser), nil
+}
+
+// AsSuspender returns d as a Suspender, or ErrCapabilityNotSupported.
+func AsSuspender(d Driver) (Suspender, error) {
+	if !HasCapability(d, CapabilitySuspend) {
+		return nil, ErrCapabilityNotSupported{
+			DriverName: d.DriverName(),
+			Capability: CapabilitySuspend,
+		}
+	}
+
+	return d.(Suspender), nil
+}
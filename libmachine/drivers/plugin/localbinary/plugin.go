@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -29,6 +31,7 @@ const (
 	PluginEnvKey        = "MACHINE_PLUGIN_TOKEN"
 	PluginEnvVal        = "42"
 	PluginEnvDriverName = "MACHINE_PLUGIN_DRIVER_NAME"
+	PluginBinaryPrefix  = "docker-machine-driver-"
 )
 
 type PluginStreamer interface {
@@ -98,24 +101,72 @@ func (e ErrPluginBinaryNotFound) Error() string {
 //  + If the driver is NOT a core driver, then the separate binary must be in the PATH and it's name must be
 // `docker-machine-driver-driverName`
 func driverPath(driverName string) string {
+	if IsCoreDriver(driverName) {
+		if CurrentBinaryIsDockerMachine {
+			return os.Args[0]
+		}
+
+		return "docker-machine"
+	}
+
+	return PluginBinaryPrefix + driverName
+}
+
+// IsCoreDriver reports whether driverName is built into docker-machine.
+func IsCoreDriver(driverName string) bool {
 	for _, coreDriver := range CoreDrivers {
 		if coreDriver == driverName {
-			if CurrentBinaryIsDockerMachine {
-				return os.Args[0]
-			}
-
-			return "docker-machine"
+			return true
 		}
 	}
 
-	return fmt.Sprintf("docker-machine-driver-%s", driverName)
+	return false
 }
 
-func NewPlugin(driverName string) (*Plugin, error) {
+// LookupDriver returns the path of the binary which serves driverName.
+func LookupDriver(driverName string) (string, error) {
 	driverPath := driverPath(driverName)
 	binaryPath, err := exec.LookPath(driverPath)
 	if err != nil {
-		return nil, ErrPluginBinaryNotFound{driverName, driverPath}
+		return "", ErrPluginBinaryNotFound{driverName, driverPath}
+	}
+
+	return binaryPath, nil
+}
+
+// PluginDrivers returns the sorted names of the drivers of the plugin
+// binaries found on the PATH.
+func PluginDrivers() []string {
+	found := map[string]bool{}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		matches, err := filepath.Glob(filepath.Join(dir, PluginBinaryPrefix+"*"))
+		if err != nil {
+			continue
+		}
+
+		for _, match := range matches {
+			name := strings.TrimPrefix(filepath.Base(match), PluginBinaryPrefix)
+			name = strings.TrimSuffix(name, ".exe")
+			if _, err := exec.LookPath(match); err == nil && name != "" {
+				found[name] = true
+			}
+		}
+	}
+
+	names := []string{}
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func NewPlugin(driverName string) (*Plugin, error) {
+	binaryPath, err := LookupDriver(driverName)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Debugf("Found binary path at %s", binaryPath)
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -157,3 +159,38 @@ func TestExecServer(t *testing.T) {
 		t.Fatalf("Error serving: %s", err)
 	}
 }
+
+func TestPluginDrivers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "machine-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"docker-machine-driver-kvm", "docker-machine-driver-xhyve", "docker-machine"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// not executable
+	if err := ioutil.WriteFile(filepath.Join(dir, "docker-machine-driver-broken"), []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir)
+
+	assert.Equal(t, []string{"kvm", "xhyve"}, PluginDrivers())
+
+	path, err := LookupDriver("kvm")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "docker-machine-driver-kvm"), path)
+
+	_, err = LookupDriver("qemu")
+	assert.Equal(t, ErrPluginBinaryNotFound{"qemu", "docker-machine-driver-qemu"}, err)
+}
+
+func TestIsCoreDriver(t *testing.T) {
+	assert.True(t, IsCoreDriver("virtualbox"))
+	assert.False(t, IsCoreDriver("kvm"))
+}
@@ -0,0 +1,54 @@
+package drivers
+
+// CapabilityResize is supported by drivers implementing Resizer.
+const CapabilityResize Capability = "resize"
+
+// ResizeOptions describes the new size of a machine. Zero values leave the
+// corresponding size unchanged.
+type ResizeOptions struct {
+	// CPUs is the number of virtual CPUs.
+	CPUs int
+
+	// Memory is the size of the memory in MB.
+	Memory int
+
+	// DiskSize is the size of the disk in MB. Disks only grow.
+	DiskSize int
+
+	// MachineType is the name of a size of the provider, e.g. an EC2
+	// instance type, for the drivers which don't size CPUs and memory
+	// separately.
+	MachineType string
+}
+
+// IsZero reports whether the options leave every size unchanged.
+func (o ResizeOptions) IsZero() bool {
+	return o == ResizeOptions{}
+}
+
+// Resizer is implemented by drivers able to change the size of an existing
+// machine.
+type Resizer interface {
+	// Resize changes the size of the stopped machine and stores the new
+	// size in the configuration of the driver.
+	Resize(opts ResizeOptions) error
+}
+
+func init() {
+	RegisterCapability(CapabilityResize, func(d Driver) bool {
+		_, ok := d.(Resizer)
+		return ok
+	})
+}
+
+// AsResizer returns d as a Resizer, or ErrCapabilityNotSupported.
+func AsResizer(d Driver) (Resizer, error) {
+	if !HasCapability(d, CapabilityResize) {
+		return nil, ErrCapabilityNotSupported{
+			DriverName: d.DriverName(),
+			Capability: CapabilityResize,
+		}
+	}
+
+	return d.(Resizer), nil
+}
@@ -2,7 +2,9 @@ package rpcdriver
 
 import (
 	"fmt"
+	"net"
 	"net/rpc"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +20,12 @@ import (
 
 var (
 	heartbeatInterval = 5 * time.Second
+
+	// limiters apply the concurrency policies of the drivers, by name, to
+	// all the clients of the drivers of the name.
+	limiters        = map[string]*drivers.Limiter{}
+	policyOverrides = map[string]drivers.ConcurrencyOverride{}
+	limitersLock    = &sync.Mutex{}
 )
 
 type RPCClientDriverFactory interface {
@@ -40,7 +48,52 @@ func NewRPCClientDriverFactory() RPCClientDriverFactory {
 type RPCClientDriver struct {
 	plugin          localbinary.DriverPlugin
 	heartbeatDoneCh chan bool
+	closeOnce       sync.Once
+	apiVersion      int
+	capabilities    []drivers.Capability
 	Client          *InternalClient
+
+	// lastConfig is the last configuration known to the driver binary,
+	// sent again to it when it's restarted.
+	lastConfig []byte
+	configLock sync.Mutex
+}
+
+// pluginLauncher starts a new plugin binary serving the driver, and connects
+// to it.
+type pluginLauncher func() (localbinary.DriverPlugin, *rpc.Client, error)
+
+// ErrIncompatibleAPIVersion is returned when a driver binary serves another
+// version of the plugin API than this client.
+type ErrIncompatibleAPIVersion struct {
+	Version int
+}
+
+func (e ErrIncompatibleAPIVersion) Error() string {
+	return fmt.Sprintf("Driver binary uses an incompatible API version (%d)", e.Version)
+}
+
+// ErrPluginRestarted is returned by the calls interrupted by the crash of the
+// plugin binary serving the driver. The plugin binary was restarted but the
+// call isn't retried, since it may have changed the machine before the crash.
+type ErrPluginRestarted struct {
+	Method string
+	Err    error
+}
+
+func (e ErrPluginRestarted) Error() string {
+	return fmt.Sprintf("Driver plugin crashed during %s and was restarted, check the state of the machine before trying again: %s", strings.TrimPrefix(e.Method, "."), e.Err)
+}
+
+// ErrPluginCrashed is returned when the plugin binary serving the driver
+// crashed and couldn't be restarted.
+type ErrPluginCrashed struct {
+	Method string
+	Err    error
+}
+
+func (e ErrPluginCrashed) Error() string {
+	return fmt.Sprintf("Driver plugin crashed during %s and couldn't be restarted: %s", strings.TrimPrefix(e.Method, "."), e.Err)
 }
 
 type RPCCall struct {
@@ -53,43 +106,221 @@ type InternalClient struct {
 	MachineName    string
 	RPCClient      *rpc.Client
 	rpcServiceName string
+	limiter        *drivers.Limiter
+
+	// restart, when set, restarts the crashed plugin binary serving the
+	// driver and returns a client connected to the new one.
+	restart func() (*rpc.Client, error)
+	// lock guards RPCClient, replaced when the plugin binary is restarted.
+	lock       sync.RWMutex
+	generation int
 }
 
 const (
 	RPCServiceNameV0 = `RpcServerDriver`
 	RPCServiceNameV1 = `RPCServerDriver`
 
-	HeartbeatMethod          = `.Heartbeat`
-	GetVersionMethod         = `.GetVersion`
-	CloseMethod              = `.Close`
-	GetCreateFlagsMethod     = `.GetCreateFlags`
-	SetConfigRawMethod       = `.SetConfigRaw`
-	GetConfigRawMethod       = `.GetConfigRaw`
-	DriverNameMethod         = `.DriverName`
-	SetConfigFromFlagsMethod = `.SetConfigFromFlags`
-	GetURLMethod             = `.GetURL`
-	GetMachineNameMethod     = `.GetMachineName`
-	GetIPMethod              = `.GetIP`
-	GetSSHHostnameMethod     = `.GetSSHHostname`
-	GetSSHKeyPathMethod      = `.GetSSHKeyPath`
-	GetSSHPortMethod         = `.GetSSHPort`
-	GetSSHUsernameMethod     = `.GetSSHUsername`
-	GetStateMethod           = `.GetState`
-	PreCreateCheckMethod     = `.PreCreateCheck`
-	CreateMethod             = `.Create`
-	RemoveMethod             = `.Remove`
-	StartMethod              = `.Start`
-	StopMethod               = `.Stop`
-	RestartMethod            = `.Restart`
-	KillMethod               = `.Kill`
-	UpgradeMethod            = `.Upgrade`
+	HeartbeatMethod            = `.Heartbeat`
+	GetVersionMethod           = `.GetVersion`
+	GetCapabilitiesMethod      = `.GetCapabilities`
+	GetConcurrencyPolicyMethod = `.GetConcurrencyPolicy`
+	CloseMethod                = `.Close`
+	GetCreateFlagsMethod       = `.GetCreateFlags`
+	SetConfigRawMethod         = `.SetConfigRaw`
+	GetConfigRawMethod         = `.GetConfigRaw`
+	DriverNameMethod           = `.DriverName`
+	SetConfigFromFlagsMethod   = `.SetConfigFromFlags`
+	GetURLMethod               = `.GetURL`
+	GetMachineNameMethod       = `.GetMachineName`
+	GetIPMethod                = `.GetIP`
+	GetSSHHostnameMethod       = `.GetSSHHostname`
+	GetSSHKeyPathMethod        = `.GetSSHKeyPath`
+	GetSSHPortMethod           = `.GetSSHPort`
+	GetSSHUsernameMethod       = `.GetSSHUsername`
+	GetStateMethod             = `.GetState`
+	PreCreateCheckMethod       = `.PreCreateCheck`
+	CreateMethod               = `.Create`
+	RemoveMethod               = `.Remove`
+	StartMethod                = `.Start`
+	StopMethod                 = `.Stop`
+	RestartMethod              = `.Restart`
+	KillMethod                 = `.Kill`
+	UpgradeMethod              = `.Upgrade`
+	CreateSnapshotMethod       = `.CreateSnapshot`
+	ListSnapshotsMethod        = `.ListSnapshots`
+	RestoreSnapshotMethod      = `.RestoreSnapshot`
+	RemoveSnapshotMethod       = `.RemoveSnapshot`
+	ResizeMethod               = `.Resize`
+	PauseMethod                = `.Pause`
+	UnpauseMethod              = `.Unpause`
+	SuspendMethod              = `.Suspend`
+	ResumeMethod               = `.Resume`
+	AddSharedFolderMethod      = `.AddSharedFolder`
+	RemoveSharedFolderMethod   = `.RemoveSharedFolder`
+	ListSharedFoldersMethod    = `.ListSharedFolders`
 )
 
+// unlimitedMethods are answered by the driver server itself, they're not
+// subject to the concurrency policy of the driver.
+var unlimitedMethods = map[string]bool{
+	HeartbeatMethod:            true,
+	GetVersionMethod:           true,
+	GetCapabilitiesMethod:      true,
+	GetConcurrencyPolicyMethod: true,
+	CloseMethod:                true,
+	GetCreateFlagsMethod:       true,
+	SetConfigRawMethod:         true,
+	GetConfigRawMethod:         true,
+	DriverNameMethod:           true,
+	GetMachineNameMethod:       true,
+	GetSSHKeyPathMethod:        true,
+	GetSSHPortMethod:           true,
+	GetSSHUsernameMethod:       true,
+}
+
+// idempotentMethods don't change the machine, they're made again once the
+// crashed plugin binary serving the driver is restarted, or when throttled.
+// The others could be half done, and are made only once.
+var idempotentMethods = map[string]bool{
+	HeartbeatMethod:            true,
+	GetVersionMethod:           true,
+	GetCapabilitiesMethod:      true,
+	GetConcurrencyPolicyMethod: true,
+	GetCreateFlagsMethod:       true,
+	SetConfigRawMethod:         true,
+	GetConfigRawMethod:         true,
+	DriverNameMethod:           true,
+	GetURLMethod:               true,
+	GetMachineNameMethod:       true,
+	GetIPMethod:                true,
+	GetSSHHostnameMethod:       true,
+	GetSSHKeyPathMethod:        true,
+	GetSSHPortMethod:           true,
+	GetSSHUsernameMethod:       true,
+	GetStateMethod:             true,
+	ListSnapshotsMethod:        true,
+	ListSharedFoldersMethod:    true,
+}
+
 func (ic *InternalClient) Call(serviceMethod string, args interface{}, reply interface{}) error {
 	if serviceMethod != HeartbeatMethod {
 		log.Debugf("(%s) Calling %+v", ic.MachineName, serviceMethod)
 	}
-	return ic.RPCClient.Call(ic.rpcServiceName+serviceMethod, args, reply)
+
+	ic.lock.RLock()
+	rpcclient, generation := ic.RPCClient, ic.generation
+	ic.lock.RUnlock()
+
+	err := ic.call(rpcclient, serviceMethod, args, reply)
+	if serviceMethod == CloseMethod || !isConnectionError(err) {
+		return err
+	}
+
+	rpcclient, restarted, restartErr := ic.restartFrom(generation, err)
+	if !restarted {
+		return err
+	}
+	if restartErr != nil {
+		return ErrPluginCrashed{
+			Method: serviceMethod,
+			Err:    restartErr,
+		}
+	}
+
+	if !idempotentMethods[serviceMethod] {
+		return ErrPluginRestarted{
+			Method: serviceMethod,
+			Err:    err,
+		}
+	}
+
+	return ic.call(rpcclient, serviceMethod, args, reply)
+}
+
+func (ic *InternalClient) call(rpcclient *rpc.Client, serviceMethod string, args interface{}, reply interface{}) error {
+	if ic.limiter == nil || unlimitedMethods[serviceMethod] {
+		return rpcclient.Call(ic.rpcServiceName+serviceMethod, args, reply)
+	}
+
+	call := func() error {
+		return rpcclient.Call(ic.rpcServiceName+serviceMethod, args, reply)
+	}
+
+	if !idempotentMethods[serviceMethod] {
+		return ic.limiter.DoOnce(call)
+	}
+
+	return ic.limiter.Do(call)
+}
+
+// restartFrom restarts the plugin binary, unless it was already restarted
+// since the connection of the given generation broke. It tells whether the
+// plugin binary can be restarted at all.
+func (ic *InternalClient) restartFrom(generation int, cause error) (*rpc.Client, bool, error) {
+	ic.lock.Lock()
+	defer ic.lock.Unlock()
+
+	if ic.restart == nil {
+		return nil, false, nil
+	}
+
+	if ic.generation != generation {
+		return ic.RPCClient, true, nil
+	}
+
+	log.Warnf("(%s) Lost the connection to the driver plugin, restarting it: %s", ic.MachineName, cause)
+
+	rpcclient, err := ic.restart()
+	if err != nil {
+		return nil, true, err
+	}
+
+	ic.RPCClient.Close()
+	ic.RPCClient = rpcclient
+	ic.generation++
+
+	return rpcclient, true, nil
+}
+
+// isConnectionError tells whether the error comes from the connection to the
+// plugin binary, rather than from the driver it serves.
+func isConnectionError(err error) bool {
+	switch err {
+	case nil:
+		return false
+	case rpc.ErrShutdown, io.EOF, io.ErrUnexpectedEOF, io.ErrClosedPipe:
+		return true
+	}
+
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// SetConcurrencyPolicyOverrides sets the policies, by driver name, whose
+// fields override the ones declared by the drivers. It's called before the
+// drivers are loaded.
+func SetConcurrencyPolicyOverrides(overrides map[string]drivers.ConcurrencyOverride) {
+	limitersLock.Lock()
+	defer limitersLock.Unlock()
+
+	policyOverrides = overrides
+	limiters = map[string]*drivers.Limiter{}
+}
+
+// limiterFor returns the limiter shared by the drivers of the name, created
+// with the policy the first of them declares.
+func limiterFor(driverName string, policy drivers.ConcurrencyPolicy) *drivers.Limiter {
+	limitersLock.Lock()
+	defer limitersLock.Unlock()
+
+	if limiter, ok := limiters[driverName]; ok {
+		return limiter
+	}
+
+	limiter := drivers.NewLimiter(policy.Override(policyOverrides[driverName]))
+	limiters[driverName] = limiter
+
+	return limiter
 }
 
 func (ic *InternalClient) switchToV0() {
@@ -118,12 +349,11 @@ func (f *DefaultRPCClientDriverFactory) Close() error {
 	return nil
 }
 
-func (f *DefaultRPCClientDriverFactory) NewRPCClientDriver(driverName string, rawDriver []byte) (*RPCClientDriver, error) {
-	mcnName := ""
-
+// startPlugin starts the plugin binary of the driver, and connects to it.
+func startPlugin(driverName string) (*localbinary.Plugin, *rpc.Client, error) {
 	p, err := localbinary.NewPlugin(driverName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	go func() {
@@ -136,28 +366,63 @@ func (f *DefaultRPCClientDriverFactory) NewRPCClientDriver(driverName string, ra
 
 	addr, err := p.Address()
 	if err != nil {
-		return nil, fmt.Errorf("Error attempting to get plugin server address for RPC: %s", err)
+		return nil, nil, fmt.Errorf("Error attempting to get plugin server address for RPC: %s", err)
 	}
 
 	rpcclient, err := rpc.DialHTTP("tcp", addr)
+	if err != nil {
+		p.Close()
+		return nil, nil, err
+	}
+
+	return p, rpcclient, nil
+}
+
+func (f *DefaultRPCClientDriverFactory) NewRPCClientDriver(driverName string, rawDriver []byte) (*RPCClientDriver, error) {
+	p, rpcclient, err := startPlugin(driverName)
 	if err != nil {
 		return nil, err
 	}
 
-	c := &RPCClientDriver{
-		Client:          NewInternalClient(rpcclient),
-		heartbeatDoneCh: make(chan bool),
+	c, err := NewRPCClientDriverFromClient(rpcclient, rawDriver)
+	if err != nil {
+		p.Close()
+		return nil, err
 	}
 
+	p.MachineName = c.Client.MachineName
+	c.plugin = p
+	c.setLauncher(func() (localbinary.DriverPlugin, *rpc.Client, error) {
+		p, rpcclient, err := startPlugin(driverName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p.MachineName = c.Client.MachineName
+		return p, rpcclient, nil
+	})
+
 	f.openedDriversLock.Lock()
 	f.openedDrivers = append(f.openedDrivers, c)
 	f.openedDriversLock.Unlock()
 
+	return c, nil
+}
+
+// NewRPCClientDriverFromClient returns a driver making its calls through an
+// RPC client connected to a driver server, e.g. one served in the same
+// process rather than by a plugin binary.
+func NewRPCClientDriverFromClient(rpcclient *rpc.Client, rawDriver []byte) (*RPCClientDriver, error) {
+	c := &RPCClientDriver{
+		Client:          NewInternalClient(rpcclient),
+		heartbeatDoneCh: make(chan bool),
+	}
+
 	var serverVersion int
 	if err := c.Client.Call(GetVersionMethod, struct{}{}, &serverVersion); err != nil {
 		// this is the first call we make to the server. We try to play nice with old pre 0.5.1 client,
 		// by gracefully trying old RPCServiceName, we do this only once, and keep the result for future calls.
-		log.Debugf(err.Error())
+		log.Debug(err)
 		log.Debugf("Client (%s) with %s does not work, re-attempting with %s", c.Client.MachineName, RPCServiceNameV1, RPCServiceNameV0)
 		c.Client.switchToV0()
 		if err := c.Client.Call(GetVersionMethod, struct{}{}, &serverVersion); err != nil {
@@ -166,36 +431,170 @@ func (f *DefaultRPCClientDriverFactory) NewRPCClientDriver(driverName string, ra
 	}
 
 	if serverVersion != version.APIVersion {
-		return nil, fmt.Errorf("Driver binary uses an incompatible API version (%d)", serverVersion)
+		return nil, ErrIncompatibleAPIVersion{
+			Version: serverVersion,
+		}
 	}
 	log.Debug("Using API Version ", serverVersion)
+	c.apiVersion = serverVersion
 
-	go func(c *RPCClientDriver) {
-		for {
-			select {
-			case <-c.heartbeatDoneCh:
-				return
-			case <-time.After(heartbeatInterval):
-				if err := c.Client.Call(HeartbeatMethod, struct{}{}, nil); err != nil {
-					log.Warnf("Wrapper Docker Machine process exiting due to closed plugin server (%s)", err)
-					if err := c.close(); err != nil {
-						log.Warn(err)
-					}
+	if err := c.Client.Call(GetCapabilitiesMethod, struct{}{}, &c.capabilities); err != nil {
+		// plugins built before capabilities were introduced only support
+		// the methods of drivers.Driver
+		log.Debugf("Driver binary doesn't report its capabilities: %s", err)
+		c.capabilities = []drivers.Capability{}
+	}
+
+	driverName, err := c.rpcStringCall(DriverNameMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy drivers.ConcurrencyPolicy
+	if err := c.Client.Call(GetConcurrencyPolicyMethod, struct{}{}, &policy); err != nil {
+		log.Debugf("Driver binary doesn't declare its concurrency policy: %s", err)
+		policy = drivers.DefaultConcurrencyPolicies[driverName]
+	}
+	c.Client.limiter = limiterFor(driverName, policy)
+
+	if err := c.SetConfigRaw(rawDriver); err != nil {
+		return nil, err
+	}
+
+	c.Client.MachineName = c.GetMachineName()
+
+	go c.heartbeat(heartbeatInterval)
+
+	return c, nil
+}
+
+// heartbeat keeps the driver binary alive until the driver is closed. A
+// missed heartbeat restarts the driver binary if it can be, and closes the
+// driver otherwise.
+func (c *RPCClientDriver) heartbeat(interval time.Duration) {
+	for {
+		select {
+		case <-c.heartbeatDoneCh:
+			return
+		case <-time.After(interval):
+			if err := c.Client.Call(HeartbeatMethod, struct{}{}, nil); err != nil {
+				log.Warnf("Wrapper Docker Machine process exiting due to closed plugin server (%s)", err)
+				if err := c.close(); err != nil {
+					log.Warn(err)
 				}
+				return
 			}
 		}
-	}(c)
+	}
+}
 
-	if err := c.SetConfigRaw(rawDriver); err != nil {
-		return nil, err
+// setLauncher lets the driver restart the plugin binary serving it, with
+// the launcher, when it crashes.
+func (c *RPCClientDriver) setLauncher(launch pluginLauncher) {
+	c.Client.lock.Lock()
+	defer c.Client.lock.Unlock()
+
+	c.Client.restart = func() (*rpc.Client, error) {
+		return c.restartPlugin(launch)
+	}
+}
+
+// restartPlugin replaces the crashed plugin binary serving the driver with a
+// new one, given the last known configuration of the driver. It's called
+// with the lock of the client held.
+func (c *RPCClientDriver) restartPlugin(launch pluginLauncher) (*rpc.Client, error) {
+	if c.plugin != nil {
+		// the crashed binary only has to be reaped
+		go c.plugin.Close()
+		c.plugin = nil
+	}
+
+	p, rpcclient, err := launch()
+	if err != nil {
+		return nil, fmt.Errorf("Error restarting the driver plugin: %s", err)
+	}
+
+	c.configLock.Lock()
+	config := c.lastConfig
+	c.configLock.Unlock()
+
+	if err := rpcclient.Call(c.Client.rpcServiceName+SetConfigRawMethod, config, nil); err != nil {
+		rpcclient.Close()
+		if p != nil {
+			p.Close()
+		}
+		return nil, fmt.Errorf("Error configuring the restarted driver plugin: %s", err)
 	}
 
-	mcnName = c.GetMachineName()
-	p.MachineName = mcnName
-	c.Client.MachineName = mcnName
 	c.plugin = p
+	log.Infof("(%s) Restarted the driver plugin", c.Client.MachineName)
 
-	return c, nil
+	return rpcclient, nil
+}
+
+// setLastConfig records the configuration known to the driver binary.
+func (c *RPCClientDriver) setLastConfig(data []byte) {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	c.lastConfig = data
+}
+
+// callAndRefreshConfig makes a call that may change the configuration of
+// the driver, like its instance ID or IP, then records the new one so that a
+// restarted plugin binary isn't given a stale one.
+func (c *RPCClientDriver) callAndRefreshConfig(serviceMethod string, args interface{}, reply interface{}) error {
+	if err := c.Client.Call(serviceMethod, args, reply); err != nil {
+		return err
+	}
+
+	if _, err := c.GetConfigRaw(); err != nil {
+		log.Debugf("Error refreshing the driver configuration after %s: %s", strings.TrimPrefix(serviceMethod, "."), err)
+	}
+
+	return nil
+}
+
+// APIVersion returns the version of the plugin API served by the driver
+// binary.
+func (c *RPCClientDriver) APIVersion() int {
+	return c.apiVersion
+}
+
+// ConcurrencyPolicy returns the policy the calls to the driver binary are
+// made with.
+func (c *RPCClientDriver) ConcurrencyPolicy() drivers.ConcurrencyPolicy {
+	if c.Client.limiter == nil {
+		return drivers.ConcurrencyPolicy{}
+	}
+	return c.Client.limiter.Policy
+}
+
+// Capabilities returns the optional capabilities reported by the driver
+// binary. RPCClientDriver implements the interfaces of all of them.
+func (c *RPCClientDriver) Capabilities() []drivers.Capability {
+	return c.capabilities
+}
+
+// checkCapability returns ErrCapabilityNotSupported if the driver binary
+// didn't report the capability name, rather than making a call it can't
+// serve.
+func (c *RPCClientDriver) checkCapability(name drivers.Capability) error {
+	for _, capability := range c.capabilities {
+		if capability == name {
+			return nil
+		}
+	}
+
+	driverName, err := c.rpcStringCall(DriverNameMethod)
+	if err != nil {
+		return err
+	}
+
+	return drivers.ErrCapabilityNotSupported{
+		DriverName: driverName,
+		Capability: name,
+	}
 }
 
 func (c *RPCClientDriver) MarshalJSON() ([]byte, error) {
@@ -206,21 +605,43 @@ func (c *RPCClientDriver) UnmarshalJSON(data []byte) error {
 	return c.SetConfigRaw(data)
 }
 
+// close stops the heartbeat and closes the driver server, once. It doesn't
+// restart the plugin binary anymore.
 func (c *RPCClientDriver) close() error {
-	c.heartbeatDoneCh <- true
-	close(c.heartbeatDoneCh)
+	var err error
 
-	log.Debug("Making call to close driver server")
+	c.closeOnce.Do(func() {
+		close(c.heartbeatDoneCh)
 
-	if err := c.Client.Call(CloseMethod, struct{}{}, nil); err != nil {
-		log.Debugf("Failed to make call to close driver server: %s", err)
-	} else {
-		log.Debug("Successfully made call to close driver server")
-	}
+		c.Client.lock.Lock()
+		c.Client.restart = nil
+		plugin := c.plugin
+		c.Client.lock.Unlock()
+
+		log.Debug("Making call to close driver server")
+
+		if err := c.Client.Call(CloseMethod, struct{}{}, nil); err != nil {
+			log.Debugf("Failed to make call to close driver server: %s", err)
+		} else {
+			log.Debug("Successfully made call to close driver server")
+		}
+
+		if plugin == nil {
+			return
+		}
+
+		log.Debug("Making call to close connection to plugin binary")
+
+		err = plugin.Close()
+	})
 
-	log.Debug("Making call to close connection to plugin binary")
+	return err
+}
 
-	return c.plugin.Close()
+// Close stops the heartbeat and closes the driver server, and the plugin
+// binary serving it if any.
+func (c *RPCClientDriver) Close() error {
+	return c.close()
 }
 
 // Helper method to make requests which take no arguments and return simply a
@@ -246,7 +667,13 @@ func (c *RPCClientDriver) GetCreateFlags() []mcnflag.Flag {
 }
 
 func (c *RPCClientDriver) SetConfigRaw(data []byte) error {
-	return c.Client.Call(SetConfigRawMethod, data, nil)
+	if err := c.Client.Call(SetConfigRawMethod, data, nil); err != nil {
+		return err
+	}
+
+	c.setLastConfig(data)
+
+	return nil
 }
 
 func (c *RPCClientDriver) GetConfigRaw() ([]byte, error) {
@@ -256,6 +683,8 @@ func (c *RPCClientDriver) GetConfigRaw() ([]byte, error) {
 		return nil, err
 	}
 
+	c.setLastConfig(data)
+
 	return data, nil
 }
 
@@ -270,7 +699,7 @@ func (c *RPCClientDriver) DriverName() string {
 }
 
 func (c *RPCClientDriver) SetConfigFromFlags(flags drivers.DriverOptions) error {
-	return c.Client.Call(SetConfigFromFlagsMethod, &flags, nil)
+	return c.callAndRefreshConfig(SetConfigFromFlagsMethod, &flags, nil)
 }
 
 func (c *RPCClientDriver) GetURL() (string, error) {
@@ -335,33 +764,131 @@ func (c *RPCClientDriver) GetState() (state.State, error) {
 }
 
 func (c *RPCClientDriver) PreCreateCheck() error {
-	return c.Client.Call(PreCreateCheckMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(PreCreateCheckMethod, struct{}{}, nil)
 }
 
 func (c *RPCClientDriver) Create() error {
-	return c.Client.Call(CreateMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(CreateMethod, struct{}{}, nil)
 }
 
 func (c *RPCClientDriver) Remove() error {
-	return c.Client.Call(RemoveMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(RemoveMethod, struct{}{}, nil)
 }
 
 func (c *RPCClientDriver) Start() error {
-	return c.Client.Call(StartMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(StartMethod, struct{}{}, nil)
 }
 
 func (c *RPCClientDriver) Stop() error {
-	return c.Client.Call(StopMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(StopMethod, struct{}{}, nil)
 }
 
 func (c *RPCClientDriver) Restart() error {
-	return c.Client.Call(RestartMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(RestartMethod, struct{}{}, nil)
 }
 
 func (c *RPCClientDriver) Kill() error {
-	return c.Client.Call(KillMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(KillMethod, struct{}{}, nil)
 }
 
 func (c *RPCClientDriver) Upgrade() error {
-	return c.Client.Call(UpgradeMethod, struct{}{}, nil)
+	return c.callAndRefreshConfig(UpgradeMethod, struct{}{}, nil)
+}
+
+func (c *RPCClientDriver) CreateSnapshot(name string) error {
+	if err := c.checkCapability(drivers.CapabilitySnapshot); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(CreateSnapshotMethod, name, nil)
+}
+
+func (c *RPCClientDriver) ListSnapshots() ([]drivers.Snapshot, error) {
+	if err := c.checkCapability(drivers.CapabilitySnapshot); err != nil {
+		return nil, err
+	}
+
+	var snapshots []drivers.Snapshot
+
+	if err := c.Client.Call(ListSnapshotsMethod, struct{}{}, &snapshots); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+func (c *RPCClientDriver) RestoreSnapshot(name string) error {
+	if err := c.checkCapability(drivers.CapabilitySnapshot); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(RestoreSnapshotMethod, name, nil)
+}
+
+func (c *RPCClientDriver) RemoveSnapshot(name string) error {
+	if err := c.checkCapability(drivers.CapabilitySnapshot); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(RemoveSnapshotMethod, name, nil)
+}
+
+func (c *RPCClientDriver) Resize(opts drivers.ResizeOptions) error {
+	if err := c.checkCapability(drivers.CapabilityResize); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(ResizeMethod, opts, nil)
+}
+
+func (c *RPCClientDriver) Pause() error {
+	if err := c.checkCapability(drivers.CapabilityPause); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(PauseMethod, struct{}{}, nil)
+}
+
+func (c *RPCClientDriver) Unpause() error {
+	if err := c.checkCapability(drivers.CapabilityPause); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(UnpauseMethod, struct{}{}, nil)
+}
+
+func (c *RPCClientDriver) Suspend() error {
+	if err := c.checkCapability(drivers.CapabilitySuspend); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(SuspendMethod, struct{}{}, nil)
+}
+
+func (c *RPCClientDriver) Resume() error {
+	if err := c.checkCapability(drivers.CapabilitySuspend); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(ResumeMethod, struct{}{}, nil)
+}
+
+func (c *RPCClientDriver) AddSharedFolder(folder drivers.SharedFolder) error {
+	if err := c.checkCapability(drivers.CapabilityShare); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(AddSharedFolderMethod, folder, nil)
+}
+
+func (c *RPCClientDriver) RemoveSharedFolder(guestPath string) error {
+	if err := c.checkCapability(drivers.CapabilityShare); err != nil {
+		return err
+	}
+	return c.callAndRefreshConfig(RemoveSharedFolderMethod, guestPath, nil)
+}
+
+func (c *RPCClientDriver) ListSharedFolders() ([]drivers.SharedFolder, error) {
+	if err := c.checkCapability(drivers.CapabilityShare); err != nil {
+		return nil, err
+	}
+
+	var folders []drivers.SharedFolder
+
+	if err := c.Client.Call(ListSharedFoldersMethod, struct{}{}, &folders); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
 }
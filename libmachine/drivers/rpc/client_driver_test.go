@@ -0,0 +1,283 @@
+package rpcdriver
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/rpc"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/drivers/plugin/localbinary"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/stretchr/testify/assert"
+)
+
+// pipeServer serves a driver over an in-memory connection, the way a plugin
+// binary serves it over TCP.
+type pipeServer struct {
+	driver *fakedriver.Driver
+	conn   net.Conn
+	done   chan bool
+}
+
+func newPipeServer(d *fakedriver.Driver) (*pipeServer, *rpc.Client, error) {
+	rpcd := NewRPCServerDriver(d)
+	server := rpc.NewServer()
+	if err := server.RegisterName(RPCServiceNameV1, rpcd); err != nil {
+		return nil, nil, err
+	}
+
+	clientConn, serverConn := net.Pipe()
+	go server.ServeConn(serverConn)
+
+	s := &pipeServer{
+		driver: d,
+		conn:   serverConn,
+		done:   make(chan bool),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-rpcd.HeartbeatCh:
+			case <-rpcd.CloseCh:
+			case <-s.done:
+				return
+			}
+		}
+	}()
+
+	return s, rpc.NewClient(clientConn), nil
+}
+
+// crash breaks the connection, as the death of a plugin binary does.
+func (s *pipeServer) crash() {
+	s.conn.Close()
+	close(s.done)
+}
+
+func rawConfig(t *testing.T, name string, s state.State) []byte {
+	rawDriver, err := json.Marshal(&fakedriver.Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: name,
+		},
+		MockState: s,
+		MockIP:    "1.2.3.4",
+	})
+	assert.NoError(t, err)
+
+	return rawDriver
+}
+
+// restartingDriver returns a driver whose plugin binary is restarted by
+// serving a new fake driver.
+func restartingDriver(t *testing.T) (*RPCClientDriver, *pipeServer, func() []*pipeServer) {
+	server, rpcclient, err := newPipeServer(&fakedriver.Driver{})
+	assert.NoError(t, err)
+
+	c, err := NewRPCClientDriverFromClient(rpcclient, rawConfig(t, "default", state.Running))
+	assert.NoError(t, err)
+
+	var (
+		lock      sync.Mutex
+		restarted []*pipeServer
+	)
+
+	c.setLauncher(func() (localbinary.DriverPlugin, *rpc.Client, error) {
+		server, rpcclient, err := newPipeServer(&fakedriver.Driver{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		lock.Lock()
+		restarted = append(restarted, server)
+		lock.Unlock()
+
+		return nil, rpcclient, nil
+	})
+
+	return c, server, func() []*pipeServer {
+		lock.Lock()
+		defer lock.Unlock()
+		return restarted
+	}
+}
+
+func TestRPCClientDriverRestartsCrashedPlugin(t *testing.T) {
+	c, server, restarted := restartingDriver(t)
+	defer c.Close()
+
+	server.crash()
+
+	s, err := c.GetState()
+
+	assert.NoError(t, err)
+	assert.Equal(t, state.Running, s)
+	assert.Len(t, restarted(), 1)
+	assert.Equal(t, "default", restarted()[0].driver.MachineName)
+
+	ip, err := c.GetIP()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", ip)
+	assert.Len(t, restarted(), 1)
+}
+
+func TestRPCClientDriverRestartsWithLastConfig(t *testing.T) {
+	c, server, restarted := restartingDriver(t)
+	defer c.Close()
+
+	assert.NoError(t, c.SetConfigRaw(rawConfig(t, "renamed", state.Stopped)))
+
+	server.crash()
+
+	s, err := c.GetState()
+
+	assert.NoError(t, err)
+	assert.Equal(t, state.Stopped, s)
+	assert.Equal(t, "renamed", restarted()[0].driver.MachineName)
+}
+
+func TestRPCClientDriverRestartsWithConfigChangedByCalls(t *testing.T) {
+	c, server, restarted := restartingDriver(t)
+	defer c.Close()
+
+	assert.NoError(t, c.Stop())
+
+	server.crash()
+
+	s, err := c.GetState()
+
+	assert.NoError(t, err)
+	assert.Equal(t, state.Stopped, s)
+	assert.Equal(t, state.Stopped, restarted()[0].driver.MockState)
+}
+
+func TestRPCClientDriverDoesntRetryNonIdempotentCalls(t *testing.T) {
+	c, server, restarted := restartingDriver(t)
+	defer c.Close()
+
+	server.crash()
+
+	err := c.Stop()
+
+	assert.IsType(t, ErrPluginRestarted{}, err)
+	assert.Equal(t, StopMethod, err.(ErrPluginRestarted).Method)
+	assert.Len(t, restarted(), 1)
+	assert.Equal(t, state.Running, restarted()[0].driver.MockState)
+
+	assert.NoError(t, c.Stop())
+	assert.Equal(t, state.Stopped, restarted()[0].driver.MockState)
+}
+
+type throttledDriver struct {
+	*fakedriver.Driver
+	creates int
+	states  int
+}
+
+func (d *throttledDriver) Create() error {
+	d.creates++
+	return errors.New("RequestLimitExceeded")
+}
+
+func (d *throttledDriver) GetState() (state.State, error) {
+	d.states++
+	return state.None, errors.New("RequestLimitExceeded")
+}
+
+func TestRPCClientDriverRetriesOnlyIdempotentCalls(t *testing.T) {
+	d := &throttledDriver{Driver: &fakedriver.Driver{}}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName(RPCServiceNameV1, NewRPCServerDriver(d)))
+
+	clientConn, serverConn := net.Pipe()
+	go server.ServeConn(serverConn)
+
+	client := NewInternalClient(rpc.NewClient(clientConn))
+	defer client.RPCClient.Close()
+	client.limiter = drivers.NewLimiter(drivers.ConcurrencyPolicy{
+		MaxRetries:       2,
+		RetryBackoff:     time.Nanosecond,
+		ThrottlingErrors: []string{"RequestLimitExceeded"},
+	})
+
+	assert.EqualError(t, client.Call(CreateMethod, struct{}{}, nil), "RequestLimitExceeded")
+	assert.Equal(t, 1, d.creates)
+
+	var s state.State
+	assert.EqualError(t, client.Call(GetStateMethod, struct{}{}, &s), "RequestLimitExceeded")
+	assert.Equal(t, 3, d.states)
+}
+
+func TestRPCClientDriverPluginCantRestart(t *testing.T) {
+	c, server, _ := restartingDriver(t)
+	defer c.Close()
+
+	c.setLauncher(func() (localbinary.DriverPlugin, *rpc.Client, error) {
+		return nil, nil, assert.AnError
+	})
+
+	server.crash()
+
+	_, err := c.GetState()
+
+	assert.IsType(t, ErrPluginCrashed{}, err)
+	assert.Equal(t, GetStateMethod, err.(ErrPluginCrashed).Method)
+}
+
+func TestRPCClientDriverWithoutRestart(t *testing.T) {
+	server, rpcclient, err := newPipeServer(&fakedriver.Driver{})
+	assert.NoError(t, err)
+
+	c, err := NewRPCClientDriverFromClient(rpcclient, rawConfig(t, "default", state.Running))
+	assert.NoError(t, err)
+	defer c.Close()
+
+	server.crash()
+
+	_, err = c.GetState()
+
+	assert.True(t, isConnectionError(err), "%v isn't a connection error", err)
+}
+
+func TestRPCClientDriverHeartbeatRestartsCrashedPlugin(t *testing.T) {
+	defer func(interval time.Duration) {
+		heartbeatInterval = interval
+	}(heartbeatInterval)
+	heartbeatInterval = 10 * time.Millisecond
+
+	c, server, restarted := restartingDriver(t)
+	defer c.Close()
+
+	server.crash()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(restarted()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Len(t, restarted(), 1)
+}
+
+func TestRPCClientDriverCloseTwice(t *testing.T) {
+	_, rpcclient, err := newPipeServer(&fakedriver.Driver{})
+	assert.NoError(t, err)
+
+	c, err := NewRPCClientDriverFromClient(rpcclient, rawConfig(t, "default", state.Running))
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, c.Close())
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, isConnectionError(rpc.ErrShutdown))
+	assert.True(t, isConnectionError(&net.OpError{Op: "read", Err: assert.AnError}))
+	assert.False(t, isConnectionError(rpc.ServerError("Machine does not exist")))
+	assert.False(t, isConnectionError(nil))
+}
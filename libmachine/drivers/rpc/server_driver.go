@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/log"
@@ -33,6 +34,14 @@ func init() {
 	gob.Register(new(mcnflag.StringFlag))
 	gob.Register(new(mcnflag.StringSliceFlag))
 	gob.Register(new(mcnflag.BoolFlag))
+	gob.Register(new(mcnflag.EnumFlag))
+	gob.Register(new(mcnflag.DurationFlag))
+	gob.Register(new(mcnflag.FloatFlag))
+	gob.Register(new(mcnflag.MapFlag))
+
+	// values of the flags sent in RPCFlags
+	gob.Register(time.Duration(0))
+	gob.Register(map[string]string{})
 }
 
 type RPCFlags struct {
@@ -42,16 +51,23 @@ type RPCFlags struct {
 func (r RPCFlags) Get(key string) interface{} {
 	val, ok := r.Values[key]
 	if !ok {
-		log.Warnf("Trying to access option %s which does not exist", key)
-		log.Warn("THIS ***WILL*** CAUSE UNEXPECTED BEHAVIOR")
+		log.Debugf("Option %s was not given, its zero value is used", key)
 	}
 	return val
 }
 
+// warnType warns that the value of an option isn't of the type the driver
+// expects. Options which weren't given are left alone.
+func (r RPCFlags) warnType(key, typeName string) {
+	if val, ok := r.Values[key]; ok && val != nil {
+		log.Warnf("Option %s is a %T, not a %s", key, val, typeName)
+	}
+}
+
 func (r RPCFlags) String(key string) string {
 	val, ok := r.Get(key).(string)
 	if !ok {
-		log.Warnf("Type assertion did not go smoothly to string for key %s", key)
+		r.warnType(key, "string")
 	}
 	return val
 }
@@ -59,7 +75,7 @@ func (r RPCFlags) String(key string) string {
 func (r RPCFlags) StringSlice(key string) []string {
 	val, ok := r.Get(key).([]string)
 	if !ok {
-		log.Warnf("Type assertion did not go smoothly to string slice for key %s", key)
+		r.warnType(key, "string slice")
 	}
 	return val
 }
@@ -67,7 +83,7 @@ func (r RPCFlags) StringSlice(key string) []string {
 func (r RPCFlags) Int(key string) int {
 	val, ok := r.Get(key).(int)
 	if !ok {
-		log.Warnf("Type assertion did not go smoothly to int for key %s", key)
+		r.warnType(key, "int")
 	}
 	return val
 }
@@ -75,7 +91,31 @@ func (r RPCFlags) Int(key string) int {
 func (r RPCFlags) Bool(key string) bool {
 	val, ok := r.Get(key).(bool)
 	if !ok {
-		log.Warnf("Type assertion did not go smoothly to bool for key %s", key)
+		r.warnType(key, "bool")
+	}
+	return val
+}
+
+func (r RPCFlags) Duration(key string) time.Duration {
+	val, ok := r.Get(key).(time.Duration)
+	if !ok {
+		r.warnType(key, "duration")
+	}
+	return val
+}
+
+func (r RPCFlags) Float(key string) float64 {
+	val, ok := r.Get(key).(float64)
+	if !ok {
+		r.warnType(key, "float")
+	}
+	return val
+}
+
+func (r RPCFlags) Map(key string) map[string]string {
+	val, ok := r.Get(key).(map[string]string)
+	if !ok {
+		r.warnType(key, "map")
 	}
 	return val
 }
@@ -104,6 +144,18 @@ func (r *RPCServerDriver) GetVersion(_ *struct{}, reply *int) error {
 	return nil
 }
 
+// GetCapabilities reports the optional capabilities of the actual driver, so
+// that the client only uses the optional operations it supports.
+func (r *RPCServerDriver) GetCapabilities(_ *struct{}, reply *[]drivers.Capability) error {
+	*reply = drivers.Capabilities(r.ActualDriver)
+	return nil
+}
+
+func (r *RPCServerDriver) GetConcurrencyPolicy(_ *struct{}, reply *drivers.ConcurrencyPolicy) error {
+	*reply = drivers.GetConcurrencyPolicy(r.ActualDriver)
+	return nil
+}
+
 func (r *RPCServerDriver) GetConfigRaw(_ *struct{}, reply *[]byte) error {
 	driverData, err := json.Marshal(r.ActualDriver)
 	if err != nil {
@@ -225,3 +277,115 @@ func (r *RPCServerDriver) Heartbeat(_ *struct{}, _ *struct{}) error {
 	r.HeartbeatCh <- true
 	return nil
 }
+
+func (r *RPCServerDriver) CreateSnapshot(name string, _ *struct{}) error {
+	snapshotter, err := drivers.AsSnapshotter(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return snapshotter.CreateSnapshot(name)
+}
+
+func (r *RPCServerDriver) ListSnapshots(_ *struct{}, reply *[]drivers.Snapshot) error {
+	snapshotter, err := drivers.AsSnapshotter(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := snapshotter.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	*reply = snapshots
+
+	return nil
+}
+
+func (r *RPCServerDriver) RestoreSnapshot(name string, _ *struct{}) error {
+	snapshotter, err := drivers.AsSnapshotter(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return snapshotter.RestoreSnapshot(name)
+}
+
+func (r *RPCServerDriver) RemoveSnapshot(name string, _ *struct{}) error {
+	snapshotter, err := drivers.AsSnapshotter(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return snapshotter.RemoveSnapshot(name)
+}
+
+func (r *RPCServerDriver) Resize(opts drivers.ResizeOptions, _ *struct{}) error {
+	resizer, err := drivers.AsResizer(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return resizer.Resize(opts)
+}
+
+func (r *RPCServerDriver) Pause(_ *struct{}, _ *struct{}) error {
+	pauser, err := drivers.AsPauser(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return pauser.Pause()
+}
+
+func (r *RPCServerDriver) Unpause(_ *struct{}, _ *struct{}) error {
+	pauser, err := drivers.AsPauser(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return pauser.Unpause()
+}
+
+func (r *RPCServerDriver) Suspend(_ *struct{}, _ *struct{}) error {
+	suspender, err := drivers.AsSuspender(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return suspender.Suspend()
+}
+
+func (r *RPCServerDriver) Resume(_ *struct{}, _ *struct{}) error {
+	suspender, err := drivers.AsSuspender(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return suspender.Resume()
+}
+
+func (r *RPCServerDriver) AddSharedFolder(folder drivers.SharedFolder, _ *struct{}) error {
+	sharer, err := drivers.AsSharer(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return sharer.AddSharedFolder(folder)
+}
+
+func (r *RPCServerDriver) RemoveSharedFolder(guestPath string, _ *struct{}) error {
+	sharer, err := drivers.AsSharer(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+	return sharer.RemoveSharedFolder(guestPath)
+}
+
+func (r *RPCServerDriver) ListSharedFolders(_ *struct{}, reply *[]drivers.SharedFolder) error {
+	sharer, err := drivers.AsSharer(r.ActualDriver)
+	if err != nil {
+		return err
+	}
+
+	folders, err := sharer.ListSharedFolders()
+	if err != nil {
+		return err
+	}
+
+	*reply = folders
+
+	return nil
+}
@@ -1,10 +1,15 @@
 package rpcdriver
 
 import (
+	"bytes"
+	"encoding/gob"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnflag"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -73,3 +78,140 @@ func TestRPCServerDriverCreate(t *testing.T) {
 		assert.Equal(t, tc.expectedErr, tc.serverDriver.Create(nil, nil))
 	}
 }
+
+type capableDriver struct {
+	*fakedriver.Driver
+}
+
+func (d *capableDriver) Capabilities() []drivers.Capability {
+	return []drivers.Capability{"snapshot"}
+}
+
+func TestRPCServerDriverGetCapabilities(t *testing.T) {
+	var capabilities []drivers.Capability
+
+	err := NewRPCServerDriver(&capableDriver{&fakedriver.Driver{}}).GetCapabilities(&struct{}{}, &capabilities)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []drivers.Capability{"snapshot"}, capabilities)
+
+	err = NewRPCServerDriver(&fakedriver.Driver{}).GetCapabilities(&struct{}{}, &capabilities)
+
+	assert.NoError(t, err)
+	assert.Empty(t, capabilities)
+}
+
+func TestRPCServerDriverResizeNotSupported(t *testing.T) {
+	err := NewRPCServerDriver(&fakedriver.Driver{}).Resize(drivers.ResizeOptions{CPUs: 2}, nil)
+
+	assert.Equal(t, drivers.ErrCapabilityNotSupported{
+		DriverName: "Driver",
+		Capability: drivers.CapabilityResize,
+	}, err)
+}
+
+func TestRPCServerDriverListSharedFoldersNotSupported(t *testing.T) {
+	var folders []drivers.SharedFolder
+
+	err := NewRPCServerDriver(&fakedriver.Driver{}).ListSharedFolders(&struct{}{}, &folders)
+
+	assert.Equal(t, drivers.ErrCapabilityNotSupported{
+		DriverName: "Driver",
+		Capability: drivers.CapabilityShare,
+	}, err)
+}
+
+func TestRPCFlagsGobTypedValues(t *testing.T) {
+	flags := RPCFlags{
+		Values: map[string]interface{}{
+			"timeout": 90 * time.Second,
+			"ratio":   0.5,
+			"labels":  map[string]string{"env": "test"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, gob.NewEncoder(buf).Encode(flags))
+
+	var decoded RPCFlags
+	assert.NoError(t, gob.NewDecoder(buf).Decode(&decoded))
+
+	assert.Equal(t, 90*time.Second, decoded.Duration("timeout"))
+	assert.Equal(t, 0.5, decoded.Float("ratio"))
+	assert.Equal(t, map[string]string{"env": "test"}, decoded.Map("labels"))
+	assert.Equal(t, "", decoded.String("missing"))
+}
+
+func TestRPCFlagsGobFlags(t *testing.T) {
+	flags := []mcnflag.Flag{
+		mcnflag.EnumFlag{Name: "ui", Values: []string{"gui", "headless"}},
+		mcnflag.DurationFlag{Name: "timeout", Value: time.Minute},
+		mcnflag.FloatFlag{Name: "ratio", Value: 0.5},
+		mcnflag.MapFlag{Name: "labels", Required: true},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, gob.NewEncoder(buf).Encode(flags))
+
+	var decoded []mcnflag.Flag
+	assert.NoError(t, gob.NewDecoder(buf).Decode(&decoded))
+
+	assert.Equal(t, []mcnflag.Flag{
+		&mcnflag.EnumFlag{Name: "ui", Values: []string{"gui", "headless"}},
+		&mcnflag.DurationFlag{Name: "timeout", Value: time.Minute},
+		&mcnflag.FloatFlag{Name: "ratio", Value: 0.5},
+		&mcnflag.MapFlag{Name: "labels", Required: true},
+	}, decoded)
+}
+
+func TestRPCFlagsGobSecret(t *testing.T) {
+	flags := []mcnflag.Flag{
+		mcnflag.StringFlag{Name: "token", Secret: true},
+		mcnflag.StringFlag{Name: "region"},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, gob.NewEncoder(buf).Encode(flags))
+
+	var decoded []mcnflag.Flag
+	assert.NoError(t, gob.NewDecoder(buf).Decode(&decoded))
+
+	assert.True(t, mcnflag.IsSecret(decoded[0]))
+	assert.False(t, mcnflag.IsSecret(decoded[1]))
+}
+
+type policedDriver struct {
+	*fakedriver.Driver
+}
+
+func (d *policedDriver) ConcurrencyPolicy() drivers.ConcurrencyPolicy {
+	return drivers.ConcurrencyPolicy{MaxParallel: 2, MaxRetries: 3}
+}
+
+func TestRPCServerDriverGetConcurrencyPolicy(t *testing.T) {
+	var policy drivers.ConcurrencyPolicy
+
+	err := NewRPCServerDriver(&policedDriver{&fakedriver.Driver{}}).GetConcurrencyPolicy(&struct{}{}, &policy)
+
+	assert.NoError(t, err)
+	assert.Equal(t, drivers.ConcurrencyPolicy{MaxParallel: 2, MaxRetries: 3}, policy)
+
+	err = NewRPCServerDriver(&fakedriver.Driver{}).GetConcurrencyPolicy(&struct{}{}, &policy)
+
+	assert.NoError(t, err)
+	assert.Equal(t, drivers.ConcurrencyPolicy{}, policy)
+}
+
+func TestLimiterForOverrides(t *testing.T) {
+	defer SetConcurrencyPolicyOverrides(map[string]drivers.ConcurrencyOverride{})
+
+	parallel := 5
+	SetConcurrencyPolicyOverrides(map[string]drivers.ConcurrencyOverride{
+		"amazonec2": {MaxParallel: &parallel},
+	})
+
+	limiter := limiterFor("amazonec2", drivers.ConcurrencyPolicy{MaxRetries: 3})
+
+	assert.Equal(t, drivers.ConcurrencyPolicy{MaxParallel: 5, MaxRetries: 3}, limiter.Policy)
+	assert.True(t, limiter == limiterFor("amazonec2", drivers.ConcurrencyPolicy{}))
+}
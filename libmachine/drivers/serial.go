@@ -25,6 +25,10 @@ type SerialDriver struct {
 	sync.Locker
 }
 
+// NewSerialDriver returns the driver with all its calls serialized.
+//
+// Deprecated: the drivers loaded by libmachine are called according to
+// their ConcurrencyPolicy.
 func NewSerialDriver(innerDriver Driver) Driver {
 	return newSerialDriverWithLock(innerDriver, stdLock)
 }
@@ -36,6 +40,11 @@ func newSerialDriverWithLock(innerDriver Driver, lock sync.Locker) Driver {
 	}
 }
 
+// Capabilities returns the optional capabilities of the wrapped driver.
+func (d *SerialDriver) Capabilities() []Capability {
+	return Capabilities(d.Driver)
+}
+
 // Create a host using the driver's config
 func (d *SerialDriver) Create() error {
 	d.Lock()
@@ -170,3 +179,147 @@ func (d *SerialDriver) Stop() error {
 func (d *SerialDriver) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.Driver)
 }
+
+// CreateSnapshot saves the state of the host, see Snapshotter
+func (d *SerialDriver) CreateSnapshot(name string) error {
+	snapshotter, err := AsSnapshotter(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return snapshotter.CreateSnapshot(name)
+}
+
+// ListSnapshots returns the snapshots of the host, see Snapshotter
+func (d *SerialDriver) ListSnapshots() ([]Snapshot, error) {
+	snapshotter, err := AsSnapshotter(d.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return snapshotter.ListSnapshots()
+}
+
+// RestoreSnapshot brings the host back to a snapshot, see Snapshotter
+func (d *SerialDriver) RestoreSnapshot(name string) error {
+	snapshotter, err := AsSnapshotter(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return snapshotter.RestoreSnapshot(name)
+}
+
+// RemoveSnapshot deletes a snapshot of the host, see Snapshotter
+func (d *SerialDriver) RemoveSnapshot(name string) error {
+	snapshotter, err := AsSnapshotter(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return snapshotter.RemoveSnapshot(name)
+}
+
+// Resize changes the size of the host, see Resizer
+func (d *SerialDriver) Resize(opts ResizeOptions) error {
+	resizer, err := AsResizer(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return resizer.Resize(opts)
+}
+
+// Pause freezes the host, see Pauser
+func (d *SerialDriver) Pause() error {
+	pauser, err := AsPauser(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return pauser.Pause()
+}
+
+// Unpause lets the host run again, see Pauser
+func (d *SerialDriver) Unpause() error {
+	pauser, err := AsPauser(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return pauser.Unpause()
+}
+
+// Suspend saves the host and stops it, see Suspender
+func (d *SerialDriver) Suspend() error {
+	suspender, err := AsSuspender(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return suspender.Suspend()
+}
+
+// Resume starts the saved host, see Suspender
+func (d *SerialDriver) Resume() error {
+	suspender, err := AsSuspender(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return suspender.Resume()
+}
+
+// AddSharedFolder shares a directory with the host, see Sharer
+func (d *SerialDriver) AddSharedFolder(folder SharedFolder) error {
+	sharer, err := AsSharer(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return sharer.AddSharedFolder(folder)
+}
+
+// RemoveSharedFolder stops sharing a directory with the host, see Sharer
+func (d *SerialDriver) RemoveSharedFolder(guestPath string) error {
+	sharer, err := AsSharer(d.Driver)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return sharer.RemoveSharedFolder(guestPath)
+}
+
+// ListSharedFolders returns the directories shared with the host, see Sharer
+func (d *SerialDriver) ListSharedFolders() ([]SharedFolder, error) {
+	sharer, err := AsSharer(d.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return sharer.ListSharedFolders()
+}
@@ -0,0 +1,108 @@
+package drivers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/docker/machine/libmachine/mcnutils"
+)
+
+// CapabilityShare is supported by drivers implementing Sharer.
+const CapabilityShare Capability = "share"
+
+// SharedFolder is a directory of the host mounted in a machine.
+type SharedFolder struct {
+	// HostPath is the directory of the host.
+	HostPath string
+
+	// GuestPath is where the directory is mounted in the machine.
+	GuestPath string
+
+	// ReadOnly prevents the machine from writing to the directory.
+	ReadOnly bool
+}
+
+// ParseSharedFolder parses a shared folder given as host:guestpath[:ro] or
+// host:guestpath[:rw]. The host path may have colons, e.g. a Windows drive.
+// A guest path which isn't absolute is relative to the root directory.
+func ParseSharedFolder(s string) (SharedFolder, error) {
+	folder := SharedFolder{}
+
+	parts := strings.Split(s, ":")
+	switch parts[len(parts)-1] {
+	case "ro":
+		folder.ReadOnly = true
+		parts = parts[:len(parts)-1]
+	case "rw":
+		parts = parts[:len(parts)-1]
+	}
+
+	if len(parts) < 2 {
+		return folder, fmt.Errorf("Invalid shared folder %q, expected host:guestpath[:ro]", s)
+	}
+
+	folder.HostPath = strings.Join(parts[:len(parts)-1], ":")
+	folder.GuestPath = path.Join("/", parts[len(parts)-1])
+
+	if folder.HostPath == "" || folder.GuestPath == "/" {
+		return folder, fmt.Errorf("Invalid shared folder %q, expected host:guestpath[:ro]", s)
+	}
+	if strings.ContainsAny(folder.GuestPath, " \t\n'\"\\") {
+		return folder, fmt.Errorf("Invalid guest path %q, it can't have spaces, quotes nor backslashes", folder.GuestPath)
+	}
+
+	return folder, nil
+}
+
+// MountedCommand returns the command that succeeds when a file system is
+// mounted at guestPath in the machine. /proc/mounts escapes the spaces,
+// tabs, newlines and backslashes of mount points as octal sequences.
+func MountedCommand(guestPath string) string {
+	escaped := strings.NewReplacer(`\`, `\134`, " ", `\040`, "\t", `\011`, "\n", `\012`).Replace(guestPath)
+
+	return fmt.Sprintf("grep -qsF %s /proc/mounts", mcnutils.ShellQuote(" "+escaped+" "))
+}
+
+func (f SharedFolder) String() string {
+	if f.ReadOnly {
+		return f.HostPath + ":" + f.GuestPath + ":ro"
+	}
+
+	return f.HostPath + ":" + f.GuestPath
+}
+
+// Sharer is implemented by drivers able to mount directories of the host in
+// a machine.
+type Sharer interface {
+	// AddSharedFolder shares the directory with the machine, mounting it
+	// right away if the machine is running, and stores it in the
+	// configuration of the driver.
+	AddSharedFolder(folder SharedFolder) error
+
+	// RemoveSharedFolder unmounts the directory mounted at guestPath and
+	// stops sharing it.
+	RemoveSharedFolder(guestPath string) error
+
+	// ListSharedFolders returns the directories shared with the machine.
+	ListSharedFolders() ([]SharedFolder, error)
+}
+
+func init() {
+	RegisterCapability(CapabilityShare, func(d Driver) bool {
+		_, ok := d.(Sharer)
+		return ok
+	})
+}
+
+// AsSharer returns d as a Sharer, or ErrCapabilityNotSupported.
+func AsSharer(d Driver) (Sharer, error) {
+	if !HasCapability(d, CapabilityShare) {
+		return nil, ErrCapabilityNotSupported{
+			DriverName: d.DriverName(),
+			Capability: CapabilityShare,
+		}
+	}
+
+	return d.(Sharer), nil
+}
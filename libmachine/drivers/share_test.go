@@ -0,0 +1,46 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSharedFolder(t *testing.T) {
+	var tests = []struct {
+		shareFolder string
+		expected    SharedFolder
+	}{
+		{"/src:/src", SharedFolder{HostPath: "/src", GuestPath: "/src"}},
+		{"/src:/mnt/src:ro", SharedFolder{HostPath: "/src", GuestPath: "/mnt/src", ReadOnly: true}},
+		{"/src:/mnt/src/:rw", SharedFolder{HostPath: "/src", GuestPath: "/mnt/src"}},
+		{"dir:name", SharedFolder{HostPath: "dir", GuestPath: "/name"}},
+		{`C:\src:/src:ro`, SharedFolder{HostPath: `C:\src`, GuestPath: "/src", ReadOnly: true}},
+	}
+
+	for _, test := range tests {
+		folder, err := ParseSharedFolder(test.shareFolder)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, folder)
+	}
+}
+
+func TestParseSharedFolderInvalid(t *testing.T) {
+	for _, shareFolder := range []string{"/src", "/src:ro", ":/src", "/src:/", "/src:/my src"} {
+		_, err := ParseSharedFolder(shareFolder)
+
+		assert.Error(t, err, shareFolder)
+	}
+}
+
+func TestSharedFolderString(t *testing.T) {
+	assert.Equal(t, "/src:/src", SharedFolder{HostPath: "/src", GuestPath: "/src"}.String())
+	assert.Equal(t, `C:\src:/src:ro`, SharedFolder{HostPath: `C:\src`, GuestPath: "/src", ReadOnly: true}.String())
+}
+
+func TestMountedCommand(t *testing.T) {
+	assert.Equal(t, "grep -qsF ' /src ' /proc/mounts", MountedCommand("/src"))
+	assert.Equal(t, `grep -qsF ' /my\040src\011a\134b ' /proc/mounts`, MountedCommand("/my src\ta\\b"))
+	assert.Equal(t, `grep -qsF ' /it'\''s ' /proc/mounts`, MountedCommand("/it's"))
+}
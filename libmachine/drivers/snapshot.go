@@ -0,0 +1,48 @@
+package drivers
+
+import "time"
+
+// CapabilitySnapshot is supported by drivers implementing Snapshotter.
+const CapabilitySnapshot Capability = "snapshot"
+
+// Snapshot describes a saved state of the disks of a machine.
+type Snapshot struct {
+	Name    string
+	Created time.Time
+}
+
+// Snapshotter is implemented by drivers able to save the state of the disks
+// of a machine and to bring it back later.
+type Snapshotter interface {
+	// CreateSnapshot saves the current state of the machine as name.
+	CreateSnapshot(name string) error
+
+	// ListSnapshots returns the snapshots of the machine, oldest first.
+	ListSnapshots() ([]Snapshot, error)
+
+	// RestoreSnapshot brings the machine back to the snapshot name. The
+	// machine is stopped when it's called.
+	RestoreSnapshot(name string) error
+
+	// RemoveSnapshot deletes the snapshot name.
+	RemoveSnapshot(name string) error
+}
+
+func init() {
+	RegisterCapability(CapabilitySnapshot, func(d Driver) bool {
+		_, ok := d.(Snapshotter)
+		return ok
+	})
+}
+
+// AsSnapshotter returns d as a Snapshotter, or ErrCapabilityNotSupported.
+func AsSnapshotter(d Driver) (Snapshotter, error) {
+	if !HasCapability(d, CapabilitySnapshot) {
+		return nil, ErrCapabilityNotSupported{
+			DriverName: d.DriverName(),
+			Capability: CapabilitySnapshot,
+		}
+	}
+
+	return d.(Snapshotter), nil
+}
@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnutils"
@@ -53,6 +54,58 @@ output  : %s`, command, err, output)
 	return output, nil
 }
 
+// RunSSHCommandWithStdinFromDriver runs the command on the host of the driver,
+// feeding it with the content of stdin.
+func RunSSHCommandWithStdinFromDriver(d Driver, command string, stdin io.Reader) (string, error) {
+	client, err := GetSSHClientFromDriver(d)
+	if err != nil {
+		return "", err
+	}
+
+	stdinClient, ok := client.(ssh.StdinClient)
+	if !ok {
+		return "", fmt.Errorf("The SSH client can't feed commands with data")
+	}
+
+	log.Debugf("About to run SSH command:\n%s", command)
+
+	output, err := stdinClient.OutputWithStdin(command, stdin)
+	log.Debugf("SSH cmd err, output: %v: %s", err, output)
+	if err != nil {
+		return "", fmt.Errorf(`ssh command error:
+command : %s
+err     : %v
+output  : %s`, command, err, output)
+	}
+
+	return output, nil
+}
+
+// StreamSSHCommandWithStdinFromDriver runs the command on the host of the
+// driver, feeding it with the content of stdin and copying its output to
+// stdout and stderr while it runs.
+func StreamSSHCommandWithStdinFromDriver(d Driver, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	client, err := GetSSHClientFromDriver(d)
+	if err != nil {
+		return err
+	}
+
+	stdinClient, ok := client.(ssh.StdinClient)
+	if !ok {
+		return fmt.Errorf("The SSH client can't feed commands with data")
+	}
+
+	log.Debugf("About to run SSH command:\n%s", command)
+
+	if err := stdinClient.RunWithStdin(command, stdin, stdout, stderr); err != nil {
+		return fmt.Errorf(`ssh command error:
+command : %s
+err     : %v`, command, err)
+	}
+
+	return nil
+}
+
 func sshAvailableFunc(d Driver) func() bool {
 	return func() bool {
 		log.Debug("Getting to WaitForSSH function...")
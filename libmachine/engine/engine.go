@@ -1,21 +1,120 @@
 package engine
 
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 const (
 	DefaultPort = 2376
+
+	// DefaultRootlessPort is the port a rootless engine listens on, so
+	// that it doesn't clash with a system engine left on the host.
+	DefaultRootlessPort = 2378
+
+	// ModeRootless runs the engine as the SSH user instead of root.
+	ModeRootless = "rootless"
+
+	// ConfigModeFlags configures the engine with command line flags.
+	ConfigModeFlags = "flags"
+	// ConfigModeDaemonJSON configures the engine through daemon.json.
+	ConfigModeDaemonJSON = "daemon-json"
+)
+
+var (
+	// versionPattern matches the engine versions which can be pinned, the
+	// versions of the releases and of the distribution packages.
+	versionPattern = regexp.MustCompile(`^[0-9][0-9A-Za-z.~+-]*$`)
+
+	// Channels lists the release channels understood by the engine
+	// install script.
+	Channels = []string{
+		"stable",
+		"test",
+	}
 )
 
 type Options struct {
-	ArbitraryFlags   []string
-	DNS              []string `json:"Dns"`
-	GraphDir         string
-	Env              []string
-	Ipv6             bool
-	InsecureRegistry []string
-	Labels           []string
-	LogLevel         string
-	StorageDriver    string
-	SelinuxEnabled   bool
-	TLSVerify        bool `json:"TlsVerify"`
-	RegistryMirror   []string
-	InstallURL       string
+	ArbitraryFlags      []string
+	DNS                 []string `json:"Dns"`
+	GraphDir            string
+	Env                 []string
+	Ipv6                bool
+	InsecureRegistry    []string
+	Labels              []string
+	LogLevel            string
+	StorageDriver       string
+	SelinuxEnabled      bool
+	TLSVerify           bool `json:"TlsVerify"`
+	RegistryMirror      []string
+	InstallURL          string
+	InstallVersion      string `json:",omitempty"`
+	InstallChannel      string `json:",omitempty"`
+	InstallBundle       string `json:",omitempty"`
+	InstallBundleSHA256 string `json:",omitempty"`
+	ConfigMode          string `json:",omitempty"`
+	Mode                string `json:",omitempty"`
+}
+
+// IsValidChannel reports whether channel is a known release channel. An
+// empty channel is valid and leaves the choice to the install script.
+func IsValidChannel(channel string) bool {
+	if channel == "" {
+		return true
+	}
+
+	for _, c := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValidVersion reports whether version can be pinned. An empty version is
+// valid and means the latest one.
+func IsValidVersion(version string) bool {
+	return version == "" || versionPattern.MatchString(version)
+}
+
+// IsValidConfigMode reports whether mode is a known engine configuration
+// mode. An empty mode is valid and means flags.
+func IsValidConfigMode(mode string) bool {
+	return mode == "" || mode == ConfigModeFlags || mode == ConfigModeDaemonJSON
+}
+
+// IsValidMode reports whether mode is a known engine mode. An empty mode is
+// valid and means the engine runs as root.
+func IsValidMode(mode string) bool {
+	return mode == "" || mode == ModeRootless
+}
+
+// Rootless reports whether the engine runs as the SSH user.
+func (o Options) Rootless() bool {
+	return o.Mode == ModeRootless
+}
+
+// URL returns the URL of the engine, given the URL reported by the driver
+// of its host, which assumes a system engine.
+func (o Options) URL(driverURL string) (string, error) {
+	if !o.Rootless() || driverURL == "" {
+		return driverURL, nil
+	}
+
+	u, err := url.Parse(driverURL)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing URL: %s", err)
+	}
+
+	host := u.Host
+	if i := strings.LastIndex(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	u.Host = host + ":" + strconv.Itoa(DefaultRootlessPort)
+
+	return u.String(), nil
 }
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL(t *testing.T) {
+	url, err := Options{}.URL("tcp://1.2.3.4:2376")
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://1.2.3.4:2376", url)
+
+	url, err = Options{Mode: ModeRootless}.URL("tcp://1.2.3.4:2376")
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://1.2.3.4:2378", url)
+
+	url, err = Options{Mode: ModeRootless}.URL("tcp://[fe80::1]:2376")
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://[fe80::1]:2378", url)
+
+	url, err = Options{Mode: ModeRootless}.URL("")
+	assert.NoError(t, err)
+	assert.Empty(t, url)
+}
+
+func TestIsValidVersion(t *testing.T) {
+	assert.True(t, IsValidVersion(""))
+	assert.True(t, IsValidVersion("18.09"))
+	assert.True(t, IsValidVersion("17.03.0-ce"))
+	assert.True(t, IsValidVersion("18.09.1~3-0~ubuntu"))
+	assert.True(t, IsValidVersion("18.06.1ce"))
+	assert.False(t, IsValidVersion("latest"))
+	assert.False(t, IsValidVersion("18.09; rm -rf /"))
+	assert.False(t, IsValidVersion("18.09'"))
+}
+
+func TestIsValidMode(t *testing.T) {
+	assert.True(t, IsValidMode(""))
+	assert.True(t, IsValidMode(ModeRootless))
+	assert.False(t, IsValidMode("root"))
+}
@@ -1,6 +1,7 @@
 package host
 
 import (
+	"fmt"
 	"regexp"
 
 	"github.com/docker/machine/libmachine/auth"
@@ -47,12 +48,18 @@ type Host struct {
 }
 
 type Options struct {
-	Driver        string
-	Memory        int
-	Disk          int
-	EngineOptions *engine.Options
-	SwarmOptions  *swarm.Options
-	AuthOptions   *auth.Options
+	Driver              string
+	Memory              int
+	Disk                int
+	EngineOptions       *engine.Options
+	SwarmOptions        *swarm.Options
+	AuthOptions         *auth.Options
+	PreProvisionScript  string `json:",omitempty"`
+	PostProvisionScript string `json:",omitempty"`
+	Provisioner         string `json:",omitempty"`
+	// SecretFields are the fields of the driver configuration holding
+	// secrets, joined by dots in nested objects.
+	SecretFields []string `json:",omitempty"`
 }
 
 type Metadata struct {
@@ -107,13 +114,29 @@ func (h *Host) runActionForState(action func() error, desiredState state.State)
 	return mcnutils.WaitFor(drivers.MachineInState(h.Driver, desiredState))
 }
 
+// DetectProvisioner returns the provisioner for the host: the one it was
+// created with if given explicitly, otherwise the one detected from its
+// /etc/os-release.
+func (h *Host) DetectProvisioner() (provision.Provisioner, error) {
+	if h.HostOptions != nil && h.HostOptions.Provisioner != "" {
+		return provision.NewProvisioner(h.HostOptions.Provisioner, h.Driver)
+	}
+
+	return provision.DetectProvisioner(h.Driver)
+}
+
 func (h *Host) WaitForDocker() error {
-	provisioner, err := provision.DetectProvisioner(h.Driver)
+	provisioner, err := h.DetectProvisioner()
 	if err != nil {
 		return err
 	}
 
-	return provision.WaitForDocker(provisioner, engine.DefaultPort)
+	dockerPort := engine.DefaultPort
+	if h.engineOptions().Rootless() {
+		dockerPort = engine.DefaultRootlessPort
+	}
+
+	return provision.WaitForDocker(provisioner, dockerPort)
 }
 
 func (h *Host) Start() error {
@@ -166,7 +189,7 @@ func (h *Host) Restart() error {
 }
 
 func (h *Host) DockerVersion() (string, error) {
-	url, err := h.Driver.GetURL()
+	url, err := h.URL()
 	if err != nil {
 		return "", err
 	}
@@ -196,7 +219,7 @@ func (h *Host) Upgrade() error {
 		}
 	}
 
-	provisioner, err := provision.DetectProvisioner(h.Driver)
+	provisioner, err := h.DetectProvisioner()
 	if err != nil {
 		return err
 	}
@@ -206,6 +229,23 @@ func (h *Host) Upgrade() error {
 		return err
 	}
 
+	targetVersion := ""
+	if h.HostOptions != nil && h.HostOptions.EngineOptions != nil {
+		provisioner.SetEngineOptions(*h.HostOptions.EngineOptions)
+		targetVersion = h.HostOptions.EngineOptions.InstallVersion
+	}
+
+	if targetVersion != "" {
+		if versioncmp.Equal(dockerVersion, targetVersion) {
+			log.Infof("Docker is already at version %s, which the machine is pinned to", targetVersion)
+			return nil
+		}
+
+		if versioncmp.LessThan(targetVersion, dockerVersion) {
+			log.Warnf("Downgrading Docker from %s to %s...", dockerVersion, targetVersion)
+		}
+	}
+
 	// If we're upgrading from a pre-CE (e.g., 1.13.1) release to a CE
 	// release (e.g., 17.03.0-ce), we should simply uninstall and
 	// re-install from scratch, since the official package names will
@@ -234,7 +274,11 @@ func (h *Host) Upgrade() error {
 		return h.Provision()
 	}
 
-	log.Info("Upgrading docker...")
+	if targetVersion != "" {
+		log.Infof("Upgrading docker to %s...", targetVersion)
+	} else {
+		log.Info("Upgrading docker...")
+	}
 	if err := provisioner.Package("docker", pkgaction.Upgrade); err != nil {
 		return err
 	}
@@ -243,8 +287,22 @@ func (h *Host) Upgrade() error {
 	return provisioner.Service("docker", serviceaction.Restart)
 }
 
+// URL returns the URL of the engine of the host, which differs from the one
+// reported by its driver for a rootless engine.
 func (h *Host) URL() (string, error) {
-	return h.Driver.GetURL()
+	driverURL, err := h.Driver.GetURL()
+	if err != nil {
+		return "", err
+	}
+
+	return h.engineOptions().URL(driverURL)
+}
+
+func (h *Host) engineOptions() engine.Options {
+	if h.HostOptions == nil || h.HostOptions.EngineOptions == nil {
+		return engine.Options{}
+	}
+	return *h.HostOptions.EngineOptions
 }
 
 func (h *Host) AuthOptions() *auth.Options {
@@ -255,7 +313,7 @@ func (h *Host) AuthOptions() *auth.Options {
 }
 
 func (h *Host) ConfigureAuth() error {
-	provisioner, err := provision.DetectProvisioner(h.Driver)
+	provisioner, err := h.DetectProvisioner()
 	if err != nil {
 		return err
 	}
@@ -277,7 +335,67 @@ func (h *Host) ConfigureAllAuth() error {
 }
 
 func (h *Host) Provision() error {
-	provisioner, err := provision.DetectProvisioner(h.Driver)
+	provisioner, err := h.DetectProvisioner()
+	if err != nil {
+		return err
+	}
+
+	return h.ProvisionWith(provisioner)
+}
+
+// ProvisionWith provisions the host with the given provisioner, running the
+// user supplied pre- and post-provision scripts around it.
+func (h *Host) ProvisionWith(provisioner provision.Provisioner) error {
+	if err := provision.RunProvisionScript(provisioner, provision.PreProvisionStage, h.HostOptions.PreProvisionScript); err != nil {
+		return err
+	}
+
+	if err := provisioner.Provision(*h.HostOptions.SwarmOptions, *h.HostOptions.AuthOptions, *h.HostOptions.EngineOptions); err != nil {
+		return err
+	}
+
+	return provision.RunProvisionScript(provisioner, provision.PostProvisionStage, h.HostOptions.PostProvisionScript)
+}
+
+// PlanProvision returns what provisioning the host would do, without
+// changing anything on it.
+func (h *Host) PlanProvision() (*provision.ProvisionPlan, error) {
+	provisioner, err := h.DetectProvisioner()
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := provision.PlanProvision(provisioner, *h.HostOptions.SwarmOptions, *h.HostOptions.AuthOptions, *h.HostOptions.EngineOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.HostOptions.PreProvisionScript != "" {
+		plan.Commands = append([]string{fmt.Sprintf("run %s script %s", provision.PreProvisionStage, h.HostOptions.PreProvisionScript)}, plan.Commands...)
+	}
+
+	if h.HostOptions.PostProvisionScript != "" {
+		plan.Commands = append(plan.Commands, fmt.Sprintf("run %s script %s", provision.PostProvisionStage, h.HostOptions.PostProvisionScript))
+	}
+
+	return plan, nil
+}
+
+// CheckDrift returns the differences between the persisted configuration of
+// the host and the live configuration found on it.
+func (h *Host) CheckDrift() ([]provision.Drift, error) {
+	provisioner, err := h.DetectProvisioner()
+	if err != nil {
+		return nil, err
+	}
+
+	return provision.CheckDrift(provisioner, *h.HostOptions.SwarmOptions, *h.HostOptions.AuthOptions, *h.HostOptions.EngineOptions)
+}
+
+// FixDrift re-applies the persisted configuration of the host through its
+// provisioner. User supplied provisioning scripts are not run again.
+func (h *Host) FixDrift() error {
+	provisioner, err := h.DetectProvisioner()
 	if err != nil {
 		return err
 	}
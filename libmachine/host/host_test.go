@@ -1,12 +1,21 @@
 package host
 
 import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/docker/machine/drivers/fakedriver"
 	_ "github.com/docker/machine/drivers/none"
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/engine"
 	"github.com/docker/machine/libmachine/provision"
 	"github.com/docker/machine/libmachine/state"
+	"github.com/docker/machine/libmachine/swarm"
 )
 
 func TestValidateHostnameValid(t *testing.T) {
@@ -55,3 +64,126 @@ func TestStart(t *testing.T) {
 		t.Fatalf("Expected no error but got one: %s", err)
 	}
 }
+
+func TestProvisionWithoutScripts(t *testing.T) {
+	host := &Host{
+		Driver: &fakedriver.Driver{},
+		HostOptions: &Options{
+			EngineOptions: &engine.Options{},
+			SwarmOptions:  &swarm.Options{},
+			AuthOptions:   &auth.Options{},
+		},
+	}
+
+	if err := host.ProvisionWith(provision.NewFakeProvisioner(host.Driver)); err != nil {
+		t.Fatalf("Expected no error but got one: %s", err)
+	}
+}
+
+// scriptProvisioner records the scripts it runs and when it provisions,
+// failing to run the scripts holding fail.
+type scriptProvisioner struct {
+	*provision.FakeProvisioner
+	events []string
+}
+
+func (p *scriptProvisioner) SSHStream(command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	for _, stage := range []string{provision.PreProvisionStage, provision.PostProvisionStage} {
+		if strings.Contains(command, "docker-machine-"+stage+".sh") {
+			p.events = append(p.events, stage)
+		}
+	}
+
+	script, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		return err
+	}
+	if string(script) == "fail" {
+		return errors.New("exit status 1")
+	}
+	return nil
+}
+
+func (p *scriptProvisioner) Provision(swarmOptions swarm.Options, authOptions auth.Options, engineOptions engine.Options) error {
+	p.events = append(p.events, "provision")
+	return nil
+}
+
+func writeScript(t *testing.T, content string) string {
+	script, err := ioutil.TempFile("", "machine-script-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer script.Close()
+
+	if _, err := script.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+
+	return script.Name()
+}
+
+func newScriptHost(preProvisionScript, postProvisionScript string) *Host {
+	return &Host{
+		Driver: &fakedriver.Driver{},
+		HostOptions: &Options{
+			EngineOptions:       &engine.Options{},
+			SwarmOptions:        &swarm.Options{},
+			AuthOptions:         &auth.Options{},
+			PreProvisionScript:  preProvisionScript,
+			PostProvisionScript: postProvisionScript,
+		},
+	}
+}
+
+func TestProvisionWithScripts(t *testing.T) {
+	pre := writeScript(t, "pre")
+	defer os.Remove(pre)
+	post := writeScript(t, "post")
+	defer os.Remove(post)
+
+	provisioner := &scriptProvisioner{}
+	err := newScriptHost(pre, post).ProvisionWith(provisioner)
+
+	if err != nil {
+		t.Fatalf("Expected no error but got one: %s", err)
+	}
+	if expected := []string{"pre-provision", "provision", "post-provision"}; !reflect.DeepEqual(provisioner.events, expected) {
+		t.Fatalf("Expected %v, got %v", expected, provisioner.events)
+	}
+}
+
+func TestProvisionWithFailingScript(t *testing.T) {
+	pre := writeScript(t, "fail")
+	defer os.Remove(pre)
+	post := writeScript(t, "post")
+	defer os.Remove(post)
+
+	provisioner := &scriptProvisioner{}
+	err := newScriptHost(pre, post).ProvisionWith(provisioner)
+
+	if _, ok := err.(provision.ErrProvisionScript); !ok {
+		t.Fatalf("Expected a provision script error, got %v", err)
+	}
+	if expected := []string{"pre-provision"}; !reflect.DeepEqual(provisioner.events, expected) {
+		t.Fatalf("Expected %v, got %v", expected, provisioner.events)
+	}
+}
+
+func TestURLRootless(t *testing.T) {
+	h := &Host{
+		Driver: &fakedriver.Driver{MockIP: "1.2.3.4", MockState: state.Running},
+		HostOptions: &Options{
+			EngineOptions: &engine.Options{Mode: engine.ModeRootless},
+		},
+	}
+
+	url, err := h.URL()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "tcp://1.2.3.4:2378" {
+		t.Fatalf("Expected the rootless engine port in the URL, got %s", url)
+	}
+}
@@ -0,0 +1,68 @@
+package host
+
+import (
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+)
+
+func (h *Host) Pause() error {
+	pauser, err := drivers.AsPauser(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Pausing %q...", h.Name)
+	if err := h.runActionForState(pauser.Pause, state.Paused); err != nil {
+		return err
+	}
+
+	log.Infof("Machine %q was paused.", h.Name)
+	return nil
+}
+
+func (h *Host) Unpause() error {
+	pauser, err := drivers.AsPauser(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Unpausing %q...", h.Name)
+	if err := h.runActionForState(pauser.Unpause, state.Running); err != nil {
+		return err
+	}
+
+	log.Infof("Machine %q was unpaused.", h.Name)
+	return nil
+}
+
+func (h *Host) Suspend() error {
+	suspender, err := drivers.AsSuspender(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Suspending %q...", h.Name)
+	if err := h.runActionForState(suspender.Suspend, state.Saved); err != nil {
+		return err
+	}
+
+	log.Infof("Machine %q was suspended.", h.Name)
+	return nil
+}
+
+func (h *Host) Resume() error {
+	suspender, err := drivers.AsSuspender(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Resuming %q...", h.Name)
+	if err := h.runActionForState(suspender.Resume, state.Running); err != nil {
+		return err
+	}
+
+	log.Infof("Machine %q was resumed.", h.Name)
+
+	return h.WaitForDocker()
+}
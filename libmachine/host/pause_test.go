@@ -0,0 +1,81 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/mcnerror"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/state"
+)
+
+type suspendDriver struct {
+	*fakedriver.Driver
+}
+
+func (d *suspendDriver) Pause() error {
+	d.MockState = state.Paused
+	return nil
+}
+
+func (d *suspendDriver) Unpause() error {
+	d.MockState = state.Running
+	return nil
+}
+
+func (d *suspendDriver) Suspend() error {
+	d.MockState = state.Saved
+	return nil
+}
+
+func (d *suspendDriver) Resume() error {
+	d.MockState = state.Running
+	return nil
+}
+
+func TestSuspendResume(t *testing.T) {
+	defer provision.SetDetector(&provision.StandardDetector{})
+	provision.SetDetector(&provision.FakeDetector{
+		Provisioner: provision.NewNetstatProvisioner(),
+	})
+
+	driver := &suspendDriver{&fakedriver.Driver{MockState: state.Running}}
+	h := &Host{Driver: driver}
+
+	if err := h.Suspend(); err != nil {
+		t.Fatalf("Expected no error but got one: %s", err)
+	}
+	if driver.MockState != state.Saved {
+		t.Fatalf("Expected the machine to be saved, got %s", driver.MockState)
+	}
+
+	if err := h.Resume(); err != nil {
+		t.Fatalf("Expected no error but got one: %s", err)
+	}
+	if driver.MockState != state.Running {
+		t.Fatalf("Expected the machine to be running, got %s", driver.MockState)
+	}
+}
+
+func TestPauseAlreadyPaused(t *testing.T) {
+	h := &Host{
+		Name:   "test",
+		Driver: &suspendDriver{&fakedriver.Driver{MockState: state.Paused}},
+	}
+
+	err := h.Pause()
+
+	expected := mcnerror.ErrHostAlreadyInState{Name: "test", State: state.Paused}
+	if err != expected {
+		t.Fatalf("Expected %v but got %v", expected, err)
+	}
+}
+
+func TestPauseNotSupported(t *testing.T) {
+	h := &Host{Driver: &fakedriver.Driver{}}
+
+	if _, ok := h.Pause().(drivers.ErrCapabilityNotSupported); !ok {
+		t.Fatal("Expected the pause capability to be missing")
+	}
+}
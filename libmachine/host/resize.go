@@ -0,0 +1,111 @@
+package host
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+)
+
+// growDiskCommand grows the partition holding the data of the engine, then
+// its filesystem, to the size of its disk. growpart exits with 1 when there
+// is no room left, sfdisk is used where growpart isn't installed. The
+// partition number is split from the device with the shell alone, e.g.
+// /dev/nvme0n1p1 is partition 1 of /dev/nvme0n1.
+const growDiskCommand = `set -e
+dev=$(df /var/lib/docker | awk 'NR == 2 { print $1 }')
+part=${dev##*[!0-9]}
+disk=${dev%$part}
+case $disk in *[0-9]p) disk=${disk%p} ;; esac
+if type growpart >/dev/null 2>&1; then
+  sudo growpart "$disk" "$part" || [ $? -eq 1 ]
+elif type sfdisk >/dev/null 2>&1; then
+  echo ", +" | sudo sfdisk --no-reread -N "$part" "$disk"
+  sudo partx -u "$disk" || sudo blockdev --rereadpt "$disk" || true
+else
+  echo "Neither growpart nor sfdisk is installed to grow $dev" >&2
+  exit 1
+fi
+case $(df -T /var/lib/docker | awk 'NR == 2 { print $2 }') in
+  xfs) sudo xfs_growfs /var/lib/docker ;;
+  *) sudo resize2fs "$dev" ;;
+esac`
+
+var ErrResizeNothing = errors.New("Nothing to resize, give CPUs, memory, a disk size or a machine type")
+
+// noGrowDiskProvisioners are the provisioners of the distributions lacking
+// both growpart and sfdisk, whose data partition isn't grown.
+var noGrowDiskProvisioners = map[string]bool{
+	"boot2docker": true,
+	"rancheros":   true,
+}
+
+// ErrResizeIncomplete is returned when the driver resized the host, but
+// starting it or growing its data partition failed. The host has its new
+// size nonetheless, and must be saved.
+type ErrResizeIncomplete struct {
+	Name string
+	Err  error
+}
+
+func (e ErrResizeIncomplete) Error() string {
+	return fmt.Sprintf("Machine %q was resized, but not completely: %s", e.Name, e.Err)
+}
+
+// Resize changes the size of the host, stopping it first if needed, and
+// starts it. The partition holding the data of the engine is grown when the
+// disk is. Errors past the resize itself are ErrResizeIncomplete.
+func (h *Host) Resize(opts drivers.ResizeOptions) error {
+	if opts.IsZero() {
+		return ErrResizeNothing
+	}
+
+	resizer, err := drivers.AsResizer(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	if drivers.MachineInState(h.Driver, state.Running)() {
+		if err := h.Stop(); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Resizing %q...", h.Name)
+	if err := resizer.Resize(opts); err != nil {
+		return err
+	}
+
+	if err := h.Start(); err != nil {
+		return ErrResizeIncomplete{Name: h.Name, Err: err}
+	}
+
+	if opts.DiskSize > 0 {
+		return h.growDisk()
+	}
+
+	return nil
+}
+
+// growDisk grows the partition holding the data of the engine to the size of
+// the disk, where the distribution has the tools to.
+func (h *Host) growDisk() error {
+	provisioner, err := h.DetectProvisioner()
+	if err != nil {
+		return ErrResizeIncomplete{Name: h.Name, Err: err}
+	}
+
+	if noGrowDiskProvisioners[provisioner.String()] {
+		log.Warnf("The data partition of %q can't be grown on %s, it keeps its size until it's grown by hand", h.Name, provisioner)
+		return nil
+	}
+
+	log.Infof("Growing the data partition of %q...", h.Name)
+	if _, err := h.RunSSHCommand(growDiskCommand); err != nil {
+		return ErrResizeIncomplete{Name: h.Name, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package host
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/state"
+)
+
+type resizeDriver struct {
+	*fakedriver.Driver
+	resized drivers.ResizeOptions
+}
+
+func (d *resizeDriver) Resize(opts drivers.ResizeOptions) error {
+	if d.MockState != state.Stopped {
+		return errors.New("Expected the machine to be stopped")
+	}
+	d.resized = opts
+	return nil
+}
+
+func TestResize(t *testing.T) {
+	defer provision.SetDetector(&provision.StandardDetector{})
+	provision.SetDetector(&provision.FakeDetector{
+		Provisioner: provision.NewNetstatProvisioner(),
+	})
+
+	driver := &resizeDriver{
+		Driver: &fakedriver.Driver{MockState: state.Running},
+	}
+	h := &Host{Driver: driver}
+
+	if err := h.Resize(drivers.ResizeOptions{CPUs: 2, Memory: 2048}); err != nil {
+		t.Fatalf("Expected no error but got one: %s", err)
+	}
+	if driver.resized != (drivers.ResizeOptions{CPUs: 2, Memory: 2048}) {
+		t.Fatalf("Expected the stopped machine to be resized, got %+v", driver.resized)
+	}
+	if driver.MockState != state.Running {
+		t.Fatalf("Expected the machine to be started again, got %s", driver.MockState)
+	}
+}
+
+func TestResizeNothing(t *testing.T) {
+	h := &Host{Driver: &resizeDriver{Driver: &fakedriver.Driver{}}}
+
+	if err := h.Resize(drivers.ResizeOptions{}); err != ErrResizeNothing {
+		t.Fatalf("Expected %v but got %v", ErrResizeNothing, err)
+	}
+}
+
+type failingStartResizeDriver struct {
+	resizeDriver
+}
+
+func (d *failingStartResizeDriver) Start() error {
+	return errors.New("Unable to start")
+}
+
+func TestResizeStartFailure(t *testing.T) {
+	driver := &failingStartResizeDriver{
+		resizeDriver: resizeDriver{Driver: &fakedriver.Driver{MockState: state.Stopped}},
+	}
+	h := &Host{Name: "default", Driver: driver}
+
+	err := h.Resize(drivers.ResizeOptions{CPUs: 2})
+
+	if _, ok := err.(ErrResizeIncomplete); !ok {
+		t.Fatalf("Expected an incomplete resize, got %v", err)
+	}
+	if driver.resized != (drivers.ResizeOptions{CPUs: 2}) {
+		t.Fatalf("Expected the machine to be resized, got %+v", driver.resized)
+	}
+}
+
+// boot2dockerProvisioner passes for boot2docker, with a listening daemon.
+type boot2dockerProvisioner struct {
+	provision.Provisioner
+}
+
+func (p *boot2dockerProvisioner) String() string {
+	return "boot2docker"
+}
+
+func TestResizeDiskBoot2Docker(t *testing.T) {
+	driver := &resizeDriver{
+		Driver: &fakedriver.Driver{MockState: state.Stopped},
+	}
+	h := &Host{Name: "default", Driver: driver}
+
+	defer provision.SetDetector(&provision.StandardDetector{})
+	provision.SetDetector(&provision.FakeDetector{
+		Provisioner: &boot2dockerProvisioner{provision.NewNetstatProvisioner()},
+	})
+
+	// boot2docker can't grow its data partition, no SSH command is run
+	if err := h.Resize(drivers.ResizeOptions{DiskSize: 40000}); err != nil {
+		t.Fatalf("Expected no error but got one: %s", err)
+	}
+	if driver.resized != (drivers.ResizeOptions{DiskSize: 40000}) {
+		t.Fatalf("Expected the machine to be resized, got %+v", driver.resized)
+	}
+}
@@ -0,0 +1,35 @@
+package host
+
+import (
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+)
+
+func (h *Host) AddSharedFolder(folder drivers.SharedFolder) error {
+	sharer, err := drivers.AsSharer(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Sharing %s with %q at %s...", folder.HostPath, h.Name, folder.GuestPath)
+	return sharer.AddSharedFolder(folder)
+}
+
+func (h *Host) RemoveSharedFolder(guestPath string) error {
+	sharer, err := drivers.AsSharer(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Removing the shared folder %s of %q...", guestPath, h.Name)
+	return sharer.RemoveSharedFolder(guestPath)
+}
+
+func (h *Host) ListSharedFolders() ([]drivers.SharedFolder, error) {
+	sharer, err := drivers.AsSharer(h.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return sharer.ListSharedFolders()
+}
@@ -0,0 +1,113 @@
+package host
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/docker/machine/libmachine/cert"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+)
+
+func (h *Host) CreateSnapshot(name string) error {
+	snapshotter, err := drivers.AsSnapshotter(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := snapshotter.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Name == name {
+			return fmt.Errorf("Snapshot %q of %q already exists", name, h.Name)
+		}
+	}
+
+	log.Infof("Taking snapshot %q of %q...", name, h.Name)
+	return snapshotter.CreateSnapshot(name)
+}
+
+func (h *Host) ListSnapshots() ([]drivers.Snapshot, error) {
+	snapshotter, err := drivers.AsSnapshotter(h.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshotter.ListSnapshots()
+}
+
+func (h *Host) RemoveSnapshot(name string) error {
+	snapshotter, err := drivers.AsSnapshotter(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Removing snapshot %q of %q...", name, h.Name)
+	return snapshotter.RemoveSnapshot(name)
+}
+
+// RestoreSnapshot brings the host back to the snapshot name, stopping it
+// first if needed, and starts it. Since the host may come back with another
+// IP or with the certificates of the time of the snapshot, they are
+// configured again unless they are still valid.
+func (h *Host) RestoreSnapshot(name string) error {
+	snapshotter, err := drivers.AsSnapshotter(h.Driver)
+	if err != nil {
+		return err
+	}
+
+	previousIP := ""
+	if drivers.MachineInState(h.Driver, state.Running)() {
+		if previousIP, err = h.Driver.GetIP(); err != nil {
+			log.Debugf("Unable to get the IP of %q before the restore: %s", h.Name, err)
+		}
+
+		if err := h.Stop(); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Restoring snapshot %q of %q...", name, h.Name)
+	if err := snapshotter.RestoreSnapshot(name); err != nil {
+		return err
+	}
+
+	if err := h.Start(); err != nil {
+		return err
+	}
+
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return err
+	}
+
+	if previousIP != "" && ip != previousIP {
+		log.Infof("The IP of %q is now %s, configuring the certificates again...", h.Name, ip)
+		return h.ConfigureAuth()
+	}
+
+	valid, err := h.validCertificate()
+	if err != nil || !valid {
+		log.Infof("The certificates of %q aren't valid anymore, configuring them again...", h.Name)
+		return h.ConfigureAuth()
+	}
+
+	return nil
+}
+
+func (h *Host) validCertificate() (bool, error) {
+	hostURL, err := h.URL()
+	if err != nil {
+		return false, err
+	}
+
+	u, err := url.Parse(hostURL)
+	if err != nil {
+		return false, fmt.Errorf("Error parsing URL: %s", err)
+	}
+
+	return cert.ValidateCertificate(u.Host, h.AuthOptions())
+}
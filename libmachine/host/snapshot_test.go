@@ -0,0 +1,145 @@
+package host
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/cert"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/docker/machine/libmachine/swarm"
+)
+
+type snapshotDriver struct {
+	*fakedriver.Driver
+	restored  string
+	restoreIP string
+	snapshots []drivers.Snapshot
+}
+
+func (d *snapshotDriver) CreateSnapshot(name string) error {
+	return nil
+}
+
+func (d *snapshotDriver) ListSnapshots() ([]drivers.Snapshot, error) {
+	return append([]drivers.Snapshot{}, d.snapshots...), nil
+}
+
+func (d *snapshotDriver) RestoreSnapshot(name string) error {
+	if d.MockState != state.Stopped {
+		return errors.New("Expected the machine to be stopped")
+	}
+	d.restored = name
+	d.MockIP = d.restoreIP
+	return nil
+}
+
+func (d *snapshotDriver) RemoveSnapshot(name string) error {
+	return nil
+}
+
+type recordingProvisioner struct {
+	provision.Provisioner
+	provisioned bool
+}
+
+func (p *recordingProvisioner) Provision(swarmOptions swarm.Options, authOptions auth.Options, engineOptions engine.Options) error {
+	p.provisioned = true
+	return nil
+}
+
+type fakeCertGenerator struct {
+	cert.Generator
+	valid bool
+}
+
+func (g *fakeCertGenerator) ValidateCertificate(addr string, authOptions *auth.Options) (bool, error) {
+	return g.valid, nil
+}
+
+func newSnapshotHost(ip, restoreIP string) (*Host, *snapshotDriver) {
+	driver := &snapshotDriver{
+		Driver: &fakedriver.Driver{
+			MockState: state.Running,
+			MockIP:    ip,
+		},
+		restoreIP: restoreIP,
+	}
+
+	return &Host{
+		Name:   "test",
+		Driver: driver,
+		HostOptions: &Options{
+			EngineOptions: &engine.Options{},
+			SwarmOptions:  &swarm.Options{},
+			AuthOptions:   &auth.Options{},
+		},
+	}, driver
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	defer provision.SetDetector(&provision.StandardDetector{})
+	defer cert.SetCertGenerator(cert.NewX509CertGenerator())
+
+	cases := []struct {
+		restoreIP   string
+		validCert   bool
+		provisioned bool
+	}{
+		{"1.2.3.4", true, false},
+		{"1.2.3.4", false, true},
+		{"5.6.7.8", true, true},
+	}
+
+	for _, c := range cases {
+		provisioner := &recordingProvisioner{Provisioner: provision.NewNetstatProvisioner()}
+		provision.SetDetector(&provision.FakeDetector{Provisioner: provisioner})
+		cert.SetCertGenerator(&fakeCertGenerator{valid: c.validCert})
+
+		h, driver := newSnapshotHost("1.2.3.4", c.restoreIP)
+
+		if err := h.RestoreSnapshot("snap"); err != nil {
+			t.Fatalf("Expected no error but got one: %s", err)
+		}
+		if driver.restored != "snap" {
+			t.Fatalf("Expected the snapshot to be restored, got %q", driver.restored)
+		}
+		if driver.MockState != state.Running {
+			t.Fatalf("Expected the machine to be started again, got %s", driver.MockState)
+		}
+		if provisioner.provisioned != c.provisioned {
+			t.Fatalf("Expected auth configured to be %t with IP %s and valid cert %t", c.provisioned, c.restoreIP, c.validCert)
+		}
+	}
+}
+
+func TestCreateSnapshotDuplicateName(t *testing.T) {
+	h, driver := newSnapshotHost("1.2.3.4", "1.2.3.4")
+	driver.snapshots = []drivers.Snapshot{{Name: "snap"}}
+
+	err := h.CreateSnapshot("snap")
+
+	if err == nil || err.Error() != `Snapshot "snap" of "test" already exists` {
+		t.Fatalf("Expected the duplicate snapshot to be rejected, got %v", err)
+	}
+}
+
+func TestSnapshotNotSupported(t *testing.T) {
+	h := &Host{
+		Driver: &fakedriver.Driver{},
+	}
+
+	_, err := h.ListSnapshots()
+
+	expected := drivers.ErrCapabilityNotSupported{
+		DriverName: "Driver",
+		Capability: drivers.CapabilitySnapshot,
+	}
+	if err != expected {
+		t.Fatalf("Expected %v but got %v", expected, err)
+	}
+}
@@ -1,6 +1,8 @@
 package hosttest
 
 import (
+	"time"
+
 	"github.com/docker/machine/drivers/none"
 	"github.com/docker/machine/libmachine/auth"
 	"github.com/docker/machine/libmachine/engine"
@@ -35,6 +37,18 @@ func (d DriverOptionsMock) Bool(key string) bool {
 	return d.Data[key].(bool)
 }
 
+func (d DriverOptionsMock) Duration(key string) time.Duration {
+	return d.Data[key].(time.Duration)
+}
+
+func (d DriverOptionsMock) Float(key string) float64 {
+	return d.Data[key].(float64)
+}
+
+func (d DriverOptionsMock) Map(key string) map[string]string {
+	return d.Data[key].(map[string]string)
+}
+
 func GetTestDriverFlags() *DriverOptionsMock {
 	flags := &DriverOptionsMock{
 		Data: map[string]interface{}{
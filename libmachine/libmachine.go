@@ -17,9 +17,10 @@ import (
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnerror"
+	"github.com/docker/machine/libmachine/mcnflag"
 	"github.com/docker/machine/libmachine/mcnutils"
 	"github.com/docker/machine/libmachine/persist"
-	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/secrets"
 	"github.com/docker/machine/libmachine/ssh"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/docker/machine/libmachine/swarm"
@@ -104,15 +105,45 @@ func (api *Client) Load(name string) (*host.Host, error) {
 		return nil, err
 	}
 
-	if h.DriverName == "virtualbox" {
-		h.Driver = drivers.NewSerialDriver(d)
-	} else {
-		h.Driver = d
+	h.Driver = d
+
+	if err := migrateSecretFields(h); err != nil {
+		return nil, err
 	}
 
 	return h, nil
 }
 
+// migrateSecretFields records the secret fields of machines created before
+// they were, so that they're masked when inspected and sealed when saved.
+func migrateSecretFields(h *host.Host) error {
+	if h.HostOptions == nil || len(h.HostOptions.SecretFields) > 0 {
+		return nil
+	}
+
+	flagNames := []string{}
+	for _, f := range h.Driver.GetCreateFlags() {
+		if mcnflag.IsSecret(f) {
+			flagNames = append(flagNames, f.String())
+		}
+	}
+
+	if len(flagNames) == 0 {
+		return nil
+	}
+
+	fields, err := secrets.FindFlagFields(h.RawDriver, h.DriverName, flagNames)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) > 0 {
+		h.HostOptions.SecretFields = fields
+	}
+
+	return nil
+}
+
 // Create is the wrapper method which covers all of the boilerplate around
 // actually creating, provisioning, and persisting an instance in the store.
 func (api *Client) Create(h *host.Host) error {
@@ -163,13 +194,13 @@ func (api *Client) performCreate(h *host.Host) error {
 	}
 
 	log.Info("Detecting operating system of created instance...")
-	provisioner, err := provision.DetectProvisioner(h.Driver)
+	provisioner, err := h.DetectProvisioner()
 	if err != nil {
 		return fmt.Errorf("Error detecting OS: %s", err)
 	}
 
 	log.Infof("Provisioning with %s...", provisioner.String())
-	if err := provisioner.Provision(*h.HostOptions.SwarmOptions, *h.HostOptions.AuthOptions, *h.HostOptions.EngineOptions); err != nil {
+	if err := h.ProvisionWith(provisioner); err != nil {
 		return fmt.Errorf("Error running provisioning: %s", err)
 	}
 
@@ -39,7 +39,12 @@ func (api *FakeAPI) Exists(name string) (bool, error) {
 }
 
 func (api *FakeAPI) List() ([]string, error) {
-	return []string{}, nil
+	names := []string{}
+	for _, host := range api.Hosts {
+		names = append(names, host.Name)
+	}
+
+	return names, nil
 }
 
 func (api *FakeAPI) Load(name string) (*host.Host, error) {
@@ -1,7 +1,9 @@
 package mcndockerclient
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/docker/machine/libmachine/cert"
 	"github.com/samalba/dockerclient"
@@ -45,3 +47,69 @@ func CreateContainer(dockerHost DockerHost, config *dockerclient.ContainerConfig
 
 	return nil
 }
+
+// DockerInfo returns the system-wide information reported by the engine.
+func DockerInfo(dockerHost DockerHost) (*dockerclient.Info, error) {
+	docker, err := DockerClient(dockerHost)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := docker.Info()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to query docker info: %s", err)
+	}
+
+	return info, nil
+}
+
+// ListContainers lists the containers of the engine, including stopped ones
+// if all is set.
+func ListContainers(dockerHost DockerHost, all bool) ([]dockerclient.Container, error) {
+	docker, err := DockerClient(dockerHost)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := docker.ListContainers(all, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list containers: %s", err)
+	}
+
+	return containers, nil
+}
+
+// SwarmModeInfo is the swarm mode state of an engine.
+type SwarmModeInfo struct {
+	NodeID           string
+	LocalNodeState   string
+	ControlAvailable bool
+}
+
+// SwarmMode returns the swarm mode state of the engine. The vendored client
+// predates swarm mode, so the info endpoint is queried directly.
+func SwarmMode(dockerHost DockerHost) (*SwarmModeInfo, error) {
+	docker, err := DockerClient(dockerHost)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := docker.HTTPClient.Get(docker.URL.String() + "/info")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to query docker info: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unable to query docker info: %s", resp.Status)
+	}
+
+	info := struct {
+		Swarm SwarmModeInfo
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("Unable to parse docker info: %s", err)
+	}
+
+	return &info.Swarm, nil
+}
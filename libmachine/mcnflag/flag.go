@@ -1,6 +1,9 @@
 package mcnflag
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type Flag interface {
 	fmt.Stringer
@@ -12,6 +15,10 @@ type StringFlag struct {
 	Usage  string
 	EnvVar string
 	Value  string
+	// Secret flags are sealed when they're stored and masked when they're
+	// shown.
+	Secret   bool
+	Required bool
 }
 
 // TODO: Could this be done more succinctly using embedding?
@@ -24,10 +31,11 @@ func (f StringFlag) Default() interface{} {
 }
 
 type StringSliceFlag struct {
-	Name   string
-	Usage  string
-	EnvVar string
-	Value  []string
+	Name     string
+	Usage    string
+	EnvVar   string
+	Value    []string
+	Required bool
 }
 
 // TODO: Could this be done more succinctly using embedding?
@@ -69,3 +77,71 @@ func (f BoolFlag) String() string {
 func (f BoolFlag) Default() interface{} {
 	return nil
 }
+
+// EnumFlag is a string flag taking one of the Values.
+type EnumFlag struct {
+	Name     string
+	Usage    string
+	EnvVar   string
+	Value    string
+	Values   []string
+	Required bool
+}
+
+func (f EnumFlag) String() string {
+	return f.Name
+}
+
+func (f EnumFlag) Default() interface{} {
+	return f.Value
+}
+
+// DurationFlag takes a duration, e.g. 90s or 5m.
+type DurationFlag struct {
+	Name     string
+	Usage    string
+	EnvVar   string
+	Value    time.Duration
+	Required bool
+}
+
+func (f DurationFlag) String() string {
+	return f.Name
+}
+
+func (f DurationFlag) Default() interface{} {
+	return f.Value
+}
+
+type FloatFlag struct {
+	Name     string
+	Usage    string
+	EnvVar   string
+	Value    float64
+	Required bool
+}
+
+func (f FloatFlag) String() string {
+	return f.Name
+}
+
+func (f FloatFlag) Default() interface{} {
+	return f.Value
+}
+
+// MapFlag takes key=value pairs, it can be given several times.
+type MapFlag struct {
+	Name     string
+	Usage    string
+	EnvVar   string
+	Value    map[string]string
+	Required bool
+}
+
+func (f MapFlag) String() string {
+	return f.Name
+}
+
+func (f MapFlag) Default() interface{} {
+	return f.Value
+}
@@ -0,0 +1,140 @@
+package mcnflag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrRequired is returned when a required flag isn't given.
+type ErrRequired struct {
+	Name string
+}
+
+func (e ErrRequired) Error() string {
+	return fmt.Sprintf("The --%s flag is required", e.Name)
+}
+
+// ErrNotAllowed is returned when an enum flag is given a value it doesn't
+// allow.
+type ErrNotAllowed struct {
+	Name    string
+	Value   string
+	Allowed []string
+}
+
+func (e ErrNotAllowed) Error() string {
+	return fmt.Sprintf("%q isn't allowed for --%s, use one of: %s", e.Value, e.Name, strings.Join(e.Allowed, ", "))
+}
+
+// value returns the flag itself, the flags received over RPC are pointers.
+func value(f Flag) Flag {
+	if v := reflect.ValueOf(f); v.Kind() == reflect.Ptr && !v.IsNil() {
+		if f, ok := v.Elem().Interface().(Flag); ok {
+			return f
+		}
+	}
+
+	return f
+}
+
+// IsSecret tells whether the value of the flag is a secret.
+func IsSecret(f Flag) bool {
+	stringFlag, ok := value(f).(StringFlag)
+	return ok && stringFlag.Secret
+}
+
+// Usage returns the usage of the flag, with the values it allows and
+// whether it's required.
+func Usage(f Flag) string {
+	var (
+		usage    string
+		required bool
+	)
+
+	switch f := value(f).(type) {
+	case StringFlag:
+		usage, required = f.Usage, f.Required
+	case StringSliceFlag:
+		usage, required = f.Usage, f.Required
+	case IntFlag:
+		usage = f.Usage
+	case BoolFlag:
+		usage = f.Usage
+	case EnumFlag:
+		usage, required = f.Usage, f.Required
+		usage += fmt.Sprintf(" (%s)", strings.Join(f.Values, "|"))
+	case DurationFlag:
+		usage, required = f.Usage, f.Required
+	case FloatFlag:
+		usage, required = f.Usage, f.Required
+	case MapFlag:
+		usage, required = f.Usage, f.Required
+		usage += " (key=value)"
+	}
+
+	if required {
+		usage += " [required]"
+	}
+
+	return usage
+}
+
+// Validate checks the values given to the flags, by name.
+func Validate(flags []Flag, values map[string]interface{}) error {
+	for _, f := range flags {
+		val := values[f.String()]
+
+		switch f := value(f).(type) {
+		case StringFlag:
+			if s, _ := val.(string); f.Required && s == "" {
+				return ErrRequired{Name: f.Name}
+			}
+		case StringSliceFlag:
+			if s, _ := val.([]string); f.Required && len(s) == 0 {
+				return ErrRequired{Name: f.Name}
+			}
+		case EnumFlag:
+			s, _ := val.(string)
+			if s == "" {
+				if f.Required {
+					return ErrRequired{Name: f.Name}
+				}
+				continue
+			}
+
+			if !contains(f.Values, s) {
+				return ErrNotAllowed{
+					Name:    f.Name,
+					Value:   s,
+					Allowed: f.Values,
+				}
+			}
+		case DurationFlag:
+			if d, _ := val.(time.Duration); f.Required && d == 0 {
+				return ErrRequired{Name: f.Name}
+			}
+		case FloatFlag:
+			if x, _ := val.(float64); f.Required && x == 0 {
+				return ErrRequired{Name: f.Name}
+			}
+		case MapFlag:
+			if m, _ := val.(map[string]string); f.Required && len(m) == 0 {
+				return ErrRequired{Name: f.Name}
+			}
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package mcnflag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var validateFlags = []Flag{
+	StringFlag{
+		Name:     "token",
+		Required: true,
+	},
+	&EnumFlag{
+		Name:   "ui",
+		Values: []string{"gui", "headless"},
+	},
+	DurationFlag{
+		Name:     "timeout",
+		Required: true,
+	},
+	MapFlag{
+		Name: "labels",
+	},
+}
+
+func TestValidate(t *testing.T) {
+	err := Validate(validateFlags, map[string]interface{}{
+		"token":   "secret",
+		"ui":      "headless",
+		"timeout": 5 * time.Minute,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestValidateRequired(t *testing.T) {
+	err := Validate(validateFlags, map[string]interface{}{
+		"token":   "",
+		"timeout": 5 * time.Minute,
+	})
+
+	assert.Equal(t, ErrRequired{Name: "token"}, err)
+
+	err = Validate(validateFlags, map[string]interface{}{
+		"token":   "secret",
+		"timeout": time.Duration(0),
+	})
+
+	assert.Equal(t, ErrRequired{Name: "timeout"}, err)
+}
+
+func TestValidateNotAllowed(t *testing.T) {
+	err := Validate(validateFlags, map[string]interface{}{
+		"token":   "secret",
+		"ui":      "sdl",
+		"timeout": 5 * time.Minute,
+	})
+
+	assert.EqualError(t, err, `"sdl" isn't allowed for --ui, use one of: gui, headless`)
+}
+
+func TestUsage(t *testing.T) {
+	assert.Equal(t, "Access token [required]", Usage(&StringFlag{Usage: "Access token", Required: true}))
+	assert.Equal(t, "UI type (gui|headless)", Usage(EnumFlag{Usage: "UI type", Values: []string{"gui", "headless"}}))
+	assert.Equal(t, "Labels (key=value)", Usage(MapFlag{Usage: "Labels"}))
+	assert.Equal(t, "Memory", Usage(IntFlag{Usage: "Memory"}))
+}
+
+func TestIsSecret(t *testing.T) {
+	assert.True(t, IsSecret(StringFlag{Secret: true}))
+	assert.True(t, IsSecret(&StringFlag{Secret: true}))
+	assert.False(t, IsSecret(StringFlag{}))
+	assert.False(t, IsSecret(IntFlag{}))
+}
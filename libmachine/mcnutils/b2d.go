@@ -22,6 +22,7 @@ import (
 
 const (
 	defaultURL            = "https://api.github.com/repos/boot2docker/boot2docker/releases"
+	releaseDownloadURL    = "https://github.com/boot2docker/boot2docker/releases/download"
 	defaultISOFilename    = "boot2docker.iso"
 	defaultVolumeIDOffset = int64(0x8028)
 	versionPrefix         = "-v"
@@ -95,9 +96,18 @@ func (b *b2dReleaseGetter) filename() string {
 	return b.isoFilename
 }
 
-// getReleaseTag gets the release tag of Boot2Docker from apiURL.
+// getReleaseTag gets the release tag of Boot2Docker from apiURL, or from the
+// configured release source by default.
 func (*b2dReleaseGetter) getReleaseTag(apiURL string) (string, error) {
 	if apiURL == "" {
+		if source := getReleaseSource(); source != nil {
+			release, err := source.Latest()
+			if err != nil {
+				return "", err
+			}
+			return release.Version, nil
+		}
+
 		apiURL = defaultURL
 	}
 
@@ -152,9 +162,19 @@ func (*b2dReleaseGetter) getReleaseTag(apiURL string) (string, error) {
 	return t.TagName, nil
 }
 
-// getReleaseURL gets the latest release URL of Boot2Docker.
+// getReleaseURL gets the latest release URL of Boot2Docker. The URL of the
+// configured release source has the digest of the release as fragment, if
+// it's listed.
 func (b *b2dReleaseGetter) getReleaseURL(apiURL string) (string, error) {
 	if apiURL == "" {
+		if source := getReleaseSource(); source != nil {
+			release, err := source.Latest()
+			if err != nil {
+				return "", err
+			}
+			return releaseRef(release), nil
+		}
+
 		apiURL = defaultURL
 	}
 
@@ -191,6 +211,12 @@ Consider specifying another storage driver (e.g. 'overlay') using '--engine-stor
 	return url, nil
 }
 
+// Boot2DockerReleaseURL returns the download URL of the Boot2Docker ISO
+// shipping the given Docker engine version, e.g. "18.09.1".
+func Boot2DockerReleaseURL(engineVersion string) string {
+	return fmt.Sprintf("%s/v%s/%s", releaseDownloadURL, strings.TrimPrefix(engineVersion, "v"), defaultISOFilename)
+}
+
 func (*b2dReleaseGetter) download(dir, file, isoURL string) error {
 	u, err := url.Parse(isoURL)
 
@@ -334,13 +360,15 @@ type B2dUtils struct {
 	iso
 	storePath    string
 	imgCachePath string
+	cache        *ImageCache
 }
 
 func NewB2dUtils(storePath string) *B2dUtils {
 	imgCachePath := filepath.Join(storePath, "cache")
+	getter := &b2dReleaseGetter{isoFilename: defaultISOFilename}
 
 	return &B2dUtils{
-		releaseGetter: &b2dReleaseGetter{isoFilename: defaultISOFilename},
+		releaseGetter: getter,
 		iso: &b2dISO{
 			commonIsoPath:  filepath.Join(imgCachePath, defaultISOFilename),
 			volumeIDOffset: defaultVolumeIDOffset,
@@ -348,6 +376,7 @@ func NewB2dUtils(storePath string) *B2dUtils {
 		},
 		storePath:    storePath,
 		imgCachePath: imgCachePath,
+		cache:        newImageCache(storePath, getter),
 	}
 }
 
@@ -357,6 +386,12 @@ func (b *B2dUtils) DownloadISO(dir, file, isoURL string) error {
 	return b.download(dir, file, isoURL)
 }
 
+// DownloadFile downloads the file at the URL, or copies the local file, to
+// dir/file.
+func DownloadFile(dir, file, fileURL string) error {
+	return (&b2dReleaseGetter{}).download(dir, file, fileURL)
+}
+
 type ReaderWithProgress struct {
 	io.ReadCloser
 	out                io.Writer
@@ -396,11 +431,126 @@ func (b *B2dUtils) DownloadLatestBoot2Docker(apiURL string) error {
 		return err
 	}
 
+	isoURL, digest := SplitDigest(latestReleaseURL)
+	image, err := b.cache.Find(isoURL)
+	if err != nil {
+		return err
+	}
+	if image != nil && (digest == "" || digest == image.SHA256) {
+		return b.setDefaultImage(image)
+	}
+
 	return b.DownloadISOFromURL(latestReleaseURL)
 }
 
+// DownloadISOFromURL pulls the ISO into the image cache, and makes it the
+// default ISO. The URL may have the digest of the ISO as #sha256=<digest>
+// fragment.
 func (b *B2dUtils) DownloadISOFromURL(latestReleaseURL string) error {
-	return b.DownloadISO(b.imgCachePath, b.filename(), latestReleaseURL)
+	image, err := b.cache.Pull(SplitDigest(latestReleaseURL))
+	if err != nil {
+		return err
+	}
+
+	return b.setDefaultImage(image)
+}
+
+// PullImage pulls an ISO into the image cache, even if it's already cached.
+// The reference is a version, a URL, or the latest release when empty.
+func (b *B2dUtils) PullImage(ref, digest string) (*CachedImage, error) {
+	var (
+		releaseURL string
+		err        error
+	)
+
+	switch {
+	case IsVersionRef(ref):
+		releaseURL, err = versionRef(ref)
+	case ref == "" || isRemote(ref):
+		releaseURL, err = b.getReleaseURL(ref)
+	default:
+		return b.cache.Import(ref, digest)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	isoURL, releaseDigest := SplitDigest(releaseURL)
+	if digest == "" {
+		digest = releaseDigest
+	}
+
+	return b.cache.Pull(isoURL, digest)
+}
+
+// setDefaultImage makes the cached image the default ISO, copied to the
+// machines created without a Boot2Docker URL.
+func (b *B2dUtils) setDefaultImage(image *CachedImage) error {
+	if err := removeFileIfExists(b.path()); err != nil {
+		return err
+	}
+
+	if err := os.Link(image.Path, b.path()); err == nil {
+		return nil
+	}
+
+	return CopyFile(image.Path, b.path())
+}
+
+// cachedImage returns the cached image of an ISO reference, a version or a
+// URL with an optional #sha256=<digest> fragment, pulling it if it isn't
+// cached yet. Local ISOs aren't cached, nil is returned for them.
+func (b *B2dUtils) cachedImage(ref string) (*CachedImage, error) {
+	isoURL, digest := SplitDigest(ref)
+
+	// a version is found in the cache whichever URL it was pulled from
+	image, err := b.cache.Find(isoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// the releases pinned on GitHub are got from the configured source
+	if v := versionFromURL(isoURL); getReleaseSource() != nil && strings.HasPrefix(isoURL, releaseDownloadURL+"/") && v != unknownVersion {
+		isoURL = v
+		if image, err = b.cache.Find(isoURL); err != nil {
+			return nil, err
+		}
+	}
+
+	if IsVersionRef(isoURL) {
+		releaseURL, err := versionRef(isoURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var releaseDigest string
+		isoURL, releaseDigest = SplitDigest(releaseURL)
+		if digest == "" {
+			digest = releaseDigest
+		}
+	} else if !isRemote(isoURL) {
+		return nil, nil
+	} else {
+		// if ISO is specified, check if it matches a github releases url
+		// or fallback to a direct download
+		downloadURL, err := b.getReleaseURL(isoURL)
+		if err != nil {
+			return nil, err
+		}
+
+		if downloadURL != isoURL {
+			isoURL = downloadURL
+			if image, err = b.cache.Find(isoURL); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if image != nil && (digest == "" || digest == image.SHA256) {
+		return image, nil
+	}
+
+	return b.cache.Pull(isoURL, digest)
 }
 
 func (b *B2dUtils) UpdateISOCache(isoURL string) error {
@@ -419,8 +569,9 @@ func (b *B2dUtils) UpdateISOCache(isoURL string) error {
 			// Warn that the b2d iso won't be updated if isoURL is set
 			log.Warnf("Boot2Docker URL was explicitly set to %q at create time, so Docker Machine cannot upgrade this machine to the latest version.", isoURL)
 		}
-		// Non-default B2D are not cached
-		return nil
+
+		_, err := b.cachedImage(isoURL)
+		return err
 	}
 
 	if !exists {
@@ -438,27 +589,60 @@ func (b *B2dUtils) UpdateISOCache(isoURL string) error {
 }
 
 func (b *B2dUtils) CopyIsoToMachineDir(isoURL, machineName string) error {
-	if err := b.UpdateISOCache(isoURL); err != nil {
-		return err
-	}
-
 	// TODO: This is a bit off-color.
 	machineDir := filepath.Join(b.storePath, "machines", machineName)
 	machineIsoPath := filepath.Join(machineDir, b.filename())
 
 	// By default just copy the existing "cached" iso to the machine's directory...
 	if isoURL == "" {
+		if err := b.UpdateISOCache(isoURL); err != nil {
+			return err
+		}
+
 		log.Infof("Copying %s to %s...", b.path(), machineIsoPath)
 		return CopyFile(b.path(), machineIsoPath)
 	}
 
-	// if ISO is specified, check if it matches a github releases url or fallback to a direct download
-	downloadURL, err := b.getReleaseURL(isoURL)
+	// ...or the cached image of the specified ISO...
+	image, err := b.cachedImage(isoURL)
 	if err != nil {
 		return err
 	}
 
-	return b.DownloadISO(machineDir, b.filename(), downloadURL)
+	if image != nil {
+		log.Infof("Copying %s (%s) to %s...", image.URL, image.Version, machineIsoPath)
+		return CopyFile(image.Path, machineIsoPath)
+	}
+
+	// ...or the specified local ISO
+	localURL, digest := SplitDigest(isoURL)
+	if err := b.DownloadISO(machineDir, b.filename(), localURL); err != nil {
+		return err
+	}
+
+	return verifyDigest(machineIsoPath, localURL, digest)
+}
+
+// verifyDigest checks the SHA-256 digest of the ISO, if one is expected.
+func verifyDigest(isoPath, isoURL, digest string) error {
+	if digest == "" {
+		return nil
+	}
+
+	sum, _, err := sha256File(isoPath)
+	if err != nil {
+		return err
+	}
+
+	if sum != digest {
+		return ErrChecksumMismatch{
+			URL:      isoURL,
+			Expected: digest,
+			Actual:   sum,
+		}
+	}
+
+	return nil
 }
 
 // isLatest checks the latest release tag and
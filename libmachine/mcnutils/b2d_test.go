@@ -178,6 +178,7 @@ func TestReaderWithProgress(t *testing.T) {
 
 type mockReleaseGetter struct {
 	ver    string
+	url    string
 	apiErr error
 	verCh  chan<- string
 }
@@ -191,7 +192,7 @@ func (m *mockReleaseGetter) getReleaseTag(apiURL string) (string, error) {
 }
 
 func (m *mockReleaseGetter) getReleaseURL(apiURL string) (string, error) {
-	return "http://127.0.0.1/dummy", m.apiErr
+	return m.url, m.apiErr
 }
 
 func (m *mockReleaseGetter) download(dir, file, isoURL string) error {
@@ -248,6 +249,10 @@ func TestCopyDefaultISOToMachine(t *testing.T) {
 		{"old", true, "v0.1.0", "v1.0.0", nil, "v1.0.0"},     // old iso => updating
 	}
 
+	// the release publishes no checksums
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
 	var isopath string
 	var err error
 	verCh := make(chan string, 1)
@@ -265,12 +270,14 @@ func TestCopyDefaultISOToMachine(t *testing.T) {
 		imgCachePath := filepath.Dir(isopath)
 		storePath := filepath.Dir(imgCachePath)
 
+		getter := &mockReleaseGetter{
+			ver:    tt.latestVer,
+			url:    ts.URL + "/dummy",
+			apiErr: tt.apiErr,
+			verCh:  verCh,
+		}
 		b := &B2dUtils{
-			releaseGetter: &mockReleaseGetter{
-				ver:    tt.latestVer,
-				apiErr: tt.apiErr,
-				verCh:  verCh,
-			},
+			releaseGetter: getter,
 			iso: &mockISO{
 				isopath: isopath,
 				exist:   tt.create,
@@ -279,6 +286,7 @@ func TestCopyDefaultISOToMachine(t *testing.T) {
 			},
 			storePath:    storePath,
 			imgCachePath: imgCachePath,
+			cache:        newImageCache(storePath, getter),
 		}
 
 		dir := filepath.Join(storePath, "machines", tt.machineName)
@@ -337,3 +345,10 @@ func newDummyISO(dir, name, version string) (string, int64, error) {
 func dummyISOData(padding, version string) []byte {
 	return []byte(fmt.Sprintf("%sBoot2Docker-%s                    ", padding, version))
 }
+
+func TestBoot2DockerReleaseURL(t *testing.T) {
+	expectedURL := "https://github.com/boot2docker/boot2docker/releases/download/v18.09.1/boot2docker.iso"
+
+	assert.Equal(t, expectedURL, Boot2DockerReleaseURL("18.09.1"))
+	assert.Equal(t, expectedURL, Boot2DockerReleaseURL("v18.09.1"))
+}
@@ -0,0 +1,403 @@
+package mcnutils
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	imagesDirname     = "images"
+	imageMetadataFile = "image.json"
+	unknownVersion    = "unknown"
+	digestFragment    = "sha256="
+)
+
+var (
+	// releaseChecksumsFilename is the file listing the SHA-256 digests of
+	// the files of a release, next to them.
+	releaseChecksumsFilename = "sha256sum.txt"
+
+	versionRefRegexp  = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.]+)?$`)
+	urlVersionRegexp  = regexp.MustCompile(`/(v[0-9]+\.[0-9]+\.[0-9]+[^/]*)/[^/]+$`)
+	unsafeNameRegexp  = regexp.MustCompile(`[^0-9A-Za-z._-]`)
+	sha256DigestRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// ErrChecksumMismatch is returned when an image doesn't have the SHA-256
+// digest it was expected to have.
+type ErrChecksumMismatch struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("The SHA-256 digest of %s is %s, not %s", e.URL, e.Actual, e.Expected)
+}
+
+// CachedImage is an ISO kept in the image cache.
+type CachedImage struct {
+	Version string
+	URL     string
+	SHA256  string
+	Size    int64
+	Pulled  time.Time
+
+	// Path is where the ISO is, it isn't stored.
+	Path string `json:"-"`
+}
+
+// ImageCache keeps ISOs by version and URL, so machines can be created from
+// a pinned version, or offline.
+type ImageCache struct {
+	// Path is the directory of the cache, <store>/cache/images.
+	Path string
+
+	releaseGetter
+}
+
+// NewImageCache returns the image cache of the store.
+func NewImageCache(storePath string) *ImageCache {
+	return newImageCache(storePath, &b2dReleaseGetter{isoFilename: defaultISOFilename})
+}
+
+func newImageCache(storePath string, getter releaseGetter) *ImageCache {
+	return &ImageCache{
+		Path:          filepath.Join(storePath, "cache", imagesDirname),
+		releaseGetter: getter,
+	}
+}
+
+// IsVersionRef tells whether an ISO reference is a version, e.g. v18.09.1,
+// rather than a URL.
+func IsVersionRef(ref string) bool {
+	return versionRefRegexp.MatchString(ref)
+}
+
+// SplitDigest splits the SHA-256 digest given as a fragment of an ISO URL,
+// e.g. https://example.com/boot2docker.iso#sha256=<digest>, from the URL.
+func SplitDigest(isoURL string) (string, string) {
+	i := strings.LastIndex(isoURL, "#"+digestFragment)
+	if i == -1 {
+		return isoURL, ""
+	}
+
+	return isoURL[:i], strings.ToLower(isoURL[i+len(digestFragment)+1:])
+}
+
+// isRemote tells whether the ISO has to be downloaded, local files aren't
+// cached.
+func isRemote(isoURL string) bool {
+	u, err := url.Parse(isoURL)
+	return err == nil && u.Scheme != "" && u.Scheme != "file"
+}
+
+// key returns the directory of an image in the cache.
+func (c *ImageCache) key(version, isoURL string) string {
+	sum := sha256.Sum256([]byte(isoURL))
+	return fmt.Sprintf("%s-%s", unsafeNameRegexp.ReplaceAllString(version, "_"), hex.EncodeToString(sum[:])[:12])
+}
+
+// List returns the cached images, the most recently pulled first.
+func (c *ImageCache) List() ([]*CachedImage, error) {
+	entries, err := ioutil.ReadDir(c.Path)
+	if os.IsNotExist(err) {
+		return []*CachedImage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	images := []*CachedImage{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(c.Path, entry.Name())
+		data, err := ioutil.ReadFile(filepath.Join(dir, imageMetadataFile))
+		if err != nil {
+			// an image being pulled
+			continue
+		}
+
+		image := &CachedImage{}
+		if err := json.Unmarshal(data, image); err != nil {
+			log.Warnf("Ignoring the cached image in %s: %s", dir, err)
+			continue
+		}
+		image.Path = filepath.Join(dir, defaultISOFilename)
+
+		images = append(images, image)
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].Pulled.After(images[j].Pulled)
+	})
+
+	return images, nil
+}
+
+// Find returns the most recently pulled image matching the reference, a
+// version or a URL, or nil if there's none.
+func (c *ImageCache) Find(ref string) (*CachedImage, error) {
+	images, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	version := "v" + strings.TrimPrefix(ref, "v")
+	for _, image := range images {
+		if image.URL == ref || (IsVersionRef(ref) && image.Version == version) {
+			return image, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Pull downloads the ISO into the cache, replacing the image of the same
+// version and URL. The ISO is verified against the digest if given, or
+// against the checksums of its release if they're published.
+func (c *ImageCache) Pull(isoURL, digest string) (*CachedImage, error) {
+	if err := os.MkdirAll(c.Path, 0700); err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := ioutil.TempDir(c.Path, ".pull-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	log.Infof("Downloading %s to the image cache...", isoURL)
+	if err := c.download(tmpDir, defaultISOFilename, isoURL); err != nil {
+		return nil, err
+	}
+
+	if digest == "" {
+		digest, err = releaseChecksum(isoURL)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to get the checksum of %s: %s", isoURL, err)
+		}
+	}
+
+	return c.add(filepath.Join(tmpDir, defaultISOFilename), isoURL, digest)
+}
+
+// Import copies a local ISO into the cache, verified against the digest if
+// given.
+func (c *ImageCache) Import(isoPath, digest string) (*CachedImage, error) {
+	absPath, err := filepath.Abs(isoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(c.Path, 0700); err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := ioutil.TempDir(c.Path, ".import-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPath := filepath.Join(tmpDir, defaultISOFilename)
+	if err := CopyFile(absPath, tmpPath); err != nil {
+		return nil, err
+	}
+
+	return c.add(tmpPath, "file://"+filepath.ToSlash(absPath), digest)
+}
+
+// add moves the ISO into the cache, once verified against the digest.
+func (c *ImageCache) add(isoPath, isoURL, digest string) (*CachedImage, error) {
+	sum, size, err := sha256File(isoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest == "" {
+		log.Warnf("No checksum to verify %s against, its SHA-256 digest is %s", isoURL, sum)
+	} else if sum != strings.ToLower(digest) {
+		return nil, ErrChecksumMismatch{
+			URL:      isoURL,
+			Expected: digest,
+			Actual:   sum,
+		}
+	}
+
+	version, err := isoVersion(isoPath)
+	if err != nil {
+		log.Debugf("Unable to get the version of %s from its volume ID: %s", isoURL, err)
+		version = versionFromURL(isoURL)
+	}
+
+	image := &CachedImage{
+		Version: version,
+		URL:     isoURL,
+		SHA256:  sum,
+		Size:    size,
+		Pulled:  time.Now().UTC(),
+	}
+
+	data, err := json.MarshalIndent(image, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(c.Path, c.key(version, isoURL))
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	image.Path = filepath.Join(dir, defaultISOFilename)
+	if err := os.Rename(isoPath, image.Path); err != nil {
+		return nil, err
+	}
+
+	// the metadata is written last, an image without it isn't listed
+	if err := ioutil.WriteFile(filepath.Join(dir, imageMetadataFile), data, 0600); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// Remove removes the image from the cache.
+func (c *ImageCache) Remove(image *CachedImage) error {
+	return os.RemoveAll(filepath.Dir(image.Path))
+}
+
+// Prune removes the cached images but the keep most recently pulled ones,
+// and returns the removed images.
+func (c *ImageCache) Prune(keep int) ([]*CachedImage, error) {
+	images, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if keep < 0 {
+		keep = 0
+	}
+	if keep >= len(images) {
+		return []*CachedImage{}, nil
+	}
+
+	for _, image := range images[keep:] {
+		if err := c.Remove(image); err != nil {
+			return nil, err
+		}
+	}
+
+	return images[keep:], nil
+}
+
+// sha256File returns the hex SHA-256 digest and the size of a file.
+func sha256File(name string) (string, int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// isoVersion returns the version of a Boot2Docker ISO from its volume ID.
+func isoVersion(isoPath string) (string, error) {
+	b := &b2dISO{
+		commonIsoPath:  isoPath,
+		volumeIDOffset: defaultVolumeIDOffset,
+		volumeIDLength: defaultVolumeIDLength,
+	}
+
+	return b.version()
+}
+
+// versionFromURL returns the version in a release download URL, e.g.
+// .../releases/download/v18.09.1/boot2docker.iso.
+func versionFromURL(isoURL string) string {
+	if matches := urlVersionRegexp.FindStringSubmatch(isoURL); matches != nil {
+		return matches[1]
+	}
+
+	return unknownVersion
+}
+
+// releaseChecksum returns the digest of the ISO listed in the checksums
+// published next to it, or an empty digest if there are none. ISOs not
+// served over HTTP have no published checksums.
+func releaseChecksum(isoURL string) (string, error) {
+	u, err := url.Parse(isoURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", nil
+	}
+
+	filename := path.Base(u.Path)
+	u.Path = path.Join(path.Dir(u.Path), releaseChecksumsFilename)
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	rsp, err := getClient().Get(u.String())
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Unexpected status %q getting %s", rsp.Status, u)
+	}
+
+	return parseChecksums(rsp.Body, filename)
+}
+
+// parseChecksums returns the digest of the file in the output of sha256sum.
+func parseChecksums(r io.Reader, filename string) (string, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		digest := strings.ToLower(fields[0])
+		if strings.TrimPrefix(fields[1], "*") == filename && sha256DigestRegex.MatchString(digest) {
+			return digest, nil
+		}
+	}
+
+	return "", scanner.Err()
+}
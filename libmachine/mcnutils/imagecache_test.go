@@ -0,0 +1,275 @@
+package mcnutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// isoData mimics a Boot2Docker ISO of the given version.
+func isoData(version string) []byte {
+	return dummyISOData(strings.Repeat(" ", int(defaultVolumeIDOffset)), version)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// newReleaseServer serves the ISOs by path, with their checksums if
+// withChecksums is set.
+func newReleaseServer(isos map[string][]byte, withChecksums bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if data, ok := isos[r.URL.Path]; ok {
+			w.Write(data)
+			return
+		}
+
+		if withChecksums && strings.HasSuffix(r.URL.Path, "/"+releaseChecksumsFilename) {
+			dir := strings.TrimSuffix(r.URL.Path, releaseChecksumsFilename)
+			for name, data := range isos {
+				if strings.HasPrefix(name, dir) {
+					fmt.Fprintf(w, "%s  %s\n", sha256Hex(data), strings.TrimPrefix(name, dir))
+				}
+			}
+			return
+		}
+
+		http.NotFound(w, r)
+	}))
+}
+
+func newTestImageCache(t *testing.T) (*ImageCache, string) {
+	storePath, err := ioutil.TempDir("", "machine-images-")
+	assert.NoError(t, err)
+
+	return newImageCache(storePath, &b2dReleaseGetter{isoFilename: defaultISOFilename}), storePath
+}
+
+func TestImageCachePull(t *testing.T) {
+	iso := isoData("v1.2.3")
+	ts := newReleaseServer(map[string][]byte{"/download/v1.2.3/boot2docker.iso": iso}, true)
+	defer ts.Close()
+
+	cache, storePath := newTestImageCache(t)
+	defer os.RemoveAll(storePath)
+
+	image, err := cache.Pull(ts.URL+"/download/v1.2.3/boot2docker.iso", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.3", image.Version)
+	assert.Equal(t, sha256Hex(iso), image.SHA256)
+	assert.Equal(t, int64(len(iso)), image.Size)
+
+	data, err := ioutil.ReadFile(image.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, iso, data)
+
+	images, err := cache.List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, image.Path, images[0].Path)
+}
+
+func TestImageCachePullChecksumMismatch(t *testing.T) {
+	ts := newReleaseServer(map[string][]byte{"/download/v1.2.3/boot2docker.iso": isoData("v1.2.3")}, false)
+	defer ts.Close()
+
+	cache, storePath := newTestImageCache(t)
+	defer os.RemoveAll(storePath)
+
+	_, err := cache.Pull(ts.URL+"/download/v1.2.3/boot2docker.iso", strings.Repeat("0", 64))
+
+	assert.IsType(t, ErrChecksumMismatch{}, err)
+
+	images, err := cache.List()
+	assert.NoError(t, err)
+	assert.Empty(t, images)
+}
+
+func TestImageCachePullUnverified(t *testing.T) {
+	ts := newReleaseServer(map[string][]byte{"/custom.iso": []byte("not a boot2docker iso")}, false)
+	defer ts.Close()
+
+	cache, storePath := newTestImageCache(t)
+	defer os.RemoveAll(storePath)
+
+	image, err := cache.Pull(ts.URL+"/custom.iso", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, unknownVersion, image.Version)
+}
+
+func TestImageCachePullChecksumsUnavailable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/"+releaseChecksumsFilename) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(isoData("v1.2.3"))
+	}))
+	defer ts.Close()
+
+	cache, storePath := newTestImageCache(t)
+	defer os.RemoveAll(storePath)
+
+	_, err := cache.Pull(ts.URL+"/download/v1.2.3/boot2docker.iso", "")
+
+	assert.Error(t, err)
+
+	images, err := cache.List()
+	assert.NoError(t, err)
+	assert.Empty(t, images)
+}
+
+func TestImageCacheFind(t *testing.T) {
+	ts := newReleaseServer(map[string][]byte{
+		"/download/v1.2.3/boot2docker.iso": isoData("v1.2.3"),
+		"/download/v1.3.0/boot2docker.iso": isoData("v1.3.0"),
+	}, true)
+	defer ts.Close()
+
+	cache, storePath := newTestImageCache(t)
+	defer os.RemoveAll(storePath)
+
+	for _, version := range []string{"v1.2.3", "v1.3.0"} {
+		_, err := cache.Pull(ts.URL+"/download/"+version+"/boot2docker.iso", "")
+		assert.NoError(t, err)
+	}
+
+	image, err := cache.Find("1.2.3")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.3", image.Version)
+
+	image, err = cache.Find(ts.URL + "/download/v1.3.0/boot2docker.iso")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.3.0", image.Version)
+
+	image, err = cache.Find("v2.0.0")
+	assert.NoError(t, err)
+	assert.Nil(t, image)
+}
+
+func TestImageCacheImport(t *testing.T) {
+	cache, storePath := newTestImageCache(t)
+	defer os.RemoveAll(storePath)
+
+	iso := isoData("v1.2.3")
+	isoPath := filepath.Join(storePath, "local.iso")
+	assert.NoError(t, ioutil.WriteFile(isoPath, iso, 0644))
+
+	_, err := cache.Import(isoPath, strings.Repeat("0", 64))
+	assert.IsType(t, ErrChecksumMismatch{}, err)
+
+	image, err := cache.Import(isoPath, strings.ToUpper(sha256Hex(iso)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.3", image.Version)
+	assert.Equal(t, "file://"+filepath.ToSlash(isoPath), image.URL)
+}
+
+func TestImageCachePrune(t *testing.T) {
+	cache, storePath := newTestImageCache(t)
+	defer os.RemoveAll(storePath)
+
+	for _, version := range []string{"v1.0.0", "v1.1.0", "v1.2.0"} {
+		isoPath := filepath.Join(storePath, version+".iso")
+		assert.NoError(t, ioutil.WriteFile(isoPath, isoData(version), 0644))
+
+		_, err := cache.Import(isoPath, "")
+		assert.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	removed, err := cache.Prune(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, removed, 2)
+
+	images, err := cache.List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, "v1.2.0", images[0].Version)
+
+	removed, err = cache.Prune(0)
+
+	assert.NoError(t, err)
+	assert.Len(t, removed, 1)
+}
+
+func TestCopyPinnedISOToMachine(t *testing.T) {
+	iso := isoData("v1.2.3")
+	ts := newReleaseServer(map[string][]byte{"/custom/boot2docker.iso": iso}, false)
+	defer ts.Close()
+
+	storePath, err := ioutil.TempDir("", "machine-images-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(storePath)
+
+	b := NewB2dUtils(storePath)
+	isoURL := ts.URL + "/custom/boot2docker.iso"
+
+	err = b.CopyIsoToMachineDir(isoURL+"#sha256="+strings.Repeat("0", 64), "bad")
+	assert.IsType(t, ErrChecksumMismatch{}, err)
+
+	for _, machineName := range []string{"first", "second"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(storePath, "machines", machineName), 0700))
+
+		err := b.CopyIsoToMachineDir(isoURL+"#sha256="+sha256Hex(iso), machineName)
+		assert.NoError(t, err)
+
+		data, err := ioutil.ReadFile(filepath.Join(storePath, "machines", machineName, defaultISOFilename))
+		assert.NoError(t, err)
+		assert.Equal(t, iso, data)
+	}
+
+	images, err := b.cache.List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, isoURL, images[0].URL)
+
+	// the cached image is used, the server isn't needed anymore
+	ts.Close()
+	assert.NoError(t, os.MkdirAll(filepath.Join(storePath, "machines", "offline"), 0700))
+
+	assert.NoError(t, b.CopyIsoToMachineDir("v1.2.3", "offline"))
+}
+
+func TestSplitDigest(t *testing.T) {
+	isoURL, digest := SplitDigest("https://example.com/boot2docker.iso#sha256=ABCDEF")
+
+	assert.Equal(t, "https://example.com/boot2docker.iso", isoURL)
+	assert.Equal(t, "abcdef", digest)
+
+	isoURL, digest = SplitDigest("https://example.com/boot2docker.iso")
+
+	assert.Equal(t, "https://example.com/boot2docker.iso", isoURL)
+	assert.Empty(t, digest)
+}
+
+func TestIsVersionRef(t *testing.T) {
+	assert.True(t, IsVersionRef("v18.09.1"))
+	assert.True(t, IsVersionRef("18.09.1"))
+	assert.True(t, IsVersionRef("v18.09.1-rc1"))
+	assert.False(t, IsVersionRef("https://example.com/v18.09.1/boot2docker.iso"))
+	assert.False(t, IsVersionRef("/tmp/boot2docker.iso"))
+}
+
+func TestParseChecksums(t *testing.T) {
+	digest := strings.Repeat("a", 64)
+
+	found, err := parseChecksums(strings.NewReader(strings.Repeat("b", 64)+"  other.iso\n"+digest+" *boot2docker.iso\n"), "boot2docker.iso")
+
+	assert.NoError(t, err)
+	assert.Equal(t, digest, found)
+}
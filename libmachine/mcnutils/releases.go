@@ -0,0 +1,216 @@
+package mcnutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/machine/libmachine/versioncmp"
+	"github.com/docker/machine/version"
+)
+
+const (
+	// ReleaseSourceEnvVar is the environment variable the release source
+	// is read from when B2dReleaseSource isn't set.
+	ReleaseSourceEnvVar = "MACHINE_B2D_RELEASE_SOURCE"
+
+	releaseIndexFilename = "index.json"
+)
+
+// B2dReleaseSource lists the Boot2Docker releases instead of the GitHub API.
+// It's the URL of an index file, the base URL of a mirror serving one, or
+// the path of a local index file or of the directory holding it.
+var B2dReleaseSource string
+
+// Release is a Boot2Docker release listed by a release source.
+type Release struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+	SHA256  string `json:"sha256,omitempty"`
+}
+
+// ReleaseSource lists the Boot2Docker releases.
+type ReleaseSource interface {
+	// Latest returns the latest release.
+	Latest() (*Release, error)
+	// Get returns the release of a version.
+	Get(version string) (*Release, error)
+}
+
+// getReleaseSource returns the configured release source, or nil to use the
+// GitHub API. The environment variable is read too, since the drivers run in
+// their own processes.
+func getReleaseSource() ReleaseSource {
+	location := B2dReleaseSource
+	if location == "" {
+		location = os.Getenv(ReleaseSourceEnvVar)
+	}
+
+	if location == "" {
+		return nil
+	}
+
+	return &IndexReleaseSource{
+		Location: location,
+	}
+}
+
+// IndexReleaseSource lists the releases of an index file, e.g.
+//
+//	{"releases": [{"version": "v18.09.1", "url": "v18.09.1/boot2docker.iso", "sha256": "..."}]}
+//
+// The URLs of the releases are relative to the index.
+type IndexReleaseSource struct {
+	// Location is the URL of the index, the base URL of a mirror serving
+	// index.json, or the path of the index or of its directory.
+	Location string
+}
+
+// indexURL returns the URL of the index file.
+func (s *IndexReleaseSource) indexURL() (*url.URL, error) {
+	if u, err := url.Parse(s.Location); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		if !strings.HasSuffix(u.Path, ".json") {
+			u.Path = path.Join(u.Path, releaseIndexFilename)
+		}
+		return u, nil
+	}
+
+	indexPath, err := filepath.Abs(strings.TrimPrefix(s.Location, "file://"))
+	if err != nil {
+		return nil, err
+	}
+
+	if fi, err := os.Stat(indexPath); err == nil && fi.IsDir() {
+		indexPath = filepath.Join(indexPath, releaseIndexFilename)
+	}
+
+	return &url.URL{
+		Scheme: "file",
+		Path:   filepath.ToSlash(indexPath),
+	}, nil
+}
+
+// releases returns the releases of the index, with absolute URLs.
+func (s *IndexReleaseSource) releases() ([]*Release, error) {
+	indexURL, err := s.indexURL()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if indexURL.Scheme == "file" {
+		data, err = ioutil.ReadFile(filepath.FromSlash(indexURL.Path))
+	} else {
+		data, err = getIndex(indexURL.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the Boot2Docker release index %s: %s", indexURL, err)
+	}
+
+	var index struct {
+		Releases []*Release `json:"releases"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("Error parsing the Boot2Docker release index %s: %s", indexURL, err)
+	}
+
+	for _, release := range index.Releases {
+		releaseURL, err := url.Parse(release.URL)
+		if err != nil {
+			return nil, err
+		}
+		release.URL = indexURL.ResolveReference(releaseURL).String()
+		release.Version = "v" + strings.TrimPrefix(release.Version, "v")
+	}
+
+	return index.Releases, nil
+}
+
+func getIndex(indexURL string) ([]byte, error) {
+	rsp, err := getClient().Get(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", rsp.Status)
+	}
+
+	return ioutil.ReadAll(rsp.Body)
+}
+
+// Latest returns the release of the highest version. Release candidates are
+// only considered by release candidates of Machine, like with GitHub.
+func (s *IndexReleaseSource) Latest() (*Release, error) {
+	releases, err := s.releases()
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *Release
+	for _, release := range releases {
+		if strings.Contains(release.Version, "-rc") && !version.RC() {
+			continue
+		}
+
+		if latest == nil || versioncmp.GreaterThan(strings.TrimPrefix(release.Version, "v"), strings.TrimPrefix(latest.Version, "v")) {
+			latest = release
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("No Boot2Docker release listed in %s", s.Location)
+	}
+
+	return latest, nil
+}
+
+// Get returns the release of the version.
+func (s *IndexReleaseSource) Get(v string) (*Release, error) {
+	releases, err := s.releases()
+	if err != nil {
+		return nil, err
+	}
+
+	v = "v" + strings.TrimPrefix(v, "v")
+	for _, release := range releases {
+		if release.Version == v {
+			return release, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No Boot2Docker release %s listed in %s", v, s.Location)
+}
+
+// releaseRef returns the URL of the release, with its digest as fragment if
+// it's known.
+func releaseRef(release *Release) string {
+	if release.SHA256 == "" {
+		return release.URL
+	}
+
+	return release.URL + "#" + digestFragment + release.SHA256
+}
+
+// versionRef returns the reference of the release of a version, from the
+// configured release source or from GitHub.
+func versionRef(v string) (string, error) {
+	source := getReleaseSource()
+	if source == nil {
+		return Boot2DockerReleaseURL(v), nil
+	}
+
+	release, err := source.Get(v)
+	if err != nil {
+		return "", err
+	}
+
+	return releaseRef(release), nil
+}
@@ -0,0 +1,188 @@
+package mcnutils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/machine/version"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMirror serves an index of the releases and their ISOs, the way a
+// corporate mirror does.
+func newMirror(isos map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/b2d/index.json" {
+			fmt.Fprint(w, `{"releases": [`)
+			sep := ""
+			for v, data := range isos {
+				fmt.Fprintf(w, `%s{"version": %q, "url": "%s/boot2docker.iso", "sha256": %q}`, sep, v, v, sha256Hex(data))
+				sep = ","
+			}
+			fmt.Fprint(w, `]}`)
+			return
+		}
+
+		for v, data := range isos {
+			if r.URL.Path == "/b2d/"+v+"/boot2docker.iso" {
+				w.Write(data)
+				return
+			}
+		}
+
+		http.NotFound(w, r)
+	}))
+}
+
+func withReleaseSource(location string) func() {
+	B2dReleaseSource = location
+	return func() {
+		B2dReleaseSource = ""
+	}
+}
+
+func TestIndexReleaseSourceMirror(t *testing.T) {
+	ts := newMirror(map[string][]byte{
+		"v1.2.0":      isoData("v1.2.0"),
+		"v1.10.0":     isoData("v1.10.0"),
+		"v1.11.0-rc1": isoData("v1.11.0-rc1"),
+	})
+	defer ts.Close()
+
+	source := &IndexReleaseSource{Location: ts.URL + "/b2d"}
+
+	release, err := source.Latest()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.10.0", release.Version)
+	assert.Equal(t, ts.URL+"/b2d/v1.10.0/boot2docker.iso", release.URL)
+	assert.Equal(t, sha256Hex(isoData("v1.10.0")), release.SHA256)
+
+	release, err = source.Get("1.2.0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ts.URL+"/b2d/v1.2.0/boot2docker.iso", release.URL)
+
+	_, err = source.Get("v2.0.0")
+
+	assert.Error(t, err)
+}
+
+func TestIndexReleaseSourceRC(t *testing.T) {
+	defer func(v string) { version.Version = v }(version.Version)
+	version.Version = "v0.17.0-rc1"
+
+	ts := newMirror(map[string][]byte{
+		"v1.10.0":     isoData("v1.10.0"),
+		"v1.11.0-rc1": isoData("v1.11.0-rc1"),
+	})
+	defer ts.Close()
+
+	release, err := (&IndexReleaseSource{Location: ts.URL + "/b2d/index.json"}).Latest()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.11.0-rc1", release.Version)
+}
+
+func TestIndexReleaseSourceLocalDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "machine-releases-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	index := `{"releases": [{"version": "1.2.0", "url": "isos/boot2docker-1.2.0.iso"}]}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, releaseIndexFilename), []byte(index), 0644))
+
+	release, err := (&IndexReleaseSource{Location: dir}).Latest()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.0", release.Version)
+	assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(dir, "isos", "boot2docker-1.2.0.iso")), release.URL)
+}
+
+func TestIndexReleaseSourceUnreachable(t *testing.T) {
+	ts := newMirror(map[string][]byte{})
+	ts.Close()
+
+	_, err := (&IndexReleaseSource{Location: ts.URL + "/b2d"}).Latest()
+
+	assert.Error(t, err)
+}
+
+func TestUpdateISOCacheFromMirror(t *testing.T) {
+	iso := isoData("v1.10.0")
+	ts := newMirror(map[string][]byte{
+		"v1.2.0":  isoData("v1.2.0"),
+		"v1.10.0": iso,
+	})
+	defer ts.Close()
+	defer withReleaseSource(ts.URL + "/b2d")()
+
+	storePath, err := ioutil.TempDir("", "machine-releases-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(storePath)
+
+	b := NewB2dUtils(storePath)
+
+	assert.NoError(t, b.UpdateISOCache(""))
+
+	ver, err := b.version()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.10.0", ver)
+	assert.True(t, b.isLatest())
+
+	images, err := b.cache.List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, ts.URL+"/b2d/v1.10.0/boot2docker.iso", images[0].URL)
+	assert.Equal(t, sha256Hex(iso), images[0].SHA256)
+}
+
+func TestCopyPinnedReleaseFromMirror(t *testing.T) {
+	ts := newMirror(map[string][]byte{
+		"v1.2.0":  isoData("v1.2.0"),
+		"v1.10.0": isoData("v1.10.0"),
+	})
+	defer ts.Close()
+	defer withReleaseSource(ts.URL + "/b2d")()
+
+	storePath, err := ioutil.TempDir("", "machine-releases-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(storePath)
+
+	b := NewB2dUtils(storePath)
+
+	for _, machineName := range []string{"by-version", "by-github-url"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(storePath, "machines", machineName), 0700))
+	}
+
+	assert.NoError(t, b.CopyIsoToMachineDir("v1.2.0", "by-version"))
+	assert.NoError(t, b.CopyIsoToMachineDir(Boot2DockerReleaseURL("1.2.0"), "by-github-url"))
+
+	for _, machineName := range []string{"by-version", "by-github-url"} {
+		data, err := ioutil.ReadFile(filepath.Join(storePath, "machines", machineName, defaultISOFilename))
+		assert.NoError(t, err)
+		assert.Equal(t, isoData("v1.2.0"), data)
+	}
+
+	images, err := b.cache.List()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+}
+
+func TestGetReleaseSourceFromEnv(t *testing.T) {
+	defer os.Setenv(ReleaseSourceEnvVar, os.Getenv(ReleaseSourceEnvVar))
+
+	os.Setenv(ReleaseSourceEnvVar, "")
+	assert.Nil(t, getReleaseSource())
+
+	os.Setenv(ReleaseSourceEnvVar, "https://mirror.example.com/b2d")
+	assert.Equal(t, &IndexReleaseSource{Location: "https://mirror.example.com/b2d"}, getReleaseSource())
+
+	defer withReleaseSource("/srv/b2d")()
+	assert.Equal(t, &IndexReleaseSource{Location: "/srv/b2d"}, getReleaseSource())
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -131,3 +132,9 @@ func GenerateRandomID() string {
 		return value
 	}
 }
+
+// ShellQuote returns s quoted for a POSIX shell, which then takes it as a
+// single word whatever it holds.
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
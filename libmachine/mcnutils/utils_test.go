@@ -99,3 +99,19 @@ func TestShortenIdInvalid(t *testing.T) {
 		t.Fatalf("Id returned is incorrect: truncate on %s returned %s", id, truncID)
 	}
 }
+
+func TestShellQuote(t *testing.T) {
+	cases := map[string]string{
+		"":          "''",
+		"/data":     "'/data'",
+		"/my data":  "'/my data'",
+		"$(reboot)": "'$(reboot)'",
+		"it's":      `'it'\''s'`,
+	}
+
+	for s, expected := range cases {
+		if quoted := ShellQuote(s); quoted != expected {
+			t.Errorf("Expected %s to be quoted as %s, got %s", s, expected, quoted)
+		}
+	}
+}
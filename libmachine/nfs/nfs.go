@@ -0,0 +1,187 @@
+// Package nfs exports directories of the host to the machines of local
+// hypervisors, as a faster alternative to the shared folders of the
+// hypervisor.
+//
+// The exports of each machine are kept in a block of /etc/exports delimited
+// by comments naming the machine, so that they're replaced or removed
+// without touching the others.
+//
+// /etc/exports is updated with sudo from the driver plugins, which have no
+// terminal to ask for a password. Setting up the host for NFS shares is
+// allowing the user to run "tee /etc/exports" and "exportfs -ra", or
+// "nfsd restart" on OS X, with sudo without a password.
+package nfs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnutils"
+)
+
+var (
+	ErrNotSupported = errors.New("Sharing folders over NFS is only supported on Linux and OS X hosts")
+
+	ErrPasswordRequired = errors.New("sudo asks for a password, which can't be given while sharing folders over NFS. Allow running tee /etc/exports and exportfs -ra (nfsd restart on OS X) with sudo without a password")
+
+	exportsFile = "/etc/exports"
+	goos        = runtime.GOOS
+	runSudo     = sudo
+)
+
+// CheckSupported returns ErrNotSupported if the host has no NFS server
+// known to work.
+func CheckSupported() error {
+	switch goos {
+	case "linux", "darwin":
+		return nil
+	}
+
+	return ErrNotSupported
+}
+
+// Export exports the folders to the network of the machine named id, in
+// place of the ones exported to it before. No folders removes its exports.
+func Export(id string, network *net.IPNet, folders []drivers.SharedFolder) error {
+	if err := CheckSupported(); err != nil {
+		return err
+	}
+
+	lines := []string{}
+	for _, folder := range folders {
+		lines = append(lines, exportLine(network, folder, os.Getuid(), os.Getgid()))
+	}
+
+	return updateExports(id, lines)
+}
+
+// Unexport removes the exports of the machine named id.
+func Unexport(id string) error {
+	if err := CheckSupported(); err != nil {
+		return err
+	}
+
+	return updateExports(id, nil)
+}
+
+// MountCommand returns the command that mounts the folder exported by the
+// host at serverIP in the machine, unless it's already mounted.
+func MountCommand(serverIP net.IP, folder drivers.SharedFolder) string {
+	options := "nolock,vers=3,tcp"
+	if folder.ReadOnly {
+		options += ",ro"
+	}
+
+	guestPath := mcnutils.ShellQuote(folder.GuestPath)
+
+	return fmt.Sprintf("sudo mkdir -p %s && (%s || sudo mount -t nfs -o %s %s %s)",
+		guestPath, drivers.MountedCommand(folder.GuestPath), options, mcnutils.ShellQuote(serverIP.String()+":"+folder.HostPath), guestPath)
+}
+
+// exportLine returns the line of /etc/exports that exports the folder to the
+// network, the files being accessed as uid:gid whoever the guest user is.
+func exportLine(network *net.IPNet, folder drivers.SharedFolder, uid, gid int) string {
+	if goos == "darwin" {
+		line := fmt.Sprintf("%q -alldirs -mapall=%d:%d -network %s -mask %s", folder.HostPath, uid, gid, network.IP, net.IP(network.Mask))
+		if folder.ReadOnly {
+			line += " -ro"
+		}
+		return line
+	}
+
+	mode := "rw"
+	if folder.ReadOnly {
+		mode = "ro"
+	}
+
+	return fmt.Sprintf("%q %s(%s,no_subtree_check,all_squash,anonuid=%d,anongid=%d)", folder.HostPath, network, mode, uid, gid)
+}
+
+func updateExports(id string, lines []string) error {
+	content, err := ioutil.ReadFile(exportsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	updated := replaceExports(string(content), id, lines)
+	if updated == string(content) {
+		return nil
+	}
+
+	log.Infof("Updating %s...", exportsFile)
+	if err := runSudo(updated, "tee", exportsFile); err != nil {
+		return fmt.Errorf("Unable to update %s: %s", exportsFile, err)
+	}
+
+	if goos == "darwin" {
+		return runSudo("", "nfsd", "restart")
+	}
+
+	return runSudo("", "exportfs", "-ra")
+}
+
+// replaceExports returns the content of /etc/exports with the block of the
+// machine named id replaced by the lines.
+func replaceExports(content, id string, lines []string) string {
+	begin := "# docker-machine-begin " + id
+	end := "# docker-machine-end " + id
+
+	kept := []string{}
+	found, inBlock := false, false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		switch strings.TrimSpace(line) {
+		case begin:
+			found, inBlock = true, true
+		case end:
+			inBlock = false
+		default:
+			if !inBlock && line != "" {
+				kept = append(kept, line)
+			}
+		}
+	}
+
+	if !found && len(lines) == 0 {
+		return content
+	}
+
+	updated := strings.Join(kept, "")
+	if updated != "" && !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+
+	if len(lines) == 0 {
+		return updated
+	}
+
+	return updated + begin + "\n" + strings.Join(lines, "\n") + "\n" + end + "\n"
+}
+
+// sudo runs the command as root, failing rather than asking for a password.
+func sudo(stdin string, args ...string) error {
+	cmd := exec.Command("sudo", append([]string{"-n"}, args...)...)
+	cmd.Stdin = strings.NewReader(stdin)
+
+	output, err := cmd.CombinedOutput()
+	return sudoError(err, string(output))
+}
+
+func sudoError(err error, output string) error {
+	if err == nil {
+		return nil
+	}
+
+	if strings.Contains(output, "password is required") {
+		return ErrPasswordRequired
+	}
+
+	return fmt.Errorf("%s: %s", err, strings.TrimSpace(output))
+}
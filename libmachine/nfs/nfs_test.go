@@ -0,0 +1,135 @@
+package nfs
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNetwork = &net.IPNet{IP: net.IPv4(192, 168, 99, 0).To4(), Mask: net.CIDRMask(24, 32)}
+
+// withExports runs the test against a temporary exports file, recording the
+// sudo commands instead of running them.
+func withExports(t *testing.T, hostOS string, content string, test func(commands *[]string)) {
+	dir, err := ioutil.TempDir("", "nfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "exports")
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(file, hostOS string, run func(string, ...string) error) {
+		exportsFile, goos, runSudo = file, hostOS, run
+	}(exportsFile, goos, runSudo)
+
+	commands := []string{}
+	exportsFile, goos = file, hostOS
+	runSudo = func(stdin string, args ...string) error {
+		commands = append(commands, strings.Join(args, " "))
+		if args[0] == "tee" {
+			return ioutil.WriteFile(args[1], []byte(stdin), 0644)
+		}
+		return nil
+	}
+
+	test(&commands)
+}
+
+func readExports(t *testing.T) string {
+	content, err := ioutil.ReadFile(exportsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestExportLinux(t *testing.T) {
+	withExports(t, "linux", "/srv 10.0.0.0/8(ro)\n", func(commands *[]string) {
+		err := Export("default", testNetwork, []drivers.SharedFolder{
+			{HostPath: "/home/user/src", GuestPath: "/src"},
+			{HostPath: "/data", GuestPath: "/data", ReadOnly: true},
+		})
+
+		uid, gid := os.Getuid(), os.Getgid()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/srv 10.0.0.0/8(ro)\n"+
+			"# docker-machine-begin default\n"+
+			exportLine(testNetwork, drivers.SharedFolder{HostPath: "/home/user/src"}, uid, gid)+"\n"+
+			exportLine(testNetwork, drivers.SharedFolder{HostPath: "/data", ReadOnly: true}, uid, gid)+"\n"+
+			"# docker-machine-end default\n", readExports(t))
+		assert.Equal(t, []string{"tee " + exportsFile, "exportfs -ra"}, *commands)
+	})
+}
+
+func TestExportUnchanged(t *testing.T) {
+	withExports(t, "linux", "", func(commands *[]string) {
+		folders := []drivers.SharedFolder{{HostPath: "/src", GuestPath: "/src"}}
+
+		assert.NoError(t, Export("default", testNetwork, folders))
+		assert.NoError(t, Export("default", testNetwork, folders))
+		assert.Len(t, *commands, 2)
+	})
+}
+
+func TestUnexportDarwin(t *testing.T) {
+	content := "# docker-machine-begin other\n\"/other\" -network 192.168.99.0 -mask 255.255.255.0\n# docker-machine-end other\n"
+
+	withExports(t, "darwin", content+"# docker-machine-begin default\n\"/src\" -alldirs\n# docker-machine-end default\n", func(commands *[]string) {
+		assert.NoError(t, Unexport("default"))
+		assert.Equal(t, content, readExports(t))
+		assert.Equal(t, []string{"tee " + exportsFile, "nfsd restart"}, *commands)
+	})
+}
+
+func TestUnexportNotExported(t *testing.T) {
+	withExports(t, "linux", "/srv 10.0.0.0/8(ro)", func(commands *[]string) {
+		assert.NoError(t, Unexport("default"))
+		assert.Empty(t, *commands)
+	})
+}
+
+func TestExportWindows(t *testing.T) {
+	withExports(t, "windows", "", func(commands *[]string) {
+		assert.Equal(t, ErrNotSupported, Export("default", testNetwork, nil))
+		assert.Equal(t, ErrNotSupported, Unexport("default"))
+	})
+}
+
+func TestExportLine(t *testing.T) {
+	defer func(hostOS string) { goos = hostOS }(goos)
+
+	goos = "linux"
+	assert.Equal(t, `"/src" 192.168.99.0/24(rw,no_subtree_check,all_squash,anonuid=501,anongid=20)`,
+		exportLine(testNetwork, drivers.SharedFolder{HostPath: "/src"}, 501, 20))
+
+	goos = "darwin"
+	assert.Equal(t, `"/Users/me/my src" -alldirs -mapall=501:20 -network 192.168.99.0 -mask 255.255.255.0 -ro`,
+		exportLine(testNetwork, drivers.SharedFolder{HostPath: "/Users/me/my src", ReadOnly: true}, 501, 20))
+}
+
+func TestMountCommand(t *testing.T) {
+	assert.Equal(t,
+		"sudo mkdir -p '/src' && (grep -qsF ' /src ' /proc/mounts || sudo mount -t nfs -o nolock,vers=3,tcp,ro '192.168.99.1:/Users/me/src' '/src')",
+		MountCommand(net.IPv4(192, 168, 99, 1), drivers.SharedFolder{HostPath: "/Users/me/src", GuestPath: "/src", ReadOnly: true}))
+	assert.Equal(t,
+		`sudo mkdir -p '/my src' && (grep -qsF ' /my\040src ' /proc/mounts || sudo mount -t nfs -o nolock,vers=3,tcp '192.168.99.1:/Users/me/it'\''s $(reboot)' '/my src')`,
+		MountCommand(net.IPv4(192, 168, 99, 1), drivers.SharedFolder{HostPath: "/Users/me/it's $(reboot)", GuestPath: "/my src"}))
+}
+
+func TestSudoError(t *testing.T) {
+	assert.NoError(t, sudoError(nil, ""))
+	assert.Equal(t, ErrPasswordRequired, sudoError(errors.New("exit status 1"), "sudo: a password is required\n"))
+	assert.EqualError(t, sudoError(errors.New("exit status 1"), "exportfs: bad export\n"), "exit status 1: exportfs: bad export")
+}
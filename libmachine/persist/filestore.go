@@ -9,13 +9,18 @@ import (
 	"strings"
 
 	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnerror"
+	"github.com/docker/machine/libmachine/secrets"
 )
 
 type Filestore struct {
 	Path             string
 	CaCertPath       string
 	CaPrivateKeyPath string
+	// Secrets seals the secrets of the drivers, they are stored in clear
+	// when it's nil.
+	Secrets secrets.Provider
 }
 
 func NewFilestore(path, caCertPath, caPrivateKeyPath string) *Filestore {
@@ -57,8 +62,30 @@ func (s Filestore) saveToFile(data []byte, file string) error {
 	return err
 }
 
+// marshalHost marshals the host, with the secrets of its driver sealed.
+func (s Filestore) marshalHost(h *host.Host) ([]byte, error) {
+	if s.Secrets == nil || h.HostOptions == nil || len(h.HostOptions.SecretFields) == 0 {
+		return json.MarshalIndent(h, "", "    ")
+	}
+
+	rawDriver, err := json.Marshal(h.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	sealedDriver, err := secrets.Seal(s.Secrets, h.Name, rawDriver, h.HostOptions.SecretFields)
+	if err != nil {
+		return nil, fmt.Errorf("Error sealing the secrets of the driver: %s", err)
+	}
+
+	return json.MarshalIndent(struct {
+		*host.Host
+		Driver json.RawMessage
+	}{h, sealedDriver}, "", "    ")
+}
+
 func (s Filestore) Save(host *host.Host) error {
-	data, err := json.MarshalIndent(host, "", "    ")
+	data, err := s.marshalHost(host)
 	if err != nil {
 		return err
 	}
@@ -75,9 +102,41 @@ func (s Filestore) Save(host *host.Host) error {
 
 func (s Filestore) Remove(name string) error {
 	hostPath := filepath.Join(s.GetMachinesDir(), name)
+
+	if err := s.removeSecrets(name); err != nil {
+		log.Warnf("Error removing the secrets of %s: %s", name, err)
+	}
+
 	return os.RemoveAll(hostPath)
 }
 
+// removeSecrets lets the secrets provider forget the secrets of the machine.
+func (s Filestore) removeSecrets(name string) error {
+	if s.Secrets == nil {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(s.GetMachinesDir(), name, "config.json"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	config := struct {
+		Driver      json.RawMessage
+		HostOptions struct {
+			SecretFields []string
+		}
+	}{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+
+	return secrets.Remove(s.Secrets, name, config.Driver, config.HostOptions.SecretFields)
+}
+
 func (s Filestore) List() ([]string, error) {
 	dir, err := ioutil.ReadDir(s.GetMachinesDir())
 	if err != nil && !os.IsNotExist(err) {
@@ -126,6 +185,10 @@ func (s Filestore) loadConfig(h *host.Host) error {
 
 	h.Name = name
 
+	if err := s.openSecrets(h); err != nil {
+		return fmt.Errorf("Error opening the secrets of the driver: %s", err)
+	}
+
 	// If we end up performing a migration, we should save afterwards so we don't have to do it again on subsequent invocations.
 	if migrationPerformed {
 		if err := s.saveToFile(data, filepath.Join(s.GetMachinesDir(), h.Name, "config.json.bak")); err != nil {
@@ -140,6 +203,26 @@ func (s Filestore) loadConfig(h *host.Host) error {
 	return nil
 }
 
+// openSecrets opens the sealed secrets of the driver, which then gets them
+// in clear when it's loaded.
+func (s Filestore) openSecrets(h *host.Host) error {
+	if h.HostOptions == nil || len(h.HostOptions.SecretFields) == 0 {
+		return nil
+	}
+
+	rawDriver, err := secrets.Open(s.Secrets, h.Name, h.RawDriver, h.HostOptions.SecretFields)
+	if err != nil {
+		return err
+	}
+
+	h.RawDriver = rawDriver
+	if driver, ok := h.Driver.(*host.RawDataDriver); ok {
+		driver.Data = rawDriver
+	}
+
+	return nil
+}
+
 func (s Filestore) Load(name string) (*host.Host, error) {
 	hostPath := filepath.Join(s.GetMachinesDir(), name)
 
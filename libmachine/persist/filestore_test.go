@@ -7,12 +7,14 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/docker/machine/commands/mcndirs"
 	"github.com/docker/machine/drivers/none"
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/hosttest"
+	"github.com/docker/machine/libmachine/secrets"
 )
 
 func cleanup() {
@@ -247,3 +249,78 @@ func TestStoreLoad(t *testing.T) {
 		t.Fatalf("GetURL is not %q, got %q", expectedURL, actualURL)
 	}
 }
+
+func TestStoreSaveLoadSecrets(t *testing.T) {
+	defer cleanup()
+
+	store := getTestStore()
+	store.Secrets = secrets.NewPassphraseProvider("passphrase")
+
+	h, err := hosttest.GetDefaultTestHost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.HostOptions.SecretFields = []string{"URL"}
+
+	if err := store.Save(h); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(store.GetMachinesDir(), h.Name, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "unix:///var/run/docker.sock") {
+		t.Fatalf("Expected the secret to be sealed, got %s", data)
+	}
+
+	h, err = store.Load(h.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawDataDriver, ok := h.Driver.(*host.RawDataDriver)
+	if !ok {
+		t.Fatal("Expected driver loaded from store to be of type *host.RawDataDriver and it was not")
+	}
+
+	if !strings.Contains(string(rawDataDriver.Data), `"URL":"unix:///var/run/docker.sock"`) {
+		t.Fatalf("Expected the secret to be opened, got %s", rawDataDriver.Data)
+	}
+
+	store.Secrets = nil
+	if _, err := store.Load(h.Name); err == nil {
+		t.Fatal("Expected an error loading sealed secrets without a provider")
+	}
+}
+
+func TestStoreRemoveSecrets(t *testing.T) {
+	defer cleanup()
+
+	store := getTestStore()
+	provider := secrets.NewFileProvider(filepath.Join(store.Path, "secrets.json"))
+	store.Secrets = provider
+
+	h, err := hosttest.GetDefaultTestHost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.HostOptions.SecretFields = []string{"URL"}
+
+	if err := store.Save(h); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.Open("", h.Name+"/URL"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Remove(h.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.Open("", h.Name+"/URL"); err == nil {
+		t.Fatal("Expected the secret to be removed with the host")
+	}
+}
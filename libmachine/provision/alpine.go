@@ -0,0 +1,200 @@
+package provision
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/provision/pkgaction"
+	"github.com/docker/machine/libmachine/provision/serviceaction"
+	"github.com/docker/machine/libmachine/swarm"
+)
+
+func init() {
+	Register("Alpine", &RegisteredProvisioner{
+		New:      NewAlpineProvisioner,
+		Priority: PriorityDistribution,
+	})
+}
+
+func NewAlpineProvisioner(d drivers.Driver) Provisioner {
+	return &AlpineProvisioner{
+		NewOpenRCProvisioner("alpine", d),
+	}
+}
+
+type AlpineProvisioner struct {
+	OpenRCProvisioner
+}
+
+func (provisioner *AlpineProvisioner) String() string {
+	return "alpine"
+}
+
+func (provisioner *AlpineProvisioner) Package(name string, action pkgaction.PackageAction) error {
+	var packageAction string
+
+	switch action {
+	case pkgaction.Install:
+		packageAction = "add"
+	case pkgaction.Remove, pkgaction.Purge:
+		packageAction = "del"
+	case pkgaction.Upgrade:
+		packageAction = "add --upgrade"
+	}
+
+	switch name {
+	case "docker-engine":
+		name = "docker"
+	}
+
+	if version := provisioner.EngineOptions.InstallVersion; name == "docker" && version != "" {
+		switch action {
+		case pkgaction.Install, pkgaction.Upgrade:
+			name = fmt.Sprintf("docker=%s", alpinePackageVersion(version))
+		}
+	}
+
+	command := fmt.Sprintf("sudo apk %s --no-cache %s", packageAction, name)
+	if packageAction == "del" {
+		command = fmt.Sprintf("sudo apk del %s", name)
+	}
+
+	log.Debugf("package: action=%s name=%s", action.String(), name)
+
+	if _, err := provisioner.SSHCommand(command); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// alpinePackageVersion returns version with the package release suffix apk
+// expects (e.g. "18.09.8-r0") if it has none.
+func alpinePackageVersion(version string) string {
+	if strings.Contains(version, "-") {
+		return version
+	}
+
+	return version + "-r0"
+}
+
+func (provisioner *AlpineProvisioner) GenerateDockerOptions(dockerPort int) (*DockerOptions, error) {
+	var (
+		engineCfg bytes.Buffer
+	)
+
+	driverNameLabel := fmt.Sprintf("provider=%s", provisioner.Driver.DriverName())
+	provisioner.EngineOptions.Labels = append(provisioner.EngineOptions.Labels, driverNameLabel)
+
+	// the OpenRC script of docker passes DOCKER_OPTS to dockerd and sources
+	// this file, so the environment is exported from it
+	engineConfigTmpl := `DOCKER_OPTS="{{ if not .DaemonJSON }}-H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}{{ end }}"
+{{range .EngineOptions.Env}}export {{ printf "%q" . }}
+{{end}}`
+
+	t, err := template.New("engineConfig").Parse(engineConfigTmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	engineConfigContext := EngineConfigContext{
+		DockerPort:    dockerPort,
+		AuthOptions:   provisioner.AuthOptions,
+		EngineOptions: provisioner.EngineOptions,
+	}
+
+	t.Execute(&engineCfg, engineConfigContext)
+
+	return withDaemonConfig(&DockerOptions{
+		EngineOptions:     engineCfg.String(),
+		EngineOptionsPath: provisioner.DaemonOptionsFile,
+	}, engineConfigContext, true)
+}
+
+func (provisioner *AlpineProvisioner) dockerDaemonResponding() bool {
+	log.Debug("checking docker daemon")
+
+	if out, err := provisioner.SSHCommand("sudo docker version"); err != nil {
+		log.Warnf("Error getting SSH command to check if the daemon is up: %s", err)
+		log.Debugf("'sudo docker version' output:\n%s", out)
+		return false
+	}
+
+	// The daemon is up if the command worked.  Carry on.
+	return true
+}
+
+func (provisioner *AlpineProvisioner) Provision(swarmOptions swarm.Options, authOptions auth.Options, engineOptions engine.Options) error {
+	provisioner.SwarmOptions = swarmOptions
+	provisioner.AuthOptions = authOptions
+	provisioner.EngineOptions = engineOptions
+	swarmOptions.Env = engineOptions.Env
+
+	storageDriver, err := decideStorageDriver(provisioner, "overlay2", engineOptions.StorageDriver)
+	if err != nil {
+		return err
+	}
+	provisioner.EngineOptions.StorageDriver = storageDriver
+
+	// HACK: like Arch, Alpine images don't always come with sudo
+	log.Debug("Installing sudo")
+	if _, err := provisioner.SSHCommand("if ! type sudo; then apk add --no-cache sudo; fi"); err != nil {
+		return err
+	}
+
+	log.Debug("Setting hostname")
+	if err := provisioner.SetHostname(provisioner.Driver.GetMachineName()); err != nil {
+		return err
+	}
+
+	log.Debug("Installing base packages")
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
+	}
+
+	log.Debug("Installing docker")
+	if engineOptions.InstallBundle != "" {
+		if err := installDockerFromBundle(provisioner, engineOptions); err != nil {
+			return err
+		}
+	} else if err := provisioner.Package("docker", pkgaction.Install); err != nil {
+		return err
+	}
+
+	log.Debug("Starting OpenRC docker service")
+	if err := provisioner.Service("docker", serviceaction.Start); err != nil {
+		return err
+	}
+
+	log.Debug("Waiting for docker daemon")
+	if err := mcnutils.WaitFor(provisioner.dockerDaemonResponding); err != nil {
+		return err
+	}
+
+	if err := makeDockerOptionsDir(provisioner); err != nil {
+		return err
+	}
+
+	provisioner.AuthOptions = setRemoteAuthOptions(provisioner)
+
+	log.Debug("Configuring auth")
+	if err := ConfigureAuth(provisioner); err != nil {
+		return err
+	}
+
+	log.Debug("Configuring swarm")
+	if err := configureSwarm(provisioner, swarmOptions, provisioner.AuthOptions); err != nil {
+		return err
+	}
+
+	log.Debug("Enabling docker in OpenRC")
+	err = provisioner.Service("docker", serviceaction.Enable)
+	return err
+}
@@ -0,0 +1,77 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/provision/pkgaction"
+	"github.com/docker/machine/libmachine/provision/serviceaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlpinePackage(t *testing.T) {
+	p := NewAlpineProvisioner(&fakedriver.Driver{}).(*AlpineProvisioner)
+	sshCmder := &recordingSSHCommander{}
+	p.SSHCommander = sshCmder
+
+	assert.NoError(t, p.Package("curl", pkgaction.Install))
+	assert.NoError(t, p.Package("docker-engine", pkgaction.Purge))
+	assert.NoError(t, p.Package("docker", pkgaction.Upgrade))
+
+	assert.Equal(t, []string{
+		"sudo apk add --no-cache curl",
+		"sudo apk del docker",
+		"sudo apk add --upgrade --no-cache docker",
+	}, sshCmder.commands)
+}
+
+func TestAlpinePackageInstallVersion(t *testing.T) {
+	p := NewAlpineProvisioner(&fakedriver.Driver{}).(*AlpineProvisioner)
+	sshCmder := &recordingSSHCommander{}
+	p.SSHCommander = sshCmder
+	p.EngineOptions = engine.Options{
+		InstallVersion: "18.09.8",
+	}
+
+	assert.NoError(t, p.Package("docker", pkgaction.Install))
+
+	assert.Equal(t, []string{"sudo apk add --no-cache docker=18.09.8-r0"}, sshCmder.commands)
+}
+
+func TestOpenRCService(t *testing.T) {
+	p := NewOpenRCProvisioner("alpine", &fakedriver.Driver{})
+	sshCmder := &recordingSSHCommander{}
+	p.SSHCommander = sshCmder
+
+	for _, action := range []serviceaction.ServiceAction{
+		serviceaction.DaemonReload,
+		serviceaction.Restart,
+		serviceaction.Enable,
+		serviceaction.Disable,
+	} {
+		assert.NoError(t, p.Service("docker", action))
+	}
+
+	assert.Equal(t, []string{
+		"sudo rc-service docker restart",
+		"sudo rc-update add docker default",
+		"sudo rc-update del docker default",
+	}, sshCmder.commands)
+}
+
+func TestAlpineGenerateDockerOptions(t *testing.T) {
+	p := NewAlpineProvisioner(&fakedriver.Driver{}).(*AlpineProvisioner)
+	p.EngineOptions = engine.Options{
+		StorageDriver: "overlay2",
+		Env:           []string{"HTTP_PROXY=http://proxy:3128"},
+	}
+
+	dockerOptions, err := p.GenerateDockerOptions(2376)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/etc/conf.d/docker", dockerOptions.EngineOptionsPath)
+	assert.Contains(t, dockerOptions.EngineOptions, `DOCKER_OPTS="-H tcp://0.0.0.0:2376 -H unix:///var/run/docker.sock --storage-driver overlay2 `)
+	assert.Contains(t, dockerOptions.EngineOptions, "--label provider=Driver")
+	assert.Contains(t, dockerOptions.EngineOptions, `export "HTTP_PROXY=http://proxy:3128"`)
+}
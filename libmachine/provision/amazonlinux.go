@@ -0,0 +1,148 @@
+package provision
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/provision/pkgaction"
+	"github.com/docker/machine/libmachine/provision/serviceaction"
+	"github.com/docker/machine/libmachine/swarm"
+)
+
+func init() {
+	Register("AmazonLinux", &RegisteredProvisioner{
+		New:      NewAmazonLinuxProvisioner,
+		Priority: PrioritySpecific,
+	})
+}
+
+func NewAmazonLinuxProvisioner(d drivers.Driver) Provisioner {
+	return &AmazonLinuxProvisioner{
+		NewRedHatProvisioner("amzn", d),
+	}
+}
+
+// AmazonLinuxProvisioner provisions Amazon Linux 2. The Docker CE
+// repositories don't support it, so the engine comes from the Amazon Linux
+// repositories instead.
+type AmazonLinuxProvisioner struct {
+	*RedHatProvisioner
+}
+
+func (provisioner *AmazonLinuxProvisioner) String() string {
+	return "amazonlinux"
+}
+
+func (provisioner *AmazonLinuxProvisioner) CompatibleWithHost() bool {
+	// the first Amazon Linux, with a VERSION_ID like 2018.03, uses the same
+	// ID but doesn't have systemd
+	return provisioner.OsReleaseInfo.ID == provisioner.OsReleaseID && amazonLinuxVersion(provisioner.OsReleaseInfo) == "2"
+}
+
+func (provisioner *AmazonLinuxProvisioner) Package(name string, action pkgaction.PackageAction) error {
+	switch name {
+	case "docker-engine":
+		name = "docker"
+	}
+
+	if name == "docker" && action == pkgaction.Install {
+		// use the docker topic of amazon-linux-extras when the image has it
+		// so that the engine stays on the supported release channel
+		if _, err := provisioner.SSHCommand("type amazon-linux-extras"); err == nil && provisioner.EngineOptions.InstallVersion == "" {
+			_, err := provisioner.SSHCommand("sudo amazon-linux-extras install -y docker")
+			return err
+		}
+	}
+
+	var packageAction string
+
+	switch action {
+	case pkgaction.Install:
+		packageAction = "install"
+	case pkgaction.Remove, pkgaction.Purge:
+		packageAction = "remove"
+	case pkgaction.Upgrade:
+		packageAction = "upgrade"
+	}
+
+	command := fmt.Sprintf("sudo -E yum %s -y %s", packageAction, name)
+
+	if version := provisioner.EngineOptions.InstallVersion; name == "docker" && version != "" {
+		switch action {
+		case pkgaction.Install, pkgaction.Upgrade:
+			pkg := mcnutils.ShellQuote("docker-" + version)
+			command = fmt.Sprintf("sudo -E yum downgrade -y %s || sudo -E yum install -y %s", pkg, pkg)
+		}
+	}
+
+	if _, err := provisioner.SSHCommand(command); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (provisioner *AmazonLinuxProvisioner) Provision(swarmOptions swarm.Options, authOptions auth.Options, engineOptions engine.Options) error {
+	provisioner.SwarmOptions = swarmOptions
+	provisioner.AuthOptions = authOptions
+	provisioner.EngineOptions = engineOptions
+	swarmOptions.Env = engineOptions.Env
+
+	storageDriver, err := decideStorageDriver(provisioner, "overlay2", engineOptions.StorageDriver)
+	if err != nil {
+		return err
+	}
+	provisioner.EngineOptions.StorageDriver = storageDriver
+
+	if err := provisioner.SetHostname(provisioner.Driver.GetMachineName()); err != nil {
+		return err
+	}
+
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
+	}
+
+	if engineOptions.InstallBundle != "" {
+		if err := installDockerFromBundle(provisioner, engineOptions); err != nil {
+			return err
+		}
+	} else if _, err := provisioner.SSHCommand("type docker"); err != nil || engineOptions.InstallVersion != "" {
+		if err := provisioner.Package("docker", pkgaction.Install); err != nil {
+			return err
+		}
+	}
+
+	if err := provisioner.Service("docker", serviceaction.Restart); err != nil {
+		return err
+	}
+
+	if err := provisioner.Service("docker", serviceaction.Enable); err != nil {
+		return err
+	}
+
+	if err := mcnutils.WaitFor(provisioner.dockerDaemonResponding); err != nil {
+		return err
+	}
+
+	if err := makeDockerOptionsDir(provisioner); err != nil {
+		return err
+	}
+
+	provisioner.AuthOptions = setRemoteAuthOptions(provisioner)
+
+	if err := ConfigureAuth(provisioner); err != nil {
+		return err
+	}
+
+	return configureSwarm(provisioner, swarmOptions, provisioner.AuthOptions)
+}
+
+// amazonLinuxVersion returns the major version of Amazon Linux from the
+// VERSION_ID of its os-release.
+func amazonLinuxVersion(osReleaseInfo *OsRelease) string {
+	return strings.SplitN(osReleaseInfo.VersionID, ".", 2)[0]
+}
@@ -0,0 +1,52 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/provision/pkgaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmazonLinuxCompatibleWithHost(t *testing.T) {
+	p := NewAmazonLinuxProvisioner(&fakedriver.Driver{})
+
+	p.SetOsReleaseInfo(&OsRelease{ID: "amzn", VersionID: "2"})
+	assert.True(t, p.CompatibleWithHost())
+
+	p.SetOsReleaseInfo(&OsRelease{ID: "amzn", VersionID: "2018.03"})
+	assert.False(t, p.CompatibleWithHost())
+
+	p.SetOsReleaseInfo(&OsRelease{ID: "centos", VersionID: "7"})
+	assert.False(t, p.CompatibleWithHost())
+}
+
+func TestAmazonLinuxPackageDocker(t *testing.T) {
+	p := NewAmazonLinuxProvisioner(&fakedriver.Driver{}).(*AmazonLinuxProvisioner)
+	sshCmder := &recordingSSHCommander{}
+	p.SSHCommander = sshCmder
+
+	assert.NoError(t, p.Package("docker", pkgaction.Install))
+
+	assert.Equal(t, []string{
+		"type amazon-linux-extras",
+		"sudo amazon-linux-extras install -y docker",
+	}, sshCmder.commands)
+}
+
+func TestAmazonLinuxPackageDockerVersion(t *testing.T) {
+	p := NewAmazonLinuxProvisioner(&fakedriver.Driver{}).(*AmazonLinuxProvisioner)
+	sshCmder := &recordingSSHCommander{}
+	p.SSHCommander = sshCmder
+	p.EngineOptions = engine.Options{
+		InstallVersion: "18.06.1ce",
+	}
+
+	assert.NoError(t, p.Package("docker-engine", pkgaction.Install))
+
+	assert.Equal(t, []string{
+		"type amazon-linux-extras",
+		"sudo -E yum downgrade -y 'docker-18.06.1ce' || sudo -E yum install -y 'docker-18.06.1ce'",
+	}, sshCmder.commands)
+}
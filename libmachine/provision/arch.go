@@ -2,6 +2,7 @@ package provision
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/docker/machine/libmachine/auth"
 	"github.com/docker/machine/libmachine/drivers"
@@ -13,9 +14,14 @@ import (
 	"github.com/docker/machine/libmachine/swarm"
 )
 
+const (
+	archDockerArchiveURL = "https://archive.archlinux.org/packages/d/docker"
+)
+
 func init() {
 	Register("Arch", &RegisteredProvisioner{
-		New: NewArchProvisioner,
+		New:      NewArchProvisioner,
+		Priority: PriorityDistribution,
 	})
 }
 
@@ -66,6 +72,12 @@ func (provisioner *ArchProvisioner) Package(name string, action pkgaction.Packag
 
 	command := fmt.Sprintf("sudo -E pacman %s %s", pacmanOpts, name)
 
+	if version := provisioner.EngineOptions.InstallVersion; name == "docker" && version != "" && packageAction == "S" {
+		// The Arch repositories only carry the latest release, so pinned
+		// versions come from the Arch Linux Archive.
+		command = fmt.Sprintf("sudo -E pacman -U --noconfirm --noprogressbar %s", mcnutils.ShellQuote(archDockerPackageURL(version)))
+	}
+
 	log.Debugf("package: action=%s name=%s", action.String(), name)
 
 	if _, err := provisioner.SSHCommand(command); err != nil {
@@ -75,6 +87,16 @@ func (provisioner *ArchProvisioner) Package(name string, action pkgaction.Packag
 	return nil
 }
 
+// archDockerPackageURL returns the Arch Linux Archive URL of the docker
+// package for version, which may carry a pkgrel suffix (e.g. "18.09.1-1").
+func archDockerPackageURL(version string) string {
+	if !strings.Contains(version, "-") {
+		version = version + "-1"
+	}
+
+	return fmt.Sprintf("%s/docker-1:%s-x86_64.pkg.tar.xz", archDockerArchiveURL, version)
+}
+
 func (provisioner *ArchProvisioner) dockerDaemonResponding() bool {
 	log.Debug("checking docker daemon")
 
@@ -112,14 +134,16 @@ func (provisioner *ArchProvisioner) Provision(swarmOptions swarm.Options, authOp
 	}
 
 	log.Debug("Installing base packages")
-	for _, pkg := range provisioner.Packages {
-		if err := provisioner.Package(pkg, pkgaction.Install); err != nil {
-			return err
-		}
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
 	}
 
 	log.Debug("Installing docker")
-	if err := provisioner.Package("docker", pkgaction.Install); err != nil {
+	if engineOptions.InstallBundle != "" {
+		if err := installDockerFromBundle(provisioner, engineOptions); err != nil {
+			return err
+		}
+	} else if err := provisioner.Package("docker", pkgaction.Install); err != nil {
 		return err
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/machine/libmachine/engine"
 	"github.com/docker/machine/libmachine/provision/provisiontest"
 	"github.com/docker/machine/libmachine/swarm"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestArchDefaultStorageDriver(t *testing.T) {
@@ -18,3 +19,8 @@ func TestArchDefaultStorageDriver(t *testing.T) {
 		t.Fatal("Default storage driver should be overlay")
 	}
 }
+
+func TestArchDockerPackageURL(t *testing.T) {
+	assert.Equal(t, "https://archive.archlinux.org/packages/d/docker/docker-1:18.09.1-1-x86_64.pkg.tar.xz", archDockerPackageURL("18.09.1"))
+	assert.Equal(t, "https://archive.archlinux.org/packages/d/docker/docker-1:18.09.1-2-x86_64.pkg.tar.xz", archDockerPackageURL("18.09.1-2"))
+}
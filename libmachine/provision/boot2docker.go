@@ -21,9 +21,14 @@ import (
 	"github.com/docker/machine/libmachine/swarm"
 )
 
+const (
+	boot2dockerDaemonConfigPath = "/var/lib/boot2docker/daemon.json"
+)
+
 func init() {
 	Register("boot2docker", &RegisteredProvisioner{
-		New: NewBoot2DockerProvisioner,
+		New:      NewBoot2DockerProvisioner,
+		Priority: PrioritySpecific,
 	})
 }
 
@@ -66,6 +71,13 @@ func (provisioner *Boot2DockerProvisioner) upgradeIso() error {
 	}
 	json.Unmarshal(jsonDriver, &d)
 
+	// The ISO of a pinned engine version is got from the configured
+	// Boot2Docker release source, like the latest one
+	isoURL := d.Boot2DockerURL
+	if version := provisioner.EngineOptions.InstallVersion; version != "" {
+		isoURL = mcnutils.Boot2DockerReleaseURL(version)
+	}
+
 	log.Info("Stopping machine to do the upgrade...")
 
 	if err := provisioner.Driver.Stop(); err != nil {
@@ -80,9 +92,10 @@ func (provisioner *Boot2DockerProvisioner) upgradeIso() error {
 
 	log.Infof("Upgrading machine %q...", machineName)
 
-	// Either download the latest version of the b2d url that was explicitly
-	// specified when creating the VM or copy the (updated) default ISO
-	if err := b2dutils.CopyIsoToMachineDir(d.Boot2DockerURL, machineName); err != nil {
+	// Either download the ISO for the pinned engine version, the latest
+	// version of the b2d url that was explicitly specified when creating the
+	// VM or copy the (updated) default ISO
+	if err := b2dutils.CopyIsoToMachineDir(isoURL, machineName); err != nil {
 		return err
 	}
 
@@ -132,6 +145,18 @@ func (provisioner *Boot2DockerProvisioner) GetSwarmOptions() swarm.Options {
 	return provisioner.SwarmOptions
 }
 
+func (provisioner *Boot2DockerProvisioner) GetEngineOptions() engine.Options {
+	return provisioner.EngineOptions
+}
+
+func (provisioner *Boot2DockerProvisioner) SetEngineOptions(engineOptions engine.Options) {
+	provisioner.EngineOptions = engineOptions
+}
+
+func (provisioner *Boot2DockerProvisioner) SetAuthOptions(authOptions auth.Options) {
+	provisioner.AuthOptions = authOptions
+}
+
 func (provisioner *Boot2DockerProvisioner) GenerateDockerOptions(dockerPort int) (*DockerOptions, error) {
 	var (
 		engineCfg bytes.Buffer
@@ -142,11 +167,12 @@ func (provisioner *Boot2DockerProvisioner) GenerateDockerOptions(dockerPort int)
 
 	engineConfigTmpl := `
 EXTRA_ARGS='
-{{ range .EngineOptions.Labels }}--label {{.}}
+{{ if .DaemonJSON }}--config-file ` + boot2dockerDaemonConfigPath + `
+{{ else }}{{ range .EngineOptions.Labels }}--label {{.}}
 {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}}
 {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}}
 {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}}
-{{ end }}
+{{ end }}{{ end }}
 '
 CACERT={{.AuthOptions.CaCertRemotePath}}
 DOCKER_HOST='-H tcp://0.0.0.0:{{.DockerPort}}'
@@ -172,10 +198,20 @@ SERVERCERT={{.AuthOptions.ServerCertRemotePath}}
 	t.Execute(&engineCfg, engineConfigContext)
 
 	daemonOptsDir := path.Join(provisioner.GetDockerOptionsDir(), "profile")
-	return &DockerOptions{
+	dockerOptions, err := withDaemonConfig(&DockerOptions{
 		EngineOptions:     engineCfg.String(),
 		EngineOptionsPath: daemonOptsDir,
-	}, nil
+	}, engineConfigContext, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// /etc doesn't survive a reboot on boot2docker
+	if dockerOptions.DaemonConfig != nil {
+		dockerOptions.DaemonConfigPath = boot2dockerDaemonConfigPath
+	}
+
+	return dockerOptions, nil
 }
 
 func (provisioner *Boot2DockerProvisioner) CompatibleWithHost() bool {
@@ -0,0 +1,295 @@
+package provision
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnutils"
+)
+
+const (
+	bundleRemoteDir = "/tmp/docker-machine-bundle"
+
+	dockerSystemdUnit = `[Unit]
+Description=Docker Application Container Engine
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=notify
+ExecStart=/usr/bin/dockerd -H unix:///var/run/docker.sock
+ExecReload=/bin/kill -s HUP $MAINPID
+LimitNOFILE=infinity
+LimitNPROC=infinity
+LimitCORE=infinity
+TimeoutStartSec=0
+Delegate=yes
+KillMode=process
+
+[Install]
+WantedBy=multi-user.target
+`
+
+	dockerUpstartJob = `description "Docker daemon"
+
+start on (filesystem and net-device-up IFACE!=lo)
+stop on runlevel [!2345]
+
+respawn
+
+script
+	DOCKER_OPTS=
+	if [ -f /etc/default/docker ]; then
+		. /etc/default/docker
+	fi
+	exec /usr/bin/dockerd $DOCKER_OPTS
+end script
+`
+)
+
+var (
+	ErrUnknownBundle = errors.New("engine bundle is neither a static binaries tarball nor a .deb, .rpm or pacman package")
+
+	runSSHCommandWithStdin    = drivers.RunSSHCommandWithStdinFromDriver
+	streamSSHCommandWithStdin = drivers.StreamSSHCommandWithStdinFromDriver
+)
+
+type bundleKind int
+
+const (
+	bundleStatic bundleKind = iota
+	bundleDeb
+	bundleRPM
+	bundlePacman
+)
+
+// ErrBundleChecksum is returned when an engine bundle doesn't match its
+// expected SHA-256 digest.
+type ErrBundleChecksum struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e ErrBundleChecksum) Error() string {
+	return fmt.Sprintf("Checksum mismatch for engine bundle %s: expected sha256 %s, got %s", e.Path, e.Expected, e.Actual)
+}
+
+// packageKind returns the kind of a single distribution package file.
+func packageKind(name string) (bundleKind, bool) {
+	switch {
+	case strings.HasSuffix(name, ".deb"):
+		return bundleDeb, true
+	case strings.HasSuffix(name, ".rpm"):
+		return bundleRPM, true
+	case strings.Contains(name, ".pkg.tar"):
+		return bundlePacman, true
+	}
+
+	return 0, false
+}
+
+// detectBundleKind inspects the bundle at bundlePath. It is either a single
+// distribution package, a tarball of distribution packages, or the static
+// binaries tarball from download.docker.com.
+func detectBundleKind(bundlePath string) (bundleKind, error) {
+	if kind, ok := packageKind(bundlePath); ok {
+		return kind, nil
+	}
+
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if isGzipped(bundlePath) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if path.Base(hdr.Name) == "dockerd" {
+			return bundleStatic, nil
+		}
+
+		if kind, ok := packageKind(hdr.Name); ok {
+			return kind, nil
+		}
+	}
+
+	return 0, ErrUnknownBundle
+}
+
+// isGzipped tells whether the tarball at name is compressed with gzip.
+func isGzipped(name string) bool {
+	return strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz")
+}
+
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// uploadFile copies the local file src to dest on the remote host, streaming
+// it to a single SSH command.
+func uploadFile(p Provisioner, src, dest string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = runSSHCommandWithStdin(p.GetDriver(), fmt.Sprintf("mkdir -p %s && cat > %s", mcnutils.ShellQuote(path.Dir(dest)), mcnutils.ShellQuote(dest)), f)
+	return err
+}
+
+// installDockerFromBundle uploads the engine bundle configured in
+// engineOptions to the remote host and installs Docker from it, without
+// requiring any network access from the host.
+func installDockerFromBundle(p Provisioner, engineOptions engine.Options) error {
+	if _, err := p.SSHCommand("type docker"); err == nil {
+		log.Debug("docker is already installed, skipping bundle installation")
+		return nil
+	}
+
+	bundle := engineOptions.InstallBundle
+
+	checksum, err := fileSHA256(bundle)
+	if err != nil {
+		return fmt.Errorf("Error reading engine bundle: %s", err)
+	}
+
+	if expected := strings.ToLower(engineOptions.InstallBundleSHA256); expected != "" && expected != checksum {
+		return ErrBundleChecksum{
+			Path:     bundle,
+			Expected: expected,
+			Actual:   checksum,
+		}
+	}
+
+	kind, err := detectBundleKind(bundle)
+	if err != nil {
+		return err
+	}
+
+	remoteBundle := path.Join(bundleRemoteDir, filepath.Base(bundle))
+
+	log.Infof("Uploading engine bundle %s...", bundle)
+	if err := uploadFile(p, bundle, remoteBundle); err != nil {
+		return fmt.Errorf("Error uploading engine bundle: %s", err)
+	}
+
+	if _, err := p.SSHCommand(fmt.Sprintf("echo %s | sha256sum -c -", mcnutils.ShellQuote(checksum+"  "+remoteBundle))); err != nil {
+		return fmt.Errorf("Engine bundle checksum verification failed on the remote host: %s", err)
+	}
+
+	log.Info("Installing Docker from the engine bundle...")
+	if _, err := p.SSHCommand(bundleInstallCommand(kind, remoteBundle)); err != nil {
+		return fmt.Errorf("error installing docker from bundle: %s", err)
+	}
+
+	if kind == bundleStatic {
+		if err := registerDockerService(p); err != nil {
+			return err
+		}
+	}
+
+	_, err = p.SSHCommand(fmt.Sprintf("rm -rf %s", bundleRemoteDir))
+	return err
+}
+
+func bundleInstallCommand(kind bundleKind, remoteBundle string) string {
+	extract := "tar -xf"
+	if isGzipped(remoteBundle) {
+		extract = "tar -xzf"
+	}
+
+	if kind == bundleStatic {
+		return fmt.Sprintf("sudo %s %s --strip-components=1 -C /usr/bin && sudo groupadd -f docker", extract, mcnutils.ShellQuote(remoteBundle))
+	}
+
+	if _, ok := packageKind(remoteBundle); ok {
+		return packageInstallCommand(kind, mcnutils.ShellQuote(remoteBundle))
+	}
+
+	// a tarball of packages, extract it next to the bundle first
+	packages := fmt.Sprintf("$(find . -name '*%s')", packageGlob(kind))
+	return fmt.Sprintf("cd %s && %s %s && %s", bundleRemoteDir, extract, mcnutils.ShellQuote(remoteBundle), packageInstallCommand(kind, packages))
+}
+
+func packageGlob(kind bundleKind) string {
+	switch kind {
+	case bundleDeb:
+		return ".deb"
+	case bundleRPM:
+		return ".rpm"
+	default:
+		return ".pkg.tar.*"
+	}
+}
+
+func packageInstallCommand(kind bundleKind, packages string) string {
+	switch kind {
+	case bundleDeb:
+		return fmt.Sprintf("sudo DEBIAN_FRONTEND=noninteractive dpkg -i %s", packages)
+	case bundleRPM:
+		return fmt.Sprintf("sudo rpm -Uvh --replacepkgs %s", packages)
+	default:
+		return fmt.Sprintf("sudo pacman -U --noconfirm --noprogressbar %s", packages)
+	}
+}
+
+// registerDockerService installs the init configuration for a Docker engine
+// installed from the static binaries, for systemd or upstart hosts.
+func registerDockerService(p Provisioner) error {
+	if _, err := p.SSHCommand("test -d /run/systemd/system"); err == nil {
+		if _, err := p.SSHCommand(fmt.Sprintf("printf '%%s' '%s' | sudo tee /etc/systemd/system/docker.service", dockerSystemdUnit)); err != nil {
+			return err
+		}
+		_, err := p.SSHCommand("sudo systemctl daemon-reload && sudo systemctl enable docker && sudo systemctl start docker")
+		return err
+	}
+
+	if _, err := p.SSHCommand(fmt.Sprintf("printf '%%s' '%s' | sudo tee /etc/init/docker.conf", dockerUpstartJob)); err != nil {
+		return err
+	}
+	_, err := p.SSHCommand("sudo service docker start")
+	return err
+}
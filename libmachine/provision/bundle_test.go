@@ -0,0 +1,133 @@
+package provision
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/provision/provisiontest"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSSHCommander struct {
+	commands  []string
+	responses map[string]string
+}
+
+func (sshCmder *recordingSSHCommander) SSHCommand(args string) (string, error) {
+	sshCmder.commands = append(sshCmder.commands, args)
+	return sshCmder.responses[args], nil
+}
+
+func writeTarball(t *testing.T, name string, files ...string) string {
+	dir, err := ioutil.TempDir("", "machine-bundle-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := filepath.Join(dir, name)
+	f, err := os.Create(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: file, Mode: 0755, Size: 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+
+	return bundle
+}
+
+func TestDetectBundleKind(t *testing.T) {
+	static := writeTarball(t, "docker-18.09.1.tgz", "docker/docker", "docker/dockerd", "docker/containerd")
+	defer os.RemoveAll(filepath.Dir(static))
+
+	debs := writeTarball(t, "docker-debs.tar.gz", "docker-ce_18.09.1.deb", "containerd.io_1.2.2.deb")
+	defer os.RemoveAll(filepath.Dir(debs))
+
+	empty := writeTarball(t, "empty.tgz", "README")
+	defer os.RemoveAll(filepath.Dir(empty))
+
+	kind, err := detectBundleKind(static)
+	assert.NoError(t, err)
+	assert.Equal(t, bundleStatic, kind)
+
+	kind, err = detectBundleKind(debs)
+	assert.NoError(t, err)
+	assert.Equal(t, bundleDeb, kind)
+
+	kind, err = detectBundleKind("/some/docker-ce-18.09.1.el7.x86_64.rpm")
+	assert.NoError(t, err)
+	assert.Equal(t, bundleRPM, kind)
+
+	_, err = detectBundleKind(empty)
+	assert.Equal(t, ErrUnknownBundle, err)
+}
+
+func TestUploadFile(t *testing.T) {
+	src, err := ioutil.TempFile("", "machine-upload-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(src.Name())
+
+	content := strings.Repeat("a", 1024*1024)
+	src.WriteString(content)
+	src.Close()
+
+	commands := []string{}
+	uploaded := ""
+	defer func(run func(drivers.Driver, string, io.Reader) (string, error)) { runSSHCommandWithStdin = run }(runSSHCommandWithStdin)
+	runSSHCommandWithStdin = func(d drivers.Driver, command string, stdin io.Reader) (string, error) {
+		commands = append(commands, command)
+		data, err := ioutil.ReadAll(stdin)
+		uploaded += string(data)
+		return "", err
+	}
+
+	p := NewDebianProvisioner(&fakedriver.Driver{})
+	err = uploadFile(p, src.Name(), "/tmp/bundle/file")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mkdir -p '/tmp/bundle' && cat > '/tmp/bundle/file'"}, commands)
+	assert.Equal(t, content, uploaded)
+}
+
+func TestInstallDockerFromBundleChecksumMismatch(t *testing.T) {
+	bundle := writeTarball(t, "docker-18.09.1.tgz", "docker/dockerd")
+	defer os.RemoveAll(filepath.Dir(bundle))
+
+	p := NewDebianProvisioner(&fakedriver.Driver{}).(*DebianProvisioner)
+	p.SSHCommander = &provisiontest.FakeSSHCommander{}
+
+	err := installDockerFromBundle(p, engine.Options{
+		InstallBundle:       bundle,
+		InstallBundleSHA256: "deadbeef",
+	})
+
+	assert.IsType(t, ErrBundleChecksum{}, err)
+}
+
+func TestBundleInstallCommand(t *testing.T) {
+	assert.Equal(t, "sudo tar -xzf '/tmp/docker-machine-bundle/docker.tgz' --strip-components=1 -C /usr/bin && sudo groupadd -f docker", bundleInstallCommand(bundleStatic, "/tmp/docker-machine-bundle/docker.tgz"))
+	assert.Equal(t, "sudo tar -xf '/tmp/docker-machine-bundle/docker.tar' --strip-components=1 -C /usr/bin && sudo groupadd -f docker", bundleInstallCommand(bundleStatic, "/tmp/docker-machine-bundle/docker.tar"))
+	assert.Equal(t, "sudo rpm -Uvh --replacepkgs '/tmp/docker-machine-bundle/docker-ce.rpm'", bundleInstallCommand(bundleRPM, "/tmp/docker-machine-bundle/docker-ce.rpm"))
+	assert.Equal(t, "sudo DEBIAN_FRONTEND=noninteractive dpkg -i '/tmp/docker-machine-bundle/my docker.deb'", bundleInstallCommand(bundleDeb, "/tmp/docker-machine-bundle/my docker.deb"))
+	assert.Equal(t, "cd /tmp/docker-machine-bundle && tar -xzf '/tmp/docker-machine-bundle/debs.tgz' && sudo DEBIAN_FRONTEND=noninteractive dpkg -i $(find . -name '*.deb')", bundleInstallCommand(bundleDeb, "/tmp/docker-machine-bundle/debs.tgz"))
+	assert.Equal(t, "cd /tmp/docker-machine-bundle && tar -xf '/tmp/docker-machine-bundle/rpms.tar' && sudo rpm -Uvh --replacepkgs $(find . -name '*.rpm')", bundleInstallCommand(bundleRPM, "/tmp/docker-machine-bundle/rpms.tar"))
+}
@@ -6,7 +6,8 @@ import (
 
 func init() {
 	Register("Centos", &RegisteredProvisioner{
-		New: NewCentosProvisioner,
+		New:      NewCentosProvisioner,
+		Priority: PriorityDistribution,
 	})
 }
 
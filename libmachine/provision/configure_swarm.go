@@ -14,34 +14,63 @@ import (
 	"github.com/samalba/dockerclient"
 )
 
+// swarmContainer is a swarm container to be created on the host.
+type swarmContainer struct {
+	Name   string
+	Config *dockerclient.ContainerConfig
+}
+
 func configureSwarm(p Provisioner, swarmOptions swarm.Options, authOptions auth.Options) error {
+	if err := configureSwarmMode(p, swarmOptions); err != nil {
+		return err
+	}
+
 	if !swarmOptions.IsSwarm {
 		return nil
 	}
 
 	log.Info("Configuring swarm...")
 
-	ip, err := p.GetDriver().GetIP()
+	dockerHost, containers, err := swarmContainers(p, swarmOptions, authOptions)
 	if err != nil {
 		return err
 	}
 
+	for _, container := range containers {
+		if err := mcndockerclient.CreateContainer(dockerHost, container.Config, container.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// swarmContainers returns the swarm master and agent containers described by
+// swarmOptions, along with the engine they are to be created on.
+func swarmContainers(p Provisioner, swarmOptions swarm.Options, authOptions auth.Options) (*mcndockerclient.RemoteDocker, []swarmContainer, error) {
+	containers := []swarmContainer{}
+
+	ip, err := p.GetDriver().GetIP()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	u, err := url.Parse(swarmOptions.Host)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	enginePort := engine.DefaultPort
-	engineURL, err := p.GetDriver().GetURL()
+	dockerURL, err := engineURL(p)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	parts := strings.Split(engineURL, ":")
+	parts := strings.Split(dockerURL, ":")
 	if len(parts) == 3 {
 		dPort, err := strconv.Atoi(parts[2])
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		enginePort = dPort
 	}
@@ -106,10 +135,10 @@ func configureSwarm(p Provisioner, swarmOptions swarm.Options, authOptions auth.
 			HostConfig: masterHostConfig,
 		}
 
-		err = mcndockerclient.CreateContainer(dockerHost, swarmMasterConfig, "swarm-agent-master")
-		if err != nil {
-			return err
-		}
+		containers = append(containers, swarmContainer{
+			Name:   "swarm-agent-master",
+			Config: swarmMasterConfig,
+		})
 	}
 
 	if swarmOptions.Agent {
@@ -140,10 +169,11 @@ func configureSwarm(p Provisioner, swarmOptions swarm.Options, authOptions auth.
 			swarmWorkerConfig.Cmd = append([]string{"--experimental"}, swarmWorkerConfig.Cmd...)
 		}
 
-		err = mcndockerclient.CreateContainer(dockerHost, swarmWorkerConfig, "swarm-agent")
-		if err != nil {
-			return err
-		}
+		containers = append(containers, swarmContainer{
+			Name:   "swarm-agent",
+			Config: swarmWorkerConfig,
+		})
 	}
-	return nil
+
+	return dockerHost, containers, nil
 }
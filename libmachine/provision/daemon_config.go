@@ -0,0 +1,383 @@
+package provision
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	DefaultDaemonConfigPath = "/etc/docker/daemon.json"
+)
+
+var (
+	// daemonConfigKeys are the keys accepted by dockerd in daemon.json.
+	daemonConfigKeys = map[string]bool{
+		"allow-nondistributable-artifacts": true,
+		"api-cors-header":                  true,
+		"authorization-plugins":            true,
+		"bip":                              true,
+		"bridge":                           true,
+		"cgroup-parent":                    true,
+		"cluster-advertise":                true,
+		"cluster-store":                    true,
+		"cluster-store-opts":               true,
+		"containerd":                       true,
+		"data-root":                        true,
+		"debug":                            true,
+		"default-address-pools":            true,
+		"default-gateway":                  true,
+		"default-gateway-v6":               true,
+		"default-runtime":                  true,
+		"default-shm-size":                 true,
+		"default-ulimits":                  true,
+		"dns":                              true,
+		"dns-opts":                         true,
+		"dns-search":                       true,
+		"exec-opts":                        true,
+		"exec-root":                        true,
+		"experimental":                     true,
+		"features":                         true,
+		"fixed-cidr":                       true,
+		"fixed-cidr-v6":                    true,
+		"graph":                            true,
+		"group":                            true,
+		"hosts":                            true,
+		"icc":                              true,
+		"init":                             true,
+		"init-path":                        true,
+		"insecure-registries":              true,
+		"ip":                               true,
+		"ip-forward":                       true,
+		"ip-masq":                          true,
+		"ipv6":                             true,
+		"iptables":                         true,
+		"labels":                           true,
+		"live-restore":                     true,
+		"log-driver":                       true,
+		"log-level":                        true,
+		"log-opts":                         true,
+		"max-concurrent-downloads":         true,
+		"max-concurrent-uploads":           true,
+		"max-download-attempts":            true,
+		"metrics-addr":                     true,
+		"mtu":                              true,
+		"no-new-privileges":                true,
+		"node-generic-resources":           true,
+		"oom-score-adjust":                 true,
+		"pidfile":                          true,
+		"raw-logs":                         true,
+		"registry-mirrors":                 true,
+		"runtimes":                         true,
+		"seccomp-profile":                  true,
+		"selinux-enabled":                  true,
+		"shutdown-timeout":                 true,
+		"storage-driver":                   true,
+		"storage-opts":                     true,
+		"tls":                              true,
+		"tlscacert":                        true,
+		"tlscert":                          true,
+		"tlskey":                           true,
+		"tlsverify":                        true,
+		"userland-proxy":                   true,
+		"userland-proxy-path":              true,
+		"userns-remap":                     true,
+	}
+
+	// daemonConfigBoolKeys are the daemon.json keys with a boolean value.
+	daemonConfigBoolKeys = map[string]bool{
+		"debug":             true,
+		"experimental":      true,
+		"icc":               true,
+		"init":              true,
+		"ip-forward":        true,
+		"ip-masq":           true,
+		"ipv6":              true,
+		"iptables":          true,
+		"live-restore":      true,
+		"no-new-privileges": true,
+		"raw-logs":          true,
+		"selinux-enabled":   true,
+		"tls":               true,
+		"tlsverify":         true,
+		"userland-proxy":    true,
+	}
+
+	// daemonConfigIntKeys are the daemon.json keys with an integer value.
+	daemonConfigIntKeys = map[string]bool{
+		"max-concurrent-downloads": true,
+		"max-concurrent-uploads":   true,
+		"max-download-attempts":    true,
+		"mtu":                      true,
+		"oom-score-adjust":         true,
+		"shutdown-timeout":         true,
+	}
+
+	// daemonConfigListKeys maps repeatable daemon flags to their list
+	// valued daemon.json key.
+	daemonConfigListKeys = map[string]string{
+		"allow-nondistributable-artifacts": "allow-nondistributable-artifacts",
+		"authorization-plugin":             "authorization-plugins",
+		"dns":                              "dns",
+		"dns-opt":                          "dns-opts",
+		"dns-search":                       "dns-search",
+		"exec-opt":                         "exec-opts",
+		"host":                             "hosts",
+		"insecure-registry":                "insecure-registries",
+		"label":                            "labels",
+		"registry-mirror":                  "registry-mirrors",
+		"storage-opt":                      "storage-opts",
+	}
+
+	// daemonConfigMapKeys maps repeatable key=value daemon flags to their
+	// object valued daemon.json key.
+	daemonConfigMapKeys = map[string]string{
+		"cluster-store-opt": "cluster-store-opts",
+		"log-opt":           "log-opts",
+	}
+)
+
+// ErrUnknownDaemonConfigKeys is returned when a daemon.json would contain
+// keys dockerd doesn't know about, which would prevent it from starting. Path
+// is set when the keys come from the daemon.json already on the host.
+type ErrUnknownDaemonConfigKeys struct {
+	Keys []string
+	Path string
+}
+
+func (e ErrUnknownDaemonConfigKeys) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("Unknown daemon configuration keys in %s on the host: %s, remove them for dockerd to start", e.Path, strings.Join(e.Keys, ", "))
+	}
+	return fmt.Sprintf("Unknown daemon configuration keys: %s", strings.Join(e.Keys, ", "))
+}
+
+// DaemonJSON reports whether the engine is configured through daemon.json
+// rather than command line flags. Templates use it to leave out the flags.
+func (ctx EngineConfigContext) DaemonJSON() bool {
+	return ctx.EngineOptions.ConfigMode == engine.ConfigModeDaemonJSON
+}
+
+// renderDaemonConfig renders the engine options of ctx as daemon.json
+// settings. withListener controls whether the API listener, TLS and storage
+// settings are included, for provisioners which always pass those as flags.
+func renderDaemonConfig(ctx EngineConfigContext, withListener bool) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	engineOptions := ctx.EngineOptions
+
+	if withListener {
+		config["hosts"] = []string{
+			fmt.Sprintf("tcp://0.0.0.0:%d", ctx.DockerPort),
+			"unix:///var/run/docker.sock",
+		}
+		config["tlsverify"] = true
+		config["tlscacert"] = ctx.AuthOptions.CaCertRemotePath
+		config["tlscert"] = ctx.AuthOptions.ServerCertRemotePath
+		config["tlskey"] = ctx.AuthOptions.ServerKeyRemotePath
+
+		if engineOptions.StorageDriver != "" {
+			config["storage-driver"] = engineOptions.StorageDriver
+		}
+	}
+
+	if len(engineOptions.Labels) > 0 {
+		config["labels"] = engineOptions.Labels
+	}
+	if len(engineOptions.DNS) > 0 {
+		config["dns"] = engineOptions.DNS
+	}
+	if len(engineOptions.RegistryMirror) > 0 {
+		config["registry-mirrors"] = engineOptions.RegistryMirror
+	}
+	if len(engineOptions.InsecureRegistry) > 0 {
+		config["insecure-registries"] = engineOptions.InsecureRegistry
+	}
+	if engineOptions.LogLevel != "" {
+		config["log-level"] = engineOptions.LogLevel
+	}
+	if engineOptions.GraphDir != "" {
+		config["data-root"] = engineOptions.GraphDir
+	}
+	if engineOptions.Ipv6 {
+		config["ipv6"] = true
+	}
+	if engineOptions.SelinuxEnabled {
+		config["selinux-enabled"] = true
+	}
+
+	for _, flag := range engineOptions.ArbitraryFlags {
+		addDaemonConfigFlag(config, flag)
+	}
+
+	if err := validateDaemonConfig(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ValidateDaemonConfig checks that engineOptions only render keys known to
+// dockerd, so that a bad engine option is caught before a host is created.
+func ValidateDaemonConfig(engineOptions engine.Options) error {
+	_, err := renderDaemonConfig(EngineConfigContext{EngineOptions: engineOptions}, false)
+	return err
+}
+
+// withDaemonConfig sets the daemon.json settings of dockerOptions when ctx
+// selects the daemon-json engine config mode.
+func withDaemonConfig(dockerOptions *DockerOptions, ctx EngineConfigContext, withListener bool) (*DockerOptions, error) {
+	if !ctx.DaemonJSON() {
+		return dockerOptions, nil
+	}
+
+	config, err := renderDaemonConfig(ctx, withListener)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerOptions.DaemonConfig = config
+	dockerOptions.DaemonConfigPath = DefaultDaemonConfigPath
+
+	return dockerOptions, nil
+}
+
+// addDaemonConfigFlag adds an arbitrary engine flag in the form flag=value
+// (or just flag for booleans) to config.
+func addDaemonConfigFlag(config map[string]interface{}, flag string) {
+	parts := strings.SplitN(strings.TrimLeft(flag, "-"), "=", 2)
+	name := parts[0]
+
+	if len(parts) == 1 {
+		config[name] = true
+		return
+	}
+	value := parts[1]
+
+	if key, ok := daemonConfigListKeys[name]; ok {
+		values, _ := config[key].([]string)
+		config[key] = append(values, value)
+		return
+	}
+
+	if key, ok := daemonConfigMapKeys[name]; ok {
+		values, ok := config[key].(map[string]string)
+		if !ok {
+			values = map[string]string{}
+		}
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) == 2 {
+			values[kv[0]] = kv[1]
+		}
+		config[key] = values
+		return
+	}
+
+	// values that don't parse are kept as is for dockerd to report them
+	config[name] = value
+	if daemonConfigBoolKeys[name] {
+		if b, err := strconv.ParseBool(value); err == nil {
+			config[name] = b
+		}
+	} else if daemonConfigIntKeys[name] {
+		if i, err := strconv.Atoi(value); err == nil {
+			config[name] = i
+		}
+	}
+}
+
+func validateDaemonConfig(config map[string]interface{}) error {
+	unknown := []string{}
+	for key := range config {
+		if !daemonConfigKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return ErrUnknownDaemonConfigKeys{
+			Keys: unknown,
+		}
+	}
+
+	return nil
+}
+
+// mergeDaemonConfig merges the rendered settings into the content of an
+// existing daemon.json. Settings managed by machine replace existing ones,
+// everything else is kept as is. The merged settings are validated, since
+// dockerd refuses to start with keys it doesn't know about.
+func mergeDaemonConfig(existing string, rendered map[string]interface{}) (map[string]interface{}, error) {
+	merged := map[string]interface{}{}
+
+	if strings.TrimSpace(existing) != "" {
+		if err := json.Unmarshal([]byte(existing), &merged); err != nil {
+			return nil, fmt.Errorf("Error parsing the existing daemon configuration: %s", err)
+		}
+	}
+
+	for key, value := range rendered {
+		merged[key] = value
+	}
+
+	if err := validateDaemonConfig(merged); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+func marshalDaemonConfig(config map[string]interface{}) (string, error) {
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// readRemoteFile returns the content of the file at filePath on the remote
+// host, or an empty string if it doesn't exist.
+func readRemoteFile(p SSHCommander, filePath string) (string, error) {
+	return p.SSHCommand(fmt.Sprintf("if [ -f %s ]; then sudo cat %s; fi", filePath, filePath))
+}
+
+// writeDaemonConfig merges config into the daemon.json at configPath on the
+// remote host. The file found the first time is backed up, later provisions
+// keep that backup rather than replacing it with a file machine wrote.
+func writeDaemonConfig(p SSHCommander, configPath string, config map[string]interface{}) error {
+	existing, err := readRemoteFile(p, configPath)
+	if err != nil {
+		return err
+	}
+
+	merged, err := mergeDaemonConfig(existing, config)
+	if unknownKeys, ok := err.(ErrUnknownDaemonConfigKeys); ok {
+		unknownKeys.Path = configPath
+		return unknownKeys
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := marshalDaemonConfig(merged)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(existing) != "" {
+		log.Debugf("Backing up %s to %s.bak unless it's backed up already", configPath, configPath)
+		if _, err := p.SSHCommand(fmt.Sprintf("[ -f %s.bak ] || sudo cp %s %s.bak", configPath, configPath, configPath)); err != nil {
+			return err
+		}
+	}
+
+	escaped := strings.Replace(content, "'", `'\''`, -1)
+	_, err = p.SSHCommand(fmt.Sprintf("sudo mkdir -p $(dirname %s) && printf '%%s' '%s' | sudo tee %s", configPath, escaped, configPath))
+	return err
+}
@@ -0,0 +1,177 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/provision/provisiontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderDaemonConfig(t *testing.T) {
+	ctx := EngineConfigContext{
+		DockerPort: 2376,
+		AuthOptions: auth.Options{
+			CaCertRemotePath:     "/etc/docker/ca.pem",
+			ServerCertRemotePath: "/etc/docker/server.pem",
+			ServerKeyRemotePath:  "/etc/docker/server-key.pem",
+		},
+		EngineOptions: engine.Options{
+			ConfigMode:       engine.ConfigModeDaemonJSON,
+			Labels:           []string{"env=test"},
+			DNS:              []string{"8.8.8.8"},
+			RegistryMirror:   []string{"https://mirror.example.com"},
+			InsecureRegistry: []string{"registry.local:5000"},
+			StorageDriver:    "overlay2",
+			LogLevel:         "debug",
+			ArbitraryFlags:   []string{"log-opt=max-size=10m", "exec-opt=native.cgroupdriver=systemd", "live-restore", "mtu=1400"},
+		},
+	}
+
+	config, err := renderDaemonConfig(ctx, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tcp://0.0.0.0:2376", "unix:///var/run/docker.sock"}, config["hosts"])
+	assert.Equal(t, true, config["tlsverify"])
+	assert.Equal(t, "/etc/docker/ca.pem", config["tlscacert"])
+	assert.Equal(t, "/etc/docker/server.pem", config["tlscert"])
+	assert.Equal(t, "/etc/docker/server-key.pem", config["tlskey"])
+	assert.Equal(t, []string{"env=test"}, config["labels"])
+	assert.Equal(t, []string{"8.8.8.8"}, config["dns"])
+	assert.Equal(t, []string{"https://mirror.example.com"}, config["registry-mirrors"])
+	assert.Equal(t, []string{"registry.local:5000"}, config["insecure-registries"])
+	assert.Equal(t, "overlay2", config["storage-driver"])
+	assert.Equal(t, "debug", config["log-level"])
+	assert.Equal(t, map[string]string{"max-size": "10m"}, config["log-opts"])
+	assert.Equal(t, []string{"native.cgroupdriver=systemd"}, config["exec-opts"])
+	assert.Equal(t, true, config["live-restore"])
+	assert.Equal(t, 1400, config["mtu"])
+}
+
+func TestRenderDaemonConfigWithoutListener(t *testing.T) {
+	config, err := renderDaemonConfig(EngineConfigContext{
+		EngineOptions: engine.Options{
+			StorageDriver: "overlay2",
+			Labels:        []string{"env=test"},
+		},
+	}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"labels": []string{"env=test"}}, config)
+}
+
+func TestRenderDaemonConfigUnknownKeys(t *testing.T) {
+	_, err := renderDaemonConfig(EngineConfigContext{
+		EngineOptions: engine.Options{
+			ArbitraryFlags: []string{"no-such-option=1", "bogus"},
+		},
+	}, false)
+
+	assert.Equal(t, ErrUnknownDaemonConfigKeys{Keys: []string{"bogus", "no-such-option"}}, err)
+}
+
+func TestMergeDaemonConfig(t *testing.T) {
+	existing := `{"debug": true, "labels": ["old=label"]}`
+
+	merged, err := mergeDaemonConfig(existing, map[string]interface{}{
+		"labels": []string{"new=label"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, true, merged["debug"])
+	assert.Equal(t, []string{"new=label"}, merged["labels"])
+}
+
+func TestMergeDaemonConfigUnknownExistingKeys(t *testing.T) {
+	_, err := mergeDaemonConfig(`{"not-a-docker-key": 1}`, map[string]interface{}{
+		"debug": true,
+	})
+
+	assert.Equal(t, ErrUnknownDaemonConfigKeys{Keys: []string{"not-a-docker-key"}}, err)
+}
+
+func TestWriteDaemonConfigUnknownExistingKeys(t *testing.T) {
+	sshCmder := &recordingSSHCommander{
+		responses: map[string]string{
+			"if [ -f /etc/docker/daemon.json ]; then sudo cat /etc/docker/daemon.json; fi": `{"not-a-docker-key": 1}`,
+		},
+	}
+
+	err := writeDaemonConfig(sshCmder, "/etc/docker/daemon.json", map[string]interface{}{
+		"debug": true,
+	})
+
+	assert.EqualError(t, err, "Unknown daemon configuration keys in /etc/docker/daemon.json on the host: not-a-docker-key, remove them for dockerd to start")
+	assert.Len(t, sshCmder.commands, 1)
+}
+
+func TestAddDaemonConfigFlagTypes(t *testing.T) {
+	config := map[string]interface{}{}
+
+	for _, flag := range []string{"max-concurrent-downloads=1", "mtu=0", "debug=1", "iptables=false", "log-level=1", "tlsverify"} {
+		addDaemonConfigFlag(config, flag)
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"max-concurrent-downloads": 1,
+		"mtu":                      0,
+		"debug":                    true,
+		"iptables":                 false,
+		"log-level":                "1",
+		"tlsverify":                true,
+	}, config)
+}
+
+func TestWriteDaemonConfigBacksUpExistingFile(t *testing.T) {
+	readCommand := "if [ -f /etc/docker/daemon.json ]; then sudo cat /etc/docker/daemon.json; fi"
+	backupCommand := "[ -f /etc/docker/daemon.json.bak ] || sudo cp /etc/docker/daemon.json /etc/docker/daemon.json.bak"
+	writeCommand := "sudo mkdir -p $(dirname /etc/docker/daemon.json) && printf '%s' '{\n  \"debug\": true,\n  \"log-level\": \"info\"\n}' | sudo tee /etc/docker/daemon.json"
+
+	sshCmder := &provisiontest.FakeSSHCommander{
+		Responses: map[string]string{
+			readCommand:   `{"debug": true}`,
+			backupCommand: "",
+			writeCommand:  "",
+		},
+	}
+
+	err := writeDaemonConfig(sshCmder, "/etc/docker/daemon.json", map[string]interface{}{
+		"log-level": "info",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestWriteDaemonConfigWithoutExistingFile(t *testing.T) {
+	sshCmder := &recordingSSHCommander{}
+
+	err := writeDaemonConfig(sshCmder, "/etc/docker/daemon.json", map[string]interface{}{
+		"log-level": "info",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, sshCmder.commands, 2)
+	assert.Contains(t, sshCmder.commands[1], "sudo tee /etc/docker/daemon.json")
+}
+
+func TestGenerateDockerOptionsBoot2DockerDaemonJSON(t *testing.T) {
+	p := &Boot2DockerProvisioner{
+		Driver: &fakedriver.Driver{},
+		EngineOptions: engine.Options{
+			ConfigMode:     engine.ConfigModeDaemonJSON,
+			RegistryMirror: []string{"https://mirror.example.com"},
+		},
+	}
+
+	dockerCfg, err := p.GenerateDockerOptions(2376)
+
+	assert.NoError(t, err)
+	assert.Contains(t, dockerCfg.EngineOptions, "--config-file /var/lib/boot2docker/daemon.json")
+	assert.Contains(t, dockerCfg.EngineOptions, "-H tcp://0.0.0.0:2376")
+	assert.NotContains(t, dockerCfg.EngineOptions, "--registry-mirror")
+	assert.Equal(t, "/var/lib/boot2docker/daemon.json", dockerCfg.DaemonConfigPath)
+	assert.Equal(t, []string{"https://mirror.example.com"}, dockerCfg.DaemonConfig["registry-mirrors"])
+	assert.NotContains(t, dockerCfg.DaemonConfig, "hosts")
+}
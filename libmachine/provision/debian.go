@@ -15,7 +15,8 @@ import (
 
 func init() {
 	Register("Debian", &RegisteredProvisioner{
-		New: NewDebianProvisioner,
+		New:      NewDebianProvisioner,
+		Priority: PriorityFamily,
 	})
 }
 
@@ -52,6 +53,9 @@ func (provisioner *DebianProvisioner) Package(name string, action pkgaction.Pack
 	switch name {
 	case "docker":
 		name = "docker-engine"
+		if packageAction == "install" {
+			name = aptDockerPackage(name, provisioner.EngineOptions.InstallVersion)
+		}
 	}
 
 	if updateMetadata {
@@ -104,14 +108,12 @@ func (provisioner *DebianProvisioner) Provision(swarmOptions swarm.Options, auth
 	}
 
 	log.Debug("installing base packages")
-	for _, pkg := range provisioner.Packages {
-		if err := provisioner.Package(pkg, pkgaction.Install); err != nil {
-			return err
-		}
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
 	}
 
 	log.Debug("installing docker")
-	if err := installDockerGeneric(provisioner, engineOptions.InstallURL); err != nil {
+	if err := installDockerGeneric(provisioner, engineOptions); err != nil {
 		return err
 	}
 
@@ -0,0 +1,274 @@
+package provision
+
+import (
+	"crypto/sha256"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/mcndockerclient"
+	"github.com/docker/machine/libmachine/swarm"
+	"github.com/docker/machine/libmachine/versioncmp"
+	"github.com/samalba/dockerclient"
+)
+
+var (
+	errNoPEMData = errors.New("no PEM data found")
+)
+
+// Drift is a difference between the persisted configuration of a machine
+// and the live configuration found on its host.
+type Drift struct {
+	Setting string
+	Desired string
+	Actual  string
+
+	// Diff is set instead of Desired and Actual for configuration files.
+	Diff string
+}
+
+// CheckDrift compares the live configuration of the host behind p, read over
+// SSH and the Docker API, with the given desired options.
+func CheckDrift(p Provisioner, swarmOptions swarm.Options, authOptions auth.Options, engineOptions engine.Options) ([]Drift, error) {
+	drifts := []Drift{}
+
+	plan, err := PlanProvision(p, swarmOptions, authOptions, engineOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// the provisioner now holds the auth options with the remote paths
+	remoteAuthOptions := p.GetAuthOptions()
+
+	for _, file := range plan.Files {
+		if file.Generated || !file.Changed() || isCertFile(file.Path, remoteAuthOptions) {
+			continue
+		}
+
+		diff, err := file.Diff()
+		if err != nil {
+			return nil, err
+		}
+
+		drifts = append(drifts, Drift{
+			Setting: file.Path,
+			Diff:    diff,
+		})
+	}
+
+	certDrifts, err := certDrift(p, remoteAuthOptions, authOptions)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, certDrifts...)
+
+	dockerURL, err := engineURL(p)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerHost := &mcndockerclient.RemoteDocker{
+		HostURL:    dockerURL,
+		AuthOption: &authOptions,
+	}
+
+	version := ""
+	if engineOptions.InstallVersion != "" {
+		if version, err = mcndockerclient.DockerVersion(dockerHost); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := mcndockerclient.DockerInfo(dockerHost)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, engineDrift(info, version, engineOptions, p.GetDriver().DriverName())...)
+
+	if swarmOptions.IsSwarm {
+		containers, err := mcndockerclient.ListContainers(dockerHost, true)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, swarmDrift(containers, swarmOptions)...)
+	}
+
+	return drifts, nil
+}
+
+// isCertFile reports whether filePath is one of the remote certificate
+// files, which are compared by fingerprint instead.
+func isCertFile(filePath string, authOptions auth.Options) bool {
+	return filePath == authOptions.CaCertRemotePath ||
+		filePath == authOptions.ServerCertRemotePath ||
+		filePath == authOptions.ServerKeyRemotePath
+}
+
+// certDrift compares the fingerprints of the CA and server certificates on
+// the host with the local ones.
+func certDrift(p SSHCommander, remoteAuthOptions, authOptions auth.Options) ([]Drift, error) {
+	drifts := []Drift{}
+
+	certs := []struct {
+		name       string
+		localPath  string
+		remotePath string
+	}{
+		{"CA certificate", authOptions.CaCertPath, remoteAuthOptions.CaCertRemotePath},
+		{"server certificate", authOptions.ServerCertPath, remoteAuthOptions.ServerCertRemotePath},
+	}
+
+	for _, c := range certs {
+		local, err := ioutil.ReadFile(c.localPath)
+		if err != nil {
+			return nil, err
+		}
+
+		desired, err := certFingerprint(local)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %s", c.localPath, err)
+		}
+
+		remote, err := readRemoteFile(p, c.remotePath)
+		if err != nil {
+			return nil, err
+		}
+
+		actual, err := certFingerprint([]byte(remote))
+		if err != nil {
+			actual = "missing"
+		}
+
+		if desired != actual {
+			drifts = append(drifts, Drift{
+				Setting: fmt.Sprintf("%s %s", c.name, c.remotePath),
+				Desired: desired,
+				Actual:  actual,
+			})
+		}
+	}
+
+	return drifts, nil
+}
+
+// certFingerprint returns the SHA-256 fingerprint of the first certificate
+// in pemData.
+func certFingerprint(pemData []byte) (string, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return "", errNoPEMData
+	}
+
+	sum := sha256.Sum256(block.Bytes)
+	hexSum := make([]string, len(sum))
+	for i, b := range sum {
+		hexSum[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hexSum, ":"), nil
+}
+
+// engineDrift compares the settings reported by the engine with the desired
+// engine options.
+func engineDrift(info *dockerclient.Info, version string, engineOptions engine.Options, driverName string) []Drift {
+	drifts := []Drift{}
+
+	if engineOptions.InstallVersion != "" && !versioncmp.Equal(version, engineOptions.InstallVersion) {
+		drifts = append(drifts, Drift{
+			Setting: "engine version",
+			Desired: engineOptions.InstallVersion,
+			Actual:  version,
+		})
+	}
+
+	desiredLabels := append([]string{fmt.Sprintf("provider=%s", driverName)}, engineOptions.Labels...)
+	if !sameStrings(desiredLabels, info.Labels) {
+		drifts = append(drifts, Drift{
+			Setting: "labels",
+			Desired: strings.Join(sortedStrings(desiredLabels), ", "),
+			Actual:  strings.Join(sortedStrings(info.Labels), ", "),
+		})
+	}
+
+	if engineOptions.StorageDriver != "" && engineOptions.StorageDriver != info.Driver {
+		drifts = append(drifts, Drift{
+			Setting: "storage driver",
+			Desired: engineOptions.StorageDriver,
+			Actual:  info.Driver,
+		})
+	}
+
+	if engineOptions.GraphDir != "" && engineOptions.GraphDir != info.DockerRootDir {
+		drifts = append(drifts, Drift{
+			Setting: "data root",
+			Desired: engineOptions.GraphDir,
+			Actual:  info.DockerRootDir,
+		})
+	}
+
+	return drifts
+}
+
+// swarmDrift checks that the swarm containers described by swarmOptions
+// exist and are running.
+func swarmDrift(containers []dockerclient.Container, swarmOptions swarm.Options) []Drift {
+	drifts := []Drift{}
+
+	expected := []string{}
+	if swarmOptions.Master {
+		expected = append(expected, "swarm-agent-master")
+	}
+	if swarmOptions.Agent {
+		expected = append(expected, "swarm-agent")
+	}
+
+	status := map[string]string{}
+	for _, container := range containers {
+		for _, name := range container.Names {
+			status[strings.TrimPrefix(name, "/")] = container.Status
+		}
+	}
+
+	for _, name := range expected {
+		actual, ok := status[name]
+		if !ok {
+			actual = "missing"
+		}
+
+		if !strings.HasPrefix(actual, "Up") {
+			drifts = append(drifts, Drift{
+				Setting: fmt.Sprintf("swarm container %s", name),
+				Desired: "running",
+				Actual:  actual,
+			})
+		}
+	}
+
+	return drifts
+}
+
+func sortedStrings(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA, sortedB := sortedStrings(a), sortedStrings(b)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,80 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/swarm"
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCert = `-----BEGIN CERTIFICATE-----
+AAECAwQFBgcICQ==
+-----END CERTIFICATE-----
+`
+
+func TestCertFingerprint(t *testing.T) {
+	fingerprint, err := certFingerprint([]byte(testCert))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1F:82:5A:A2:F0:02:0E:F7:CF:91:DF:A3:0D:A4:66:8D:79:1C:5D:48:24:FC:8E:41:35:4B:89:EC:05:79:5A:B3", fingerprint)
+
+	_, err = certFingerprint([]byte("not a cert"))
+	assert.Equal(t, errNoPEMData, err)
+}
+
+func TestEngineDrift(t *testing.T) {
+	info := &dockerclient.Info{
+		Driver: "aufs",
+		Labels: []string{"env=prod", "provider=virtualbox"},
+	}
+
+	drifts := engineDrift(info, "18.09.1", engine.Options{
+		Labels:        []string{"env=prod"},
+		StorageDriver: "overlay2",
+	}, "virtualbox")
+
+	assert.Equal(t, []Drift{
+		{Setting: "storage driver", Desired: "overlay2", Actual: "aufs"},
+	}, drifts)
+
+	drifts = engineDrift(info, "18.09.1", engine.Options{
+		Labels: []string{"env=staging"},
+	}, "virtualbox")
+
+	assert.Equal(t, []Drift{
+		{Setting: "labels", Desired: "env=staging, provider=virtualbox", Actual: "env=prod, provider=virtualbox"},
+	}, drifts)
+}
+
+func TestEngineDriftVersion(t *testing.T) {
+	info := &dockerclient.Info{
+		Labels: []string{"provider=virtualbox"},
+	}
+
+	assert.Empty(t, engineDrift(info, "18.09.0", engine.Options{InstallVersion: "18.09"}, "virtualbox"))
+	assert.Empty(t, engineDrift(info, "18.09.1", engine.Options{InstallVersion: "18.09.1"}, "virtualbox"))
+	assert.Equal(t, []Drift{
+		{Setting: "engine version", Desired: "18.09.1", Actual: "18.09.0"},
+	}, engineDrift(info, "18.09.0", engine.Options{InstallVersion: "18.09.1"}, "virtualbox"))
+}
+
+func TestSwarmDrift(t *testing.T) {
+	containers := []dockerclient.Container{
+		{Names: []string{"/swarm-agent"}, Status: "Up 2 hours"},
+		{Names: []string{"/swarm-agent-master"}, Status: "Exited (1) 3 minutes ago"},
+	}
+
+	drifts := swarmDrift(containers, swarm.Options{IsSwarm: true, Master: true, Agent: true})
+
+	assert.Equal(t, []Drift{
+		{Setting: "swarm container swarm-agent-master", Desired: "running", Actual: "Exited (1) 3 minutes ago"},
+	}, drifts)
+
+	drifts = swarmDrift([]dockerclient.Container{}, swarm.Options{IsSwarm: true, Agent: true})
+
+	assert.Equal(t, []Drift{
+		{Setting: "swarm container swarm-agent", Desired: "running", Actual: "missing"},
+	}, drifts)
+}
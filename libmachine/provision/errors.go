@@ -3,12 +3,23 @@ package provision
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	ErrDetectionFailed = errors.New("OS type not recognized")
 )
 
+// ErrUnknownProvisioner is returned when a provisioner is requested by a
+// name which isn't registered.
+type ErrUnknownProvisioner struct {
+	Name string
+}
+
+func (e ErrUnknownProvisioner) Error() string {
+	return fmt.Sprintf("Unknown provisioner %q, expected one of: %s", e.Name, strings.Join(RegisteredNames(), ", "))
+}
+
 type ErrDaemonAvailable struct {
 	wrappedErr error
 }
@@ -1,6 +1,8 @@
 package provision
 
 import (
+	"io"
+
 	"github.com/docker/machine/libmachine/auth"
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/engine"
@@ -27,6 +29,10 @@ func (fp *FakeProvisioner) SSHCommand(args string) (string, error) {
 	return "", nil
 }
 
+func (fp *FakeProvisioner) SSHStream(command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	return nil
+}
+
 func (fp *FakeProvisioner) String() string {
 	return "fakeprovisioner"
 }
@@ -47,6 +53,14 @@ func (fp *FakeProvisioner) GetSwarmOptions() swarm.Options {
 	return swarm.Options{}
 }
 
+func (fp *FakeProvisioner) GetEngineOptions() engine.Options {
+	return engine.Options{}
+}
+
+func (fp *FakeProvisioner) SetEngineOptions(engineOptions engine.Options) {}
+
+func (fp *FakeProvisioner) SetAuthOptions(authOptions auth.Options) {}
+
 func (fp *FakeProvisioner) Package(name string, action pkgaction.PackageAction) error {
 	return nil
 }
@@ -6,7 +6,8 @@ import (
 
 func init() {
 	Register("Fedora", &RegisteredProvisioner{
-		New: NewFedoraProvisioner,
+		New:      NewFedoraProvisioner,
+		Priority: PriorityDistribution,
 	})
 }
 
@@ -0,0 +1,31 @@
+package provision
+
+import (
+	"github.com/docker/machine/libmachine/drivers"
+)
+
+func init() {
+	Register("Flatcar", &RegisteredProvisioner{
+		New:      NewFlatcarProvisioner,
+		Priority: PrioritySpecific,
+	})
+}
+
+// NewFlatcarProvisioner returns a provisioner for Flatcar Container Linux,
+// which keeps the CoreOS layout, including cloudinit and the path of dockerd,
+// under its own os-release ID.
+func NewFlatcarProvisioner(d drivers.Driver) Provisioner {
+	return &FlatcarProvisioner{
+		CoreOSProvisioner{
+			NewSystemdProvisioner("flatcar", d),
+		},
+	}
+}
+
+type FlatcarProvisioner struct {
+	CoreOSProvisioner
+}
+
+func (provisioner *FlatcarProvisioner) String() string {
+	return "flatcar"
+}
@@ -80,6 +80,18 @@ func (provisioner *GenericProvisioner) GetSwarmOptions() swarm.Options {
 	return provisioner.SwarmOptions
 }
 
+func (provisioner *GenericProvisioner) GetEngineOptions() engine.Options {
+	return provisioner.EngineOptions
+}
+
+func (provisioner *GenericProvisioner) SetEngineOptions(engineOptions engine.Options) {
+	provisioner.EngineOptions = engineOptions
+}
+
+func (provisioner *GenericProvisioner) SetAuthOptions(authOptions auth.Options) {
+	provisioner.AuthOptions = authOptions
+}
+
 func (provisioner *GenericProvisioner) SetOsReleaseInfo(info *OsRelease) {
 	provisioner.OsReleaseInfo = info
 }
@@ -97,7 +109,7 @@ func (provisioner *GenericProvisioner) GenerateDockerOptions(dockerPort int) (*D
 	provisioner.EngineOptions.Labels = append(provisioner.EngineOptions.Labels, driverNameLabel)
 
 	engineConfigTmpl := `
-DOCKER_OPTS='
+DOCKER_OPTS='{{ if not .DaemonJSON }}
 -H tcp://0.0.0.0:{{.DockerPort}}
 -H unix:///var/run/docker.sock
 --storage-driver {{.EngineOptions.StorageDriver}}
@@ -109,7 +121,7 @@ DOCKER_OPTS='
 {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}}
 {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}}
 {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}}
-{{ end }}
+{{ end }}{{ end }}
 '
 {{range .EngineOptions.Env}}export \"{{ printf "%q" . }}\"
 {{end}}
@@ -127,10 +139,10 @@ DOCKER_OPTS='
 
 	t.Execute(&engineCfg, engineConfigContext)
 
-	return &DockerOptions{
+	return withDaemonConfig(&DockerOptions{
 		EngineOptions:     engineCfg.String(),
 		EngineOptionsPath: provisioner.DaemonOptionsFile,
-	}, nil
+	}, engineConfigContext, true)
 }
 
 func (provisioner *GenericProvisioner) GetDriver() drivers.Driver {
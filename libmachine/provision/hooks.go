@@ -0,0 +1,126 @@
+package provision
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+)
+
+const (
+	PreProvisionStage  = "pre-provision"
+	PostProvisionStage = "post-provision"
+)
+
+// ErrProvisionScript is returned when a user supplied provisioning script
+// fails on the remote host.
+type ErrProvisionScript struct {
+	Stage  string
+	Script string
+	Err    error
+}
+
+func (e ErrProvisionScript) Error() string {
+	return fmt.Sprintf("Error running %s script %s: %s", e.Stage, e.Script, e.Err)
+}
+
+// SSHStreamer is implemented by the provisioners which run the commands fed
+// with data and streaming their output themselves, instead of through the SSH
+// client of their driver.
+type SSHStreamer interface {
+	SSHStream(command string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+func streamSSHCommand(p Provisioner, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if streamer, ok := p.(SSHStreamer); ok {
+		return streamer.SSHStream(command, stdin, stdout, stderr)
+	}
+
+	return streamSSHCommandWithStdin(p.GetDriver(), command, stdin, stdout, stderr)
+}
+
+// RunProvisionScript sends the local script to the host behind the
+// provisioner and executes it as the SSH user, logging its output through the
+// machine logger as it runs. The script is fed to the standard input of the
+// command that runs it, so its size isn't bound by the command line length.
+func RunProvisionScript(p Provisioner, stage, script string) error {
+	if script == "" {
+		return nil
+	}
+
+	f, err := os.Open(script)
+	if err != nil {
+		return ErrProvisionScript{
+			Stage:  stage,
+			Script: script,
+			Err:    fmt.Errorf("reading script: %s", err),
+		}
+	}
+	defer f.Close()
+
+	log.Infof("Running %s script %s...", stage, script)
+
+	stdout := newLineLogger(func(line string) { log.Infof("(%s) %s", stage, line) })
+	stderr := newLineLogger(func(line string) { log.Warnf("(%s) %s", stage, line) })
+
+	err = streamSSHCommand(p, provisionScriptCommand(stage), f, stdout, stderr)
+
+	stdout.Flush()
+	stderr.Flush()
+
+	if err != nil {
+		return ErrProvisionScript{
+			Stage:  stage,
+			Script: script,
+			Err:    err,
+		}
+	}
+
+	return nil
+}
+
+// provisionScriptCommand returns the command that writes the script read from
+// its standard input to the remote host, runs it and removes it.
+func provisionScriptCommand(stage string) string {
+	remoteScript := path.Join("/tmp", fmt.Sprintf("docker-machine-%s.sh", stage))
+
+	return fmt.Sprintf("cat > %s && chmod +x %s && %s; status=$?; rm -f %s; exit $status",
+		remoteScript, remoteScript, remoteScript, remoteScript)
+}
+
+// lineLogger is an io.Writer logging every complete line written to it.
+type lineLogger struct {
+	buf     bytes.Buffer
+	logLine func(string)
+}
+
+func newLineLogger(logLine func(string)) *lineLogger {
+	return &lineLogger{logLine: logLine}
+}
+
+func (l *lineLogger) Write(p []byte) (int, error) {
+	l.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(l.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := l.buf.Next(i + 1)
+		l.logLine(strings.TrimRight(string(line), "\r\n"))
+	}
+
+	return len(p), nil
+}
+
+// Flush logs the last line written, when it isn't terminated by a newline.
+func (l *lineLogger) Flush() {
+	if l.buf.Len() > 0 {
+		l.logLine(strings.TrimRight(l.buf.String(), "\r\n"))
+		l.buf.Reset()
+	}
+}
@@ -0,0 +1,73 @@
+package provision
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunProvisionScriptWithoutScript(t *testing.T) {
+	err := RunProvisionScript(&FakeProvisioner{}, PreProvisionStage, "")
+
+	assert.NoError(t, err)
+}
+
+func TestRunProvisionScriptMissingScript(t *testing.T) {
+	err := RunProvisionScript(&FakeProvisioner{}, PostProvisionStage, "/does/not/exist.sh")
+
+	assert.IsType(t, ErrProvisionScript{}, err)
+	assert.Contains(t, err.Error(), "post-provision script /does/not/exist.sh")
+}
+
+func TestRunProvisionScript(t *testing.T) {
+	script, err := ioutil.TempFile("", "machine-hook-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(script.Name())
+
+	script.WriteString("#!/bin/sh\necho hello\n")
+	script.Close()
+
+	commands := []string{}
+	uploaded := ""
+	defer func(stream func(drivers.Driver, string, io.Reader, io.Writer, io.Writer) error) {
+		streamSSHCommandWithStdin = stream
+	}(streamSSHCommandWithStdin)
+	streamSSHCommandWithStdin = func(d drivers.Driver, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+		commands = append(commands, command)
+		data, err := ioutil.ReadAll(stdin)
+		uploaded += string(data)
+		return err
+	}
+
+	p := NewDebianProvisioner(&fakedriver.Driver{})
+	err = RunProvisionScript(p, PreProvisionStage, script.Name())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"cat > /tmp/docker-machine-pre-provision.sh && chmod +x /tmp/docker-machine-pre-provision.sh && /tmp/docker-machine-pre-provision.sh; status=$?; rm -f /tmp/docker-machine-pre-provision.sh; exit $status",
+	}, commands)
+	assert.Equal(t, "#!/bin/sh\necho hello\n", uploaded)
+}
+
+func TestLineLogger(t *testing.T) {
+	var lines []string
+
+	logger := newLineLogger(func(line string) {
+		lines = append(lines, line)
+	})
+	logger.Write([]byte("fir"))
+	logger.Write([]byte("st\r\nsecond\nthi"))
+
+	assert.Equal(t, []string{"first", "second"}, lines)
+
+	logger.Flush()
+
+	assert.Equal(t, []string{"first", "second", "thi"}, lines)
+}
@@ -0,0 +1,61 @@
+package provision
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/provision/serviceaction"
+)
+
+// OpenRCProvisioner is the base of the provisioners for distributions which
+// manage services with OpenRC instead of systemd.
+type OpenRCProvisioner struct {
+	GenericProvisioner
+}
+
+func NewOpenRCProvisioner(osReleaseID string, d drivers.Driver) OpenRCProvisioner {
+	return OpenRCProvisioner{
+		GenericProvisioner{
+			SSHCommander:      GenericSSHCommander{Driver: d},
+			DockerOptionsDir:  "/etc/docker",
+			DaemonOptionsFile: "/etc/conf.d/docker",
+			OsReleaseID:       osReleaseID,
+			Packages: []string{
+				"curl",
+			},
+			Driver: d,
+		},
+	}
+}
+
+// openRCCommand returns the command running action on the service name, or
+// an empty string if OpenRC has no equivalent for it.
+func openRCCommand(name string, action serviceaction.ServiceAction) string {
+	switch action {
+	case serviceaction.Start, serviceaction.Stop, serviceaction.Restart:
+		return fmt.Sprintf("sudo rc-service %s %s", name, action.String())
+	case serviceaction.Enable:
+		return fmt.Sprintf("sudo rc-update add %s default", name)
+	case serviceaction.Disable:
+		return fmt.Sprintf("sudo rc-update del %s default", name)
+	}
+
+	return ""
+}
+
+func (p *OpenRCProvisioner) Service(name string, action serviceaction.ServiceAction) error {
+	command := openRCCommand(name, action)
+	if command == "" {
+		// OpenRC reads the service configuration when it starts the
+		// service, there's nothing like a systemd daemon-reload
+		log.Debugf("Nothing to do for service action %s with OpenRC", action.String())
+		return nil
+	}
+
+	if _, err := p.SSHCommand(command); err != nil {
+		return err
+	}
+
+	return nil
+}
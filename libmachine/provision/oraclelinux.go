@@ -6,7 +6,8 @@ import (
 
 func init() {
 	Register("OracleLinux", &RegisteredProvisioner{
-		New: NewOracleLinuxProvisioner,
+		New:      NewOracleLinuxProvisioner,
+		Priority: PriorityDistribution,
 	})
 }
 
@@ -57,6 +57,34 @@ PRETTY_NAME="Fedora 23 (Twenty Three)"
 ANSI_COLOR="0;34"
 HOME_URL="https://fedoraproject.org/"
 BUG_REPORT_URL="https://bugzilla.redhat.com/"
+`)
+		alpine = []byte(`NAME="Alpine Linux"
+ID=alpine
+VERSION_ID=3.8.1
+PRETTY_NAME="Alpine Linux v3.8"
+HOME_URL="http://alpinelinux.org"
+BUG_REPORT_URL="http://bugs.alpinelinux.org"
+`)
+		amazonLinux2 = []byte(`NAME="Amazon Linux"
+VERSION="2"
+ID="amzn"
+ID_LIKE="centos rhel fedora"
+VERSION_ID="2"
+PRETTY_NAME="Amazon Linux 2"
+ANSI_COLOR="0;33"
+CPE_NAME="cpe:2.3:o:amazon:amazon_linux:2"
+HOME_URL="https://amazonlinux.com/"
+`)
+		flatcar = []byte(`NAME="Flatcar Container Linux by Kinvolk"
+ID=flatcar
+ID_LIKE=coreos
+VERSION=2079.3.0
+VERSION_ID=2079.3.0
+BUILD_ID=2019-04-22-2119
+PRETTY_NAME="Flatcar Container Linux by Kinvolk 2079.3.0 (Rhyolite)"
+ANSI_COLOR="38;5;75"
+HOME_URL="https://flatcar-linux.org/"
+BUG_REPORT_URL="https://issues.flatcar-linux.org"
 `)
 	)
 
@@ -168,6 +196,65 @@ BUG_REPORT_URL="https://bugzilla.redhat.com/"
 	if !reflect.DeepEqual(*osr, expectedOsr) {
 		t.Fatal("Error with fedora osr parsing: structs do not match")
 	}
+
+	osr, err = NewOsRelease(alpine)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing os release: %s", err)
+	}
+
+	expectedOsr = OsRelease{
+		Name:         "Alpine Linux",
+		ID:           "alpine",
+		VersionID:    "3.8.1",
+		PrettyName:   "Alpine Linux v3.8",
+		HomeURL:      "http://alpinelinux.org",
+		BugReportURL: "http://bugs.alpinelinux.org",
+	}
+
+	if !reflect.DeepEqual(*osr, expectedOsr) {
+		t.Fatal("Error with alpine osr parsing: structs do not match")
+	}
+
+	osr, err = NewOsRelease(amazonLinux2)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing os release: %s", err)
+	}
+
+	expectedOsr = OsRelease{
+		Name:       "Amazon Linux",
+		Version:    "2",
+		ID:         "amzn",
+		IDLike:     "centos rhel fedora",
+		VersionID:  "2",
+		PrettyName: "Amazon Linux 2",
+		AnsiColor:  "0;33",
+		HomeURL:    "https://amazonlinux.com/",
+	}
+
+	if !reflect.DeepEqual(*osr, expectedOsr) {
+		t.Fatal("Error with amazon linux osr parsing: structs do not match")
+	}
+
+	osr, err = NewOsRelease(flatcar)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing os release: %s", err)
+	}
+
+	expectedOsr = OsRelease{
+		Name:         "Flatcar Container Linux by Kinvolk",
+		ID:           "flatcar",
+		IDLike:       "coreos",
+		Version:      "2079.3.0",
+		VersionID:    "2079.3.0",
+		PrettyName:   "Flatcar Container Linux by Kinvolk 2079.3.0 (Rhyolite)",
+		AnsiColor:    "38;5;75",
+		HomeURL:      "https://flatcar-linux.org/",
+		BugReportURL: "https://issues.flatcar-linux.org",
+	}
+
+	if !reflect.DeepEqual(*osr, expectedOsr) {
+		t.Fatal("Error with flatcar osr parsing: structs do not match")
+	}
 }
 
 func TestParseLine(t *testing.T) {
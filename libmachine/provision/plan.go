@@ -0,0 +1,274 @@
+package provision
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/swarm"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ProvisionPlan describes what provisioning a host would do. It is computed
+// by PlanProvision without running any command that changes the host.
+type ProvisionPlan struct {
+	Provisioner string
+	CertSANs    []string
+	Files       []PlannedFile
+	Commands    []string
+}
+
+// PlannedFile is a file that provisioning writes on the host.
+type PlannedFile struct {
+	Path    string
+	Current string
+	Desired string
+
+	// Generated files are created afresh on every run, e.g. the server
+	// certificate, so their content isn't compared.
+	Generated bool
+}
+
+// Changed reports whether writing the file changes what's on the host.
+func (f PlannedFile) Changed() bool {
+	return f.Generated || f.Current != f.Desired
+}
+
+// Diff returns a unified diff between the current and desired content.
+func (f PlannedFile) Diff() (string, error) {
+	if f.Generated {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(f.Current),
+		B:        difflib.SplitLines(f.Desired),
+		FromFile: f.Path + " (current)",
+		ToFile:   f.Path + " (desired)",
+		Context:  3,
+	})
+}
+
+type basePackager interface {
+	basePackages() []string
+}
+
+func (provisioner *GenericProvisioner) basePackages() []string {
+	return provisioner.Packages
+}
+
+// PlanProvision computes what Provision would change on the host behind p
+// with the given options. It only runs read-only commands on the host.
+func PlanProvision(p Provisioner, swarmOptions swarm.Options, authOptions auth.Options, engineOptions engine.Options) (*ProvisionPlan, error) {
+	driver := p.GetDriver()
+
+	plan := &ProvisionPlan{
+		Provisioner: p.String(),
+	}
+
+	plan.Commands = append(plan.Commands, fmt.Sprintf("set hostname to %s", driver.GetMachineName()))
+
+	if engineOptions.StorageDriver == "" {
+		// keep whatever the provisioner picked when the host was created
+		if out, err := p.SSHCommand("sudo docker info --format '{{.Driver}}'"); err == nil {
+			engineOptions.StorageDriver = strings.TrimSpace(out)
+		}
+	}
+
+	plan.Commands = append(plan.Commands, installCommands(p, engineOptions)...)
+
+	p.SetEngineOptions(engineOptions)
+	p.SetAuthOptions(authOptions)
+	authOptions = setRemoteAuthOptions(p)
+	p.SetAuthOptions(authOptions)
+
+	ip, err := driver.GetIP()
+	if err != nil {
+		return nil, err
+	}
+	plan.CertSANs = append(append([]string{}, authOptions.ServerCertSANs...), ip, "localhost")
+
+	plan.Commands = append(plan.Commands,
+		fmt.Sprintf("generate server certificate %s", authOptions.ServerCertPath),
+		"stop service docker",
+	)
+
+	certFiles, err := plannedCertFiles(p, authOptions)
+	if err != nil {
+		return nil, err
+	}
+	plan.Files = append(plan.Files, certFiles...)
+
+	dockerPort, err := enginePort(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if engineOptions.Rootless() {
+		plan.Commands = append(plan.Commands, fmt.Sprintf("set up rootless engine for %s on port %d", driver.GetSSHUsername(), dockerPort))
+	} else {
+		dkrcfg, err := p.GenerateDockerOptions(dockerPort)
+		if err != nil {
+			return nil, err
+		}
+
+		if dkrcfg != nil {
+			configFiles, err := plannedDockerOptionsFiles(p, dkrcfg)
+			if err != nil {
+				return nil, err
+			}
+			plan.Files = append(plan.Files, configFiles...)
+		}
+
+		plan.Commands = append(plan.Commands, "start service docker")
+	}
+
+	if swarmOptions.IsSwarm {
+		_, containers, err := swarmContainers(p, swarmOptions, authOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, container := range containers {
+			plan.Commands = append(plan.Commands, dockerRunCommand(container))
+		}
+	}
+
+	return plan, nil
+}
+
+func installCommands(p Provisioner, engineOptions engine.Options) []string {
+	commands := []string{}
+
+	if engineOptions.InstallBundle != "" {
+		if _, err := p.SSHCommand("type docker"); err != nil {
+			commands = append(commands, fmt.Sprintf("install docker from engine bundle %s", engineOptions.InstallBundle))
+		}
+		return commands
+	}
+
+	if packager, ok := p.(basePackager); ok {
+		for _, pkg := range packager.basePackages() {
+			commands = append(commands, fmt.Sprintf("install package %s", pkg))
+		}
+	}
+
+	if engineOptions.InstallVersion != "" {
+		commands = append(commands, fmt.Sprintf("install docker %s", engineOptions.InstallVersion))
+	} else if _, err := p.SSHCommand("type docker"); err != nil {
+		commands = append(commands, fmt.Sprintf("install docker from %s", engineOptions.InstallURL))
+	}
+
+	return commands
+}
+
+func plannedCertFiles(p Provisioner, authOptions auth.Options) ([]PlannedFile, error) {
+	caCert, err := ioutil.ReadFile(authOptions.CaCertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	currentCaCert, err := readRemoteFile(p, authOptions.CaCertRemotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return []PlannedFile{
+		{
+			Path:    authOptions.CaCertRemotePath,
+			Current: currentCaCert,
+			Desired: string(caCert),
+		},
+		{
+			Path:      authOptions.ServerCertRemotePath,
+			Generated: true,
+		},
+		{
+			Path:      authOptions.ServerKeyRemotePath,
+			Generated: true,
+		},
+	}, nil
+}
+
+func plannedDockerOptionsFiles(p Provisioner, dkrcfg *DockerOptions) ([]PlannedFile, error) {
+	current, err := readRemoteFile(p, dkrcfg.EngineOptionsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []PlannedFile{
+		{
+			Path:    dkrcfg.EngineOptionsPath,
+			Current: current,
+			Desired: dkrcfg.EngineOptions,
+		},
+	}
+
+	if dkrcfg.DaemonConfig == nil {
+		return files, nil
+	}
+
+	configPath := dkrcfg.DaemonConfigPath
+	if configPath == "" {
+		configPath = DefaultDaemonConfigPath
+	}
+
+	currentConfig, err := readRemoteFile(p, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := mergeDaemonConfig(currentConfig, dkrcfg.DaemonConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	desiredConfig, err := marshalDaemonConfig(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, PlannedFile{
+		Path:    configPath,
+		Current: currentConfig,
+		Desired: desiredConfig,
+	}), nil
+}
+
+// dockerRunCommand returns the docker run command line equivalent to
+// creating container.
+func dockerRunCommand(container swarmContainer) string {
+	config := container.Config
+	args := []string{"docker", "run", "-d", "--name", container.Name}
+
+	if policy := config.HostConfig.RestartPolicy.Name; policy != "" {
+		args = append(args, "--restart", policy)
+	}
+
+	ports := []string{}
+	for containerPort, bindings := range config.HostConfig.PortBindings {
+		for _, binding := range bindings {
+			ports = append(ports, fmt.Sprintf("%s:%s:%s", binding.HostIp, binding.HostPort, strings.TrimSuffix(containerPort, "/tcp")))
+		}
+	}
+	sort.Strings(ports)
+	for _, port := range ports {
+		args = append(args, "-p", port)
+	}
+
+	for _, bind := range config.HostConfig.Binds {
+		args = append(args, "-v", bind)
+	}
+
+	for _, env := range config.Env {
+		args = append(args, "-e", env)
+	}
+
+	args = append(args, config.Image)
+	args = append(args, config.Cmd...)
+
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,105 @@
+package provision
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/docker/machine/libmachine/swarm"
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanProvision(t *testing.T) {
+	caCert, err := ioutil.TempFile("", "machine-plan-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(caCert.Name())
+	caCert.WriteString("CA CERT\n")
+	caCert.Close()
+
+	p := NewDebianProvisioner(&fakedriver.Driver{
+		MockName:  "default",
+		MockIP:    "1.2.3.4",
+		MockState: state.Running,
+	}).(*DebianProvisioner)
+	sshCmder := &recordingSSHCommander{
+		responses: map[string]string{
+			"docker --version": "Docker version 18.09.1, build 4c52b90",
+			"if [ -f /etc/docker/ca.pem ]; then sudo cat /etc/docker/ca.pem; fi":                                                                     "CA CERT\n",
+			"if [ -f /etc/systemd/system/docker.service.d/10-machine.conf ]; then sudo cat /etc/systemd/system/docker.service.d/10-machine.conf; fi": "[Service]\nExecStart=\n",
+		},
+	}
+	p.SSHCommander = sshCmder
+
+	plan, err := PlanProvision(p, swarm.Options{}, auth.Options{
+		CaCertPath:     caCert.Name(),
+		ServerCertPath: "/machine/server.pem",
+		ServerCertSANs: []string{"docker.example.com"},
+	}, engine.Options{
+		StorageDriver: "overlay2",
+		InstallURL:    "https://get.docker.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "debian", plan.Provisioner)
+	assert.Equal(t, []string{"docker.example.com", "1.2.3.4", "localhost"}, plan.CertSANs)
+	assert.Equal(t, []string{
+		"set hostname to default",
+		"install package curl",
+		"generate server certificate /machine/server.pem",
+		"stop service docker",
+		"start service docker",
+	}, plan.Commands)
+
+	assert.Len(t, plan.Files, 4)
+	assert.Equal(t, "/etc/docker/ca.pem", plan.Files[0].Path)
+	assert.False(t, plan.Files[0].Changed())
+	assert.True(t, plan.Files[1].Generated)
+	assert.Equal(t, "/etc/systemd/system/docker.service.d/10-machine.conf", plan.Files[3].Path)
+	assert.True(t, plan.Files[3].Changed())
+	assert.Contains(t, plan.Files[3].Desired, "--storage-driver overlay2")
+
+	for _, cmd := range sshCmder.commands {
+		assert.NotContains(t, cmd, "tee")
+		assert.NotContains(t, cmd, "systemctl")
+	}
+}
+
+func TestPlannedFileDiff(t *testing.T) {
+	f := PlannedFile{
+		Path:    "/etc/default/docker",
+		Current: "DOCKER_OPTS='--label a'\n",
+		Desired: "DOCKER_OPTS='--label b'\n",
+	}
+
+	diff, err := f.Diff()
+
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "-DOCKER_OPTS='--label a'")
+	assert.Contains(t, diff, "+DOCKER_OPTS='--label b'")
+}
+
+func TestDockerRunCommand(t *testing.T) {
+	cmd := dockerRunCommand(swarmContainer{
+		Name: "swarm-agent-master",
+		Config: &dockerclient.ContainerConfig{
+			Image: "swarm:latest",
+			Cmd:   []string{"manage", "token://abc"},
+			HostConfig: dockerclient.HostConfig{
+				RestartPolicy: dockerclient.RestartPolicy{Name: "always"},
+				Binds:         []string{"/etc/docker:/etc/docker"},
+				PortBindings: map[string][]dockerclient.PortBinding{
+					"3376/tcp": {{HostIp: "0.0.0.0", HostPort: "3376"}},
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, "docker run -d --name swarm-agent-master --restart always -p 0.0.0.0:3376:3376 -v /etc/docker:/etc/docker swarm:latest manage token://abc", cmd)
+}
@@ -2,6 +2,8 @@ package provision
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/docker/machine/libmachine/auth"
 	"github.com/docker/machine/libmachine/drivers"
@@ -53,6 +55,17 @@ type Provisioner interface {
 	// Get the swarm options associated with this host.
 	GetSwarmOptions() swarm.Options
 
+	// Get the engine options associated with this host.
+	GetEngineOptions() engine.Options
+
+	// Set the engine options used by package actions outside of a full
+	// provisioning run, e.g. to pin the version for an upgrade.
+	SetEngineOptions(engineOptions engine.Options)
+
+	// Set the auth options used to render the daemon configuration outside
+	// of a full provisioning run, e.g. for a dry run.
+	SetAuthOptions(authOptions auth.Options)
+
 	// Run a package action e.g. install
 	Package(name string, action pkgaction.PackageAction) error
 
@@ -90,24 +103,110 @@ type Provisioner interface {
 // RegisteredProvisioner creates a new provisioner
 type RegisteredProvisioner struct {
 	New func(d drivers.Driver) Provisioner
+
+	// Priority orders detection when several provisioners are compatible
+	// with a host: higher priorities are tried first, then by name.
+	Priority int
+}
+
+// Priorities of the built-in provisioners. Those made for a single OS image
+// are tried first, then those for a distribution, then those also used for
+// the distributions based on theirs.
+const (
+	PriorityFamily       = 0
+	PriorityDistribution = 10
+	PrioritySpecific     = 20
+)
+
+// ProvisionerMatch describes whether a registered provisioner is compatible
+// with a host.
+type ProvisionerMatch struct {
+	Name       string
+	Priority   int
+	Compatible bool
+
+	// IDLike is the ID_LIKE entry through which the provisioner matched, if
+	// it didn't match the ID of the host itself.
+	IDLike string
 }
 
 func Register(name string, p *RegisteredProvisioner) {
 	provisioners[name] = p
 }
 
+// RegisteredNames returns the names of the registered provisioners in the
+// order they are tried during detection.
+func RegisteredNames() []string {
+	names := []string{}
+	for name := range provisioners {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := provisioners[names[i]].Priority, provisioners[names[j]].Priority
+		if pi != pj {
+			return pi > pj
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+func registeredName(name string) (string, bool) {
+	for registered := range provisioners {
+		if strings.EqualFold(registered, name) {
+			return registered, true
+		}
+	}
+
+	return "", false
+}
+
+// IsRegistered reports whether a provisioner is registered under name,
+// ignoring case.
+func IsRegistered(name string) bool {
+	_, ok := registeredName(name)
+	return ok
+}
+
 func DetectProvisioner(d drivers.Driver) (Provisioner, error) {
 	return detector.DetectProvisioner(d)
 }
 
-func (detector StandardDetector) DetectProvisioner(d drivers.Driver) (Provisioner, error) {
+// NewProvisioner returns the provisioner registered under name for the host
+// behind d, bypassing detection.
+func NewProvisioner(name string, d drivers.Driver) (Provisioner, error) {
+	registered, ok := registeredName(name)
+	if !ok {
+		return nil, ErrUnknownProvisioner{
+			Name: name,
+		}
+	}
+
+	osReleaseInfo, err := OsReleaseFromDriver(d)
+	if err != nil {
+		return nil, err
+	}
+
+	provisioner := provisioners[registered].New(d)
+	provisioner.SetOsReleaseInfo(osReleaseInfo)
+
+	if !provisioner.CompatibleWithHost() {
+		log.Warnf("Using the %s provisioner, which doesn't recognize the host OS %q", registered, osReleaseInfo.ID)
+	}
+
+	return provisioner, nil
+}
+
+// OsReleaseFromDriver waits for SSH to be available on the host behind d and
+// reads its /etc/os-release.
+func OsReleaseFromDriver(d drivers.Driver) (*OsRelease, error) {
 	log.Info("Waiting for SSH to be available...")
 	if err := drivers.WaitForSSH(d); err != nil {
 		return nil, err
 	}
 
-	log.Info("Detecting the provisioner...")
-
 	osReleaseOut, err := drivers.RunSSHCommandFromDriver(d, "cat /etc/os-release")
 	if err != nil {
 		return nil, fmt.Errorf("Error getting SSH command: %s", err)
@@ -118,15 +217,90 @@ func (detector StandardDetector) DetectProvisioner(d drivers.Driver) (Provisione
 		return nil, fmt.Errorf("Error parsing /etc/os-release file: %s", err)
 	}
 
-	for _, p := range provisioners {
-		provisioner := p.New(d)
-		provisioner.SetOsReleaseInfo(osReleaseInfo)
+	return osReleaseInfo, nil
+}
+
+func (detector StandardDetector) DetectProvisioner(d drivers.Driver) (Provisioner, error) {
+	osReleaseInfo, err := OsReleaseFromDriver(d)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Detecting the provisioner...")
+
+	for _, match := range MatchProvisioners(d, osReleaseInfo) {
+		if !match.Compatible {
+			continue
+		}
 
-		if provisioner.CompatibleWithHost() {
-			log.Debugf("found compatible host: %s", osReleaseInfo.ID)
-			return provisioner, nil
+		info := *osReleaseInfo
+		if match.IDLike != "" {
+			log.Infof("No provisioner for %q, using %s for ID_LIKE %q", osReleaseInfo.ID, match.Name, match.IDLike)
+			info.ID = match.IDLike
+			info.VersionID = ""
 		}
+
+		provisioner := provisioners[match.Name].New(d)
+		provisioner.SetOsReleaseInfo(&info)
+
+		log.Debugf("found compatible host: %s", info.ID)
+		return provisioner, nil
 	}
 
 	return nil, ErrDetectionFailed
 }
+
+// MatchProvisioners checks every registered provisioner, in detection order,
+// against osReleaseInfo. Provisioners compatible with the ID of the host come
+// first, then the ones compatible with an ID_LIKE entry, most similar first.
+// The VERSION_ID of a derivative isn't a version of the distribution it's
+// like, so it's dropped when matching an ID_LIKE entry.
+func MatchProvisioners(d drivers.Driver, osReleaseInfo *OsRelease) []ProvisionerMatch {
+	names := RegisteredNames()
+
+	matches := []ProvisionerMatch{}
+	matched := map[string]bool{}
+
+	ids := append([]string{osReleaseInfo.ID}, strings.Fields(osReleaseInfo.IDLike)...)
+	for i, id := range ids {
+		info := *osReleaseInfo
+		info.ID = id
+		if i > 0 {
+			info.VersionID = ""
+		}
+
+		for _, name := range names {
+			if matched[name] {
+				continue
+			}
+
+			provisioner := provisioners[name].New(d)
+			provisioner.SetOsReleaseInfo(&info)
+
+			if provisioner.CompatibleWithHost() {
+				match := ProvisionerMatch{
+					Name:       name,
+					Priority:   provisioners[name].Priority,
+					Compatible: true,
+				}
+				if i > 0 {
+					match.IDLike = id
+				}
+
+				matches = append(matches, match)
+				matched[name] = true
+			}
+		}
+	}
+
+	for _, name := range names {
+		if !matched[name] {
+			matches = append(matches, ProvisionerMatch{
+				Name:     name,
+				Priority: provisioners[name].Priority,
+			})
+		}
+	}
+
+	return matches
+}
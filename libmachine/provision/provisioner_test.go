@@ -0,0 +1,119 @@
+package provision
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+var linuxMint = []byte(`NAME="Linux Mint"
+VERSION="19 (Tara)"
+ID=linuxmint
+ID_LIKE="ubuntu debian"
+PRETTY_NAME="Linux Mint 19"
+VERSION_ID="19"
+HOME_URL="https://www.linuxmint.com/"
+VERSION_CODENAME=tara
+UBUNTU_CODENAME=bionic
+`)
+
+func compatibleMatches(matches []ProvisionerMatch) []ProvisionerMatch {
+	compatible := []ProvisionerMatch{}
+	for _, match := range matches {
+		if match.Compatible {
+			compatible = append(compatible, match)
+		}
+	}
+	return compatible
+}
+
+func TestMatchProvisionersIDLike(t *testing.T) {
+	osReleaseInfo, err := NewOsRelease(linuxMint)
+	assert.NoError(t, err)
+
+	matches := MatchProvisioners(&fakedriver.Driver{}, osReleaseInfo)
+
+	// the Ubuntu provisioners pick the init system from VERSION_ID, which
+	// isn't an Ubuntu version on Linux Mint
+	assert.Len(t, matches, len(provisioners))
+	assert.Equal(t, []ProvisionerMatch{
+		{Name: "Debian", Compatible: true, IDLike: "debian"},
+	}, compatibleMatches(matches))
+}
+
+func TestMatchProvisionersIDFirst(t *testing.T) {
+	osReleaseInfo := &OsRelease{
+		ID:        "ubuntu",
+		IDLike:    "debian",
+		VersionID: "16.04",
+	}
+
+	matches := compatibleMatches(MatchProvisioners(&fakedriver.Driver{}, osReleaseInfo))
+
+	assert.Equal(t, []ProvisionerMatch{
+		{Name: "Ubuntu-SystemD", Priority: PriorityDistribution, Compatible: true},
+		{Name: "Debian", Compatible: true, IDLike: "debian"},
+	}, matches)
+}
+
+func TestMatchProvisionersNone(t *testing.T) {
+	osReleaseInfo := &OsRelease{
+		ID: "gentoo",
+	}
+
+	matches := MatchProvisioners(&fakedriver.Driver{}, osReleaseInfo)
+
+	assert.Empty(t, compatibleMatches(matches))
+	assert.Equal(t, RegisteredNames()[0], matches[0].Name)
+}
+
+func TestRegisteredNamesOrder(t *testing.T) {
+	Register("zz-test", &RegisteredProvisioner{
+		New:      func(d drivers.Driver) Provisioner { return NewFakeProvisioner(d) },
+		Priority: PrioritySpecific + 1,
+	})
+	defer delete(provisioners, "zz-test")
+
+	names := RegisteredNames()
+
+	assert.Equal(t, "zz-test", names[0])
+	assert.Equal(t, []string{"AmazonLinux", "CoreOS", "Flatcar", "RancherOS", "boot2docker"}, names[1:6])
+	assert.Equal(t, []string{"Debian", "RedHat"}, names[len(names)-2:])
+	assert.True(t, sort.StringsAreSorted(names[6:len(names)-2]))
+	assert.Equal(t, len(provisioners), len(names))
+}
+
+func TestIsRegistered(t *testing.T) {
+	assert.True(t, IsRegistered("Debian"))
+	assert.True(t, IsRegistered("ubuntu-systemd"))
+	assert.False(t, IsRegistered("gentoo"))
+}
+
+func TestNewProvisionerUnknown(t *testing.T) {
+	_, err := NewProvisioner("gentoo", &fakedriver.Driver{})
+
+	assert.Equal(t, ErrUnknownProvisioner{Name: "gentoo"}, err)
+	assert.Contains(t, err.Error(), "Ubuntu-SystemD")
+}
+
+func TestMatchProvisionersNewDistributions(t *testing.T) {
+	cases := []struct {
+		osRelease OsRelease
+		expected  string
+	}{
+		{OsRelease{ID: "alpine", VersionID: "3.8.1"}, "Alpine"},
+		{OsRelease{ID: "amzn", IDLike: "centos rhel fedora", VersionID: "2"}, "AmazonLinux"},
+		{OsRelease{ID: "flatcar", IDLike: "coreos", VersionID: "2079.3.0"}, "Flatcar"},
+	}
+
+	for _, c := range cases {
+		matches := compatibleMatches(MatchProvisioners(&fakedriver.Driver{}, &c.osRelease))
+
+		assert.NotEmpty(t, matches)
+		assert.Equal(t, c.expected, matches[0].Name)
+		assert.Empty(t, matches[0].IDLike)
+	}
+}
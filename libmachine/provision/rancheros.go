@@ -32,7 +32,8 @@ EOF
 
 func init() {
 	Register("RancherOS", &RegisteredProvisioner{
-		New: NewRancherProvisioner,
+		New:      NewRancherProvisioner,
+		Priority: PrioritySpecific,
 	})
 }
 
@@ -70,6 +71,9 @@ func (provisioner *RancherProvisioner) Package(name string, action pkgaction.Pac
 	var packageAction string
 
 	if name == "docker" && action == pkgaction.Upgrade {
+		if version := provisioner.EngineOptions.InstallVersion; version != "" {
+			return switchDockerVersion(provisioner, version)
+		}
 		return provisioner.upgrade()
 	}
 
@@ -118,7 +122,12 @@ func (provisioner *RancherProvisioner) Provision(swarmOptions swarm.Options, aut
 		}
 	}
 
-	if engineOptions.InstallURL == drivers.DefaultEngineInstallURL {
+	if engineOptions.InstallVersion != "" {
+		log.Debugf("Switching docker engine to version %s", engineOptions.InstallVersion)
+		if err := switchDockerVersion(provisioner, engineOptions.InstallVersion); err != nil {
+			return err
+		}
+	} else if engineOptions.InstallURL == drivers.DefaultEngineInstallURL {
 		log.Debugf("Skipping docker engine default: %s", engineOptions.InstallURL)
 	} else {
 		log.Debugf("Selecting docker engine: %s", engineOptions.InstallURL)
@@ -248,3 +257,11 @@ func selectDocker(p Provisioner, baseURL string) error {
 
 	return nil
 }
+
+func switchDockerVersion(p Provisioner, version string) error {
+	if output, err := p.SSHCommand(fmt.Sprintf("sudo ros engine switch %s", mcnutils.ShellQuote("docker-"+version))); err != nil {
+		return fmt.Errorf("error switching docker engine: (%s) %s", err, output)
+	}
+
+	return nil
+}
@@ -21,7 +21,7 @@ var (
 	ErrUnknownYumOsRelease = errors.New("unknown OS for Yum repository")
 	engineConfigTemplate   = `[Service]
 ExecStart=
-ExecStart=/usr/bin/dockerd -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}
+ExecStart=/usr/bin/dockerd{{ if not .DaemonJSON }} -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}{{ end }}
 Environment={{range .EngineOptions.Env}}{{ printf "%q" . }} {{end}}
 `
 	majorVersionRE = regexp.MustCompile(`^(\d+)(\..*)?`)
@@ -37,6 +37,7 @@ func init() {
 		New: func(d drivers.Driver) Provisioner {
 			return NewRedHatProvisioner("rhel", d)
 		},
+		Priority: PriorityFamily,
 	})
 }
 
@@ -94,6 +95,16 @@ func (provisioner *RedHatProvisioner) Package(name string, action pkgaction.Pack
 
 	command := fmt.Sprintf("sudo -E yum %s -y %s", packageAction, name)
 
+	if version := provisioner.EngineOptions.InstallVersion; name == "docker" && version != "" {
+		switch action {
+		case pkgaction.Install, pkgaction.Upgrade:
+			// yum won't install a version older than the one present, so
+			// try a downgrade first and fall back to a regular install
+			pkg := mcnutils.ShellQuote("docker-ce-" + version)
+			command = fmt.Sprintf("sudo -E yum downgrade -y %s || sudo -E yum install -y %s", pkg, pkg)
+		}
+	}
+
 	if _, err := provisioner.SSHCommand(command); err != nil {
 		return err
 	}
@@ -102,7 +113,7 @@ func (provisioner *RedHatProvisioner) Package(name string, action pkgaction.Pack
 }
 
 func installDocker(provisioner *RedHatProvisioner) error {
-	if err := installDockerGeneric(provisioner, provisioner.EngineOptions.InstallURL); err != nil {
+	if err := installDockerGeneric(provisioner, provisioner.EngineOptions); err != nil {
 		return err
 	}
 
@@ -144,16 +155,15 @@ func (provisioner *RedHatProvisioner) Provision(swarmOptions swarm.Options, auth
 		return err
 	}
 
-	for _, pkg := range provisioner.Packages {
-		log.Debugf("installing base package: name=%s", pkg)
-		if err := provisioner.Package(pkg, pkgaction.Install); err != nil {
-			return err
-		}
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
 	}
 
 	// update OS -- this is needed for libdevicemapper and the docker install
-	if _, err := provisioner.SSHCommand("sudo -E yum -y update -x docker-*"); err != nil {
-		return err
+	if engineOptions.InstallBundle == "" {
+		if _, err := provisioner.SSHCommand("sudo -E yum -y update -x docker-*"); err != nil {
+			return err
+		}
 	}
 
 	// install docker
@@ -205,8 +215,8 @@ func (provisioner *RedHatProvisioner) GenerateDockerOptions(dockerPort int) (*Do
 	t.Execute(&engineCfg, engineConfigContext)
 
 	daemonOptsDir := configPath
-	return &DockerOptions{
+	return withDaemonConfig(&DockerOptions{
 		EngineOptions:     engineCfg.String(),
 		EngineOptionsPath: daemonOptsDir,
-	}, nil
+	}, engineConfigContext, true)
 }
@@ -0,0 +1,140 @@
+package provision
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/template"
+
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/provision/pkgaction"
+	"github.com/docker/machine/libmachine/provision/serviceaction"
+)
+
+const (
+	rootlessUnitDir   = "~/.config/systemd/user/docker.service.d"
+	rootlessUnitFile  = rootlessUnitDir + "/10-machine.conf"
+	rootlessUserSetup = "export XDG_RUNTIME_DIR=/run/user/$(id -u) && "
+
+	// the rootlesskit port driver publishes the TLS port of the engine,
+	// which listens inside the namespace of rootlesskit
+	rootlessUnitTemplate = `[Service]
+Environment=DOCKERD_ROOTLESS_ROOTLESSKIT_FLAGS="-p 0.0.0.0:{{.DockerPort}}:{{.DockerPort}}/tcp"
+{{ range .EngineOptions.Env }}Environment={{ printf "%q" . }}
+{{ end }}ExecStart=
+ExecStart=/usr/bin/dockerd-rootless.sh -H tcp://0.0.0.0:{{.DockerPort}} -H unix://%t/docker.sock --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}
+`
+)
+
+var (
+	ErrRootlessRootUser = errors.New("The rootless engine runs as the SSH user, which can't be root")
+	ErrRootlessSystemd  = errors.New("The rootless engine needs a host managed by systemd")
+)
+
+// enginePort returns the port the engine of the host behind p listens on.
+func enginePort(p Provisioner) (int, error) {
+	if p.GetEngineOptions().Rootless() {
+		return engine.DefaultRootlessPort, nil
+	}
+
+	return driverDockerPort(p.GetDriver())
+}
+
+// engineURL returns the URL of the engine of the host behind p.
+func engineURL(p Provisioner) (string, error) {
+	driverURL, err := p.GetDriver().GetURL()
+	if err != nil {
+		return "", err
+	}
+
+	return p.GetEngineOptions().URL(driverURL)
+}
+
+// renderRootlessUnit returns the systemd drop-in which configures the user
+// unit of the rootless engine. The storage driver isn't set, the one picked
+// for a system engine is usually not available to an unprivileged one.
+func renderRootlessUnit(driver drivers.Driver, dockerPort int, authOptions auth.Options, engineOptions engine.Options) (string, error) {
+	var unit bytes.Buffer
+
+	engineOptions.Labels = append([]string{fmt.Sprintf("provider=%s", driver.DriverName())}, engineOptions.Labels...)
+
+	t, err := template.New("rootlessUnit").Parse(rootlessUnitTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.Execute(&unit, EngineConfigContext{
+		DockerPort:    dockerPort,
+		AuthOptions:   authOptions,
+		EngineOptions: engineOptions,
+	}); err != nil {
+		return "", err
+	}
+
+	return unit.String(), nil
+}
+
+// configureRootless replaces the system engine of the host behind p with a
+// rootless engine run by the systemd user instance of the SSH user.
+func configureRootless(p Provisioner, dockerPort int, authOptions auth.Options) error {
+	user := p.GetDriver().GetSSHUsername()
+	if user == "" || user == "root" {
+		return ErrRootlessRootUser
+	}
+
+	if _, err := p.SSHCommand("type systemctl"); err != nil {
+		return ErrRootlessSystemd
+	}
+
+	log.Infof("Setting up the rootless engine for %s...", user)
+
+	if _, err := p.SSHCommand("type dockerd-rootless-setuptool.sh"); err != nil {
+		if err := p.Package("docker-ce-rootless-extras", pkgaction.Install); err != nil {
+			return err
+		}
+	}
+
+	unit, err := renderRootlessUnit(p.GetDriver(), dockerPort, authOptions, p.GetEngineOptions())
+	if err != nil {
+		return err
+	}
+
+	commands := []string{
+		"sudo systemctl disable --now docker.service docker.socket",
+		fmt.Sprintf("grep -q '^%[1]s:' /etc/subuid || echo '%[1]s:100000:65536' | sudo tee -a /etc/subuid", user),
+		fmt.Sprintf("grep -q '^%[1]s:' /etc/subgid || echo '%[1]s:100000:65536' | sudo tee -a /etc/subgid", user),
+		fmt.Sprintf("sudo loginctl enable-linger %s", user),
+		fmt.Sprintf("sudo chown %s %s %s %s", user, authOptions.CaCertRemotePath, authOptions.ServerCertRemotePath, authOptions.ServerKeyRemotePath),
+		rootlessUserSetup + "dockerd-rootless-setuptool.sh install --force",
+		fmt.Sprintf("mkdir -p %s && printf '%%s' %s > %s", rootlessUnitDir, mcnutils.ShellQuote(unit), rootlessUnitFile),
+		rootlessUserSetup + "systemctl --user daemon-reload && systemctl --user enable docker && systemctl --user restart docker",
+	}
+
+	for _, command := range commands {
+		if _, err := p.SSHCommand(command); err != nil {
+			return err
+		}
+	}
+
+	return WaitForDocker(p, dockerPort)
+}
+
+// rootlessServiceCommand returns the command applying the action to the user
+// unit of the rootless engine. Stopping an engine which isn't set up yet
+// isn't an error.
+func rootlessServiceCommand(action serviceaction.ServiceAction) string {
+	command := fmt.Sprintf("systemctl --user %s docker", action.String())
+
+	switch action {
+	case serviceaction.Start, serviceaction.Restart:
+		command = "systemctl --user daemon-reload && " + command
+	case serviceaction.Stop:
+		command += " || true"
+	}
+
+	return rootlessUserSetup + command
+}
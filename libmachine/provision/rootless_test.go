@@ -0,0 +1,119 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/auth"
+	"github.com/docker/machine/libmachine/engine"
+	"github.com/docker/machine/libmachine/provision/serviceaction"
+	"github.com/stretchr/testify/assert"
+)
+
+var rootlessAuthOptions = auth.Options{
+	CaCertRemotePath:     "/etc/docker/ca.pem",
+	ServerCertRemotePath: "/etc/docker/server.pem",
+	ServerKeyRemotePath:  "/etc/docker/server-key.pem",
+}
+
+func TestRenderRootlessUnit(t *testing.T) {
+	unit, err := renderRootlessUnit(&fakedriver.Driver{}, 2378, rootlessAuthOptions, engine.Options{
+		Labels:        []string{"env=dev"},
+		Env:           []string{"HTTP_PROXY=http://proxy:3128"},
+		StorageDriver: "aufs",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `[Service]
+Environment=DOCKERD_ROOTLESS_ROOTLESSKIT_FLAGS="-p 0.0.0.0:2378:2378/tcp"
+Environment="HTTP_PROXY=http://proxy:3128"
+ExecStart=
+ExecStart=/usr/bin/dockerd-rootless.sh -H tcp://0.0.0.0:2378 -H unix://%t/docker.sock --tlsverify --tlscacert /etc/docker/ca.pem --tlscert /etc/docker/server.pem --tlskey /etc/docker/server-key.pem --label provider=Driver --label env=dev 
+`, unit)
+}
+
+type sshUserDriver struct {
+	*fakedriver.Driver
+	user string
+}
+
+func (d *sshUserDriver) GetSSHUsername() string {
+	return d.user
+}
+
+func TestConfigureRootlessRootUser(t *testing.T) {
+	p := NewDebianProvisioner(&sshUserDriver{&fakedriver.Driver{}, "root"}).(*DebianProvisioner)
+	p.SSHCommander = &recordingSSHCommander{}
+
+	assert.Equal(t, ErrRootlessRootUser, configureRootless(p, 2378, rootlessAuthOptions))
+}
+
+func TestConfigureRootless(t *testing.T) {
+	p := NewDebianProvisioner(&sshUserDriver{&fakedriver.Driver{}, "dev"}).(*DebianProvisioner)
+	sshCmder := &recordingSSHCommander{
+		responses: map[string]string{
+			"if ! type netstat 1>/dev/null; then ss -tln; else netstat -tln; fi": "tcp 0 0 0.0.0.0:2378 0.0.0.0:* LISTEN",
+		},
+	}
+	p.SSHCommander = sshCmder
+	p.EngineOptions = engine.Options{Mode: engine.ModeRootless}
+
+	err := configureRootless(p, 2378, rootlessAuthOptions)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"type systemctl",
+		"type dockerd-rootless-setuptool.sh",
+		"sudo systemctl disable --now docker.service docker.socket",
+		"grep -q '^dev:' /etc/subuid || echo 'dev:100000:65536' | sudo tee -a /etc/subuid",
+		"grep -q '^dev:' /etc/subgid || echo 'dev:100000:65536' | sudo tee -a /etc/subgid",
+		"sudo loginctl enable-linger dev",
+		"sudo chown dev /etc/docker/ca.pem /etc/docker/server.pem /etc/docker/server-key.pem",
+		"export XDG_RUNTIME_DIR=/run/user/$(id -u) && dockerd-rootless-setuptool.sh install --force",
+	}, sshCmder.commands[:8])
+	assert.Contains(t, sshCmder.commands[8], "mkdir -p ~/.config/systemd/user/docker.service.d && printf '%s' '[Service]")
+	assert.Equal(t, "export XDG_RUNTIME_DIR=/run/user/$(id -u) && systemctl --user daemon-reload && systemctl --user enable docker && systemctl --user restart docker", sshCmder.commands[9])
+}
+
+func TestConfigureRootlessQuotesUnit(t *testing.T) {
+	p := NewDebianProvisioner(&sshUserDriver{&fakedriver.Driver{}, "dev"}).(*DebianProvisioner)
+	sshCmder := &recordingSSHCommander{
+		responses: map[string]string{
+			"if ! type netstat 1>/dev/null; then ss -tln; else netstat -tln; fi": "tcp 0 0 0.0.0.0:2378 0.0.0.0:* LISTEN",
+		},
+	}
+	p.SSHCommander = sshCmder
+	p.EngineOptions = engine.Options{Mode: engine.ModeRootless, Env: []string{"GREETING=it's me"}}
+
+	err := configureRootless(p, 2378, rootlessAuthOptions)
+
+	assert.NoError(t, err)
+	assert.Contains(t, sshCmder.commands[8], `Environment="GREETING=it'\''s me"`)
+}
+
+func TestServiceRootless(t *testing.T) {
+	p := NewDebianProvisioner(&fakedriver.Driver{}).(*DebianProvisioner)
+	sshCmder := &recordingSSHCommander{}
+	p.SSHCommander = sshCmder
+	p.EngineOptions = engine.Options{Mode: engine.ModeRootless}
+
+	assert.NoError(t, p.Service("docker", serviceaction.Enable))
+	assert.NoError(t, p.Service("docker", serviceaction.Restart))
+	assert.NoError(t, p.Service("docker", serviceaction.Stop))
+
+	assert.Equal(t, []string{
+		"export XDG_RUNTIME_DIR=/run/user/$(id -u) && systemctl --user enable docker",
+		"export XDG_RUNTIME_DIR=/run/user/$(id -u) && systemctl --user daemon-reload && systemctl --user restart docker",
+		"export XDG_RUNTIME_DIR=/run/user/$(id -u) && systemctl --user stop docker || true",
+	}, sshCmder.commands)
+}
+
+func TestEnginePort(t *testing.T) {
+	p := NewDebianProvisioner(&fakedriver.Driver{}).(*DebianProvisioner)
+
+	p.EngineOptions = engine.Options{Mode: engine.ModeRootless}
+	port, err := enginePort(p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, engine.DefaultRootlessPort, port)
+}
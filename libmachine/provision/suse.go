@@ -16,13 +16,16 @@ import (
 
 func init() {
 	Register("openSUSE", &RegisteredProvisioner{
-		New: NewOpenSUSEProvisioner,
+		New:      NewOpenSUSEProvisioner,
+		Priority: PriorityDistribution,
 	})
 	Register("SUSE Linux Enterprise Desktop", &RegisteredProvisioner{
-		New: NewSLEDProvisioner,
+		New:      NewSLEDProvisioner,
+		Priority: PriorityDistribution,
 	})
 	Register("SUSE Linux Enterprise Server", &RegisteredProvisioner{
-		New: NewSLESProvisioner,
+		New:      NewSLESProvisioner,
+		Priority: PriorityDistribution,
 	})
 }
 
@@ -59,6 +62,11 @@ func (provisioner *SUSEProvisioner) String() string {
 func (provisioner *SUSEProvisioner) Package(name string, action pkgaction.PackageAction) error {
 	var packageAction string
 
+	version := provisioner.EngineOptions.InstallVersion
+	if name != "docker" {
+		version = ""
+	}
+
 	switch action {
 	case pkgaction.Install:
 		packageAction = "in"
@@ -70,8 +78,12 @@ func (provisioner *SUSEProvisioner) Package(name string, action pkgaction.Packag
 		// Refreshing the repository metadata can take quite some time and can cause
 		// longer provisioning times for machines that have been pre-optimized for
 		// docker by including all the needed packages.
-		if _, err := provisioner.SSHCommand(fmt.Sprintf("rpm -q %s", name)); err == nil {
-			log.Debugf("%s is already installed, skipping operation", name)
+		installed := name
+		if version != "" {
+			installed = fmt.Sprintf("%s-%s", name, version)
+		}
+		if _, err := provisioner.SSHCommand(fmt.Sprintf("rpm -q %s", mcnutils.ShellQuote(installed))); err == nil {
+			log.Debugf("%s is already installed, skipping operation", installed)
 			return nil
 		}
 	case pkgaction.Remove:
@@ -80,6 +92,12 @@ func (provisioner *SUSEProvisioner) Package(name string, action pkgaction.Packag
 		packageAction = "up"
 	}
 
+	if version != "" && (action == pkgaction.Install || action == pkgaction.Upgrade) {
+		// --oldpackage lets zypper move to an older version as well
+		packageAction = "in --oldpackage"
+		name = mcnutils.ShellQuote(name + "=" + version)
+	}
+
 	command := fmt.Sprintf("sudo -E zypper -n %s %s", packageAction, name)
 
 	log.Debugf("zypper: action=%s name=%s", action.String(), name)
@@ -146,14 +164,16 @@ func (provisioner *SUSEProvisioner) Provision(swarmOptions swarm.Options, authOp
 	}
 
 	log.Debug("Installing base packages")
-	for _, pkg := range provisioner.Packages {
-		if err := provisioner.Package(pkg, pkgaction.Install); err != nil {
-			return err
-		}
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
 	}
 
 	log.Debug("Installing docker")
-	if err := provisioner.Package("docker", pkgaction.Install); err != nil {
+	if engineOptions.InstallBundle != "" {
+		if err := installDockerFromBundle(provisioner, engineOptions); err != nil {
+			return err
+		}
+	} else if err := provisioner.Package("docker", pkgaction.Install); err != nil {
 		return err
 	}
 
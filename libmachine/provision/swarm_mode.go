@@ -0,0 +1,50 @@
+package provision
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/swarm"
+)
+
+// configureSwarmMode initializes or joins the swarm mode cluster described
+// by swarmOptions. Nodes which are already part of a cluster are left alone.
+func configureSwarmMode(p Provisioner, swarmOptions swarm.Options) error {
+	if swarmOptions.Mode == "" {
+		return nil
+	}
+
+	nodeState, err := p.SSHCommand("sudo docker info --format '{{.Swarm.LocalNodeState}}'")
+	if err != nil {
+		return fmt.Errorf("Error reading the swarm mode state: %s", err)
+	}
+
+	if strings.TrimSpace(nodeState) == "active" {
+		log.Infof("Already part of swarm mode cluster %s", swarmOptions.ModeCluster)
+		return nil
+	}
+
+	ip, err := p.GetDriver().GetIP()
+	if err != nil {
+		return err
+	}
+
+	if swarmOptions.ModeJoinAddr == "" {
+		if swarmOptions.Mode != swarm.ModeManager {
+			return fmt.Errorf("A swarm mode %s needs a manager to join", swarmOptions.Mode)
+		}
+
+		log.Infof("Initializing swarm mode cluster %s...", swarmOptions.ModeCluster)
+		_, err := p.SSHCommand(fmt.Sprintf("sudo docker swarm init --advertise-addr %s", ip))
+		return err
+	}
+
+	if swarmOptions.ModeJoinToken == "" {
+		return fmt.Errorf("No join token to join swarm mode cluster %s", swarmOptions.ModeCluster)
+	}
+
+	log.Infof("Joining swarm mode cluster %s as a %s...", swarmOptions.ModeCluster, swarmOptions.Mode)
+	_, err = p.SSHCommand(fmt.Sprintf("sudo docker swarm join --token %s --advertise-addr %s %s", swarmOptions.ModeJoinToken, ip, swarmOptions.ModeJoinAddr))
+	return err
+}
@@ -0,0 +1,70 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/docker/machine/libmachine/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSwarmModeTestProvisioner(sshCmder *recordingSSHCommander) Provisioner {
+	p := NewDebianProvisioner(&fakedriver.Driver{
+		MockIP:    "10.0.0.2",
+		MockState: state.Running,
+	}).(*DebianProvisioner)
+	p.SSHCommander = sshCmder
+	return p
+}
+
+func TestConfigureSwarmModeInit(t *testing.T) {
+	sshCmder := &recordingSSHCommander{}
+
+	err := configureSwarmMode(newSwarmModeTestProvisioner(sshCmder), swarm.Options{
+		Mode:        swarm.ModeManager,
+		ModeCluster: "prod",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sudo docker swarm init --advertise-addr 10.0.0.2", sshCmder.commands[1])
+}
+
+func TestConfigureSwarmModeJoin(t *testing.T) {
+	sshCmder := &recordingSSHCommander{}
+
+	err := configureSwarmMode(newSwarmModeTestProvisioner(sshCmder), swarm.Options{
+		Mode:          swarm.ModeWorker,
+		ModeCluster:   "prod",
+		ModeJoinAddr:  "10.0.0.1:2377",
+		ModeJoinToken: "SWMTKN-1-abc",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sudo docker swarm join --token SWMTKN-1-abc --advertise-addr 10.0.0.2 10.0.0.1:2377", sshCmder.commands[1])
+}
+
+func TestConfigureSwarmModeAlreadyActive(t *testing.T) {
+	sshCmder := &recordingSSHCommander{
+		responses: map[string]string{
+			"sudo docker info --format '{{.Swarm.LocalNodeState}}'": "active\n",
+		},
+	}
+
+	err := configureSwarmMode(newSwarmModeTestProvisioner(sshCmder), swarm.Options{
+		Mode:        swarm.ModeWorker,
+		ModeCluster: "prod",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, sshCmder.commands, 1)
+}
+
+func TestConfigureSwarmModeWorkerWithoutManager(t *testing.T) {
+	err := configureSwarmMode(newSwarmModeTestProvisioner(&recordingSSHCommander{}), swarm.Options{
+		Mode:        swarm.ModeWorker,
+		ModeCluster: "prod",
+	})
+
+	assert.Error(t, err)
+}
@@ -53,7 +53,7 @@ func (p *SystemdProvisioner) GenerateDockerOptions(dockerPort int) (*DockerOptio
 
 	engineConfigTmpl := `[Service]
 ExecStart=
-ExecStart=/usr/bin/` + arg + ` -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}
+ExecStart=/usr/bin/` + arg + `{{ if not .DaemonJSON }} -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}{{ end }}
 Environment={{range .EngineOptions.Env}}{{ printf "%q" . }} {{end}}
 `
 	t, err := template.New("engineConfig").Parse(engineConfigTmpl)
@@ -69,13 +69,19 @@ Environment={{range .EngineOptions.Env}}{{ printf "%q" . }} {{end}}
 
 	t.Execute(&engineCfg, engineConfigContext)
 
-	return &DockerOptions{
+	return withDaemonConfig(&DockerOptions{
 		EngineOptions:     engineCfg.String(),
 		EngineOptionsPath: p.DaemonOptionsFile,
-	}, nil
+	}, engineConfigContext, true)
 }
 
 func (p *SystemdProvisioner) Service(name string, action serviceaction.ServiceAction) error {
+	// the system engine stays off when a rootless one replaces it
+	if name == "docker" && p.EngineOptions.Rootless() {
+		_, err := p.SSHCommand(rootlessServiceCommand(action))
+		return err
+	}
+
 	reloadDaemon := false
 	switch action {
 	case serviceaction.Start, serviceaction.Restart:
@@ -16,7 +16,8 @@ import (
 
 func init() {
 	Register("Ubuntu-SystemD", &RegisteredProvisioner{
-		New: NewUbuntuSystemdProvisioner,
+		New:      NewUbuntuSystemdProvisioner,
+		Priority: PriorityDistribution,
 	})
 }
 
@@ -68,6 +69,9 @@ func (provisioner *UbuntuSystemdProvisioner) Package(name string, action pkgacti
 	switch name {
 	case "docker":
 		name = "docker-ce"
+		if packageAction == "install" {
+			name = aptDockerPackage(name, provisioner.EngineOptions.InstallVersion)
+		}
 	}
 
 	if updateMetadata {
@@ -114,14 +118,12 @@ func (provisioner *UbuntuSystemdProvisioner) Provision(swarmOptions swarm.Option
 	}
 
 	log.Debug("installing base packages")
-	for _, pkg := range provisioner.Packages {
-		if err := provisioner.Package(pkg, pkgaction.Install); err != nil {
-			return err
-		}
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
 	}
 
 	log.Info("Installing Docker...")
-	if err := installDockerGeneric(provisioner, engineOptions.InstallURL); err != nil {
+	if err := installDockerGeneric(provisioner, engineOptions); err != nil {
 		return err
 	}
 
@@ -16,7 +16,8 @@ import (
 
 func init() {
 	Register("Ubuntu-UpStart", &RegisteredProvisioner{
-		New: NewUbuntuProvisioner,
+		New:      NewUbuntuProvisioner,
+		Priority: PriorityDistribution,
 	})
 }
 
@@ -84,6 +85,9 @@ func (provisioner *UbuntuProvisioner) Package(name string, action pkgaction.Pack
 	switch name {
 	case "docker":
 		name = "docker-engine"
+		if packageAction == "install" {
+			name = aptDockerPackage(name, provisioner.EngineOptions.InstallVersion)
+		}
 	}
 
 	if updateMetadata {
@@ -128,14 +132,12 @@ func (provisioner *UbuntuProvisioner) Provision(swarmOptions swarm.Options, auth
 		return err
 	}
 
-	for _, pkg := range provisioner.Packages {
-		if err := provisioner.Package(pkg, pkgaction.Install); err != nil {
-			return err
-		}
+	if err := installBasePackages(provisioner, provisioner.Packages, provisioner.EngineOptions); err != nil {
+		return err
 	}
 
 	log.Info("Installing Docker...")
-	if err := installDockerGeneric(provisioner, engineOptions.InstallURL); err != nil {
+	if err := installDockerGeneric(provisioner, engineOptions); err != nil {
 		return err
 	}
 
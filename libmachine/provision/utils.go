@@ -13,27 +13,97 @@ import (
 
 	"github.com/docker/machine/libmachine/auth"
 	"github.com/docker/machine/libmachine/cert"
+	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/engine"
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/mcnutils"
+	"github.com/docker/machine/libmachine/provision/pkgaction"
 	"github.com/docker/machine/libmachine/provision/serviceaction"
 )
 
 type DockerOptions struct {
 	EngineOptions     string
 	EngineOptionsPath string
+	// DaemonConfig holds the settings merged into the daemon.json at
+	// DaemonConfigPath when the engine is configured through daemon.json.
+	DaemonConfig     map[string]interface{}
+	DaemonConfigPath string
 }
 
-func installDockerGeneric(p Provisioner, baseURL string) error {
+// installBasePackages installs the packages a provisioner needs to fetch and
+// install the engine. Installs from a local engine bundle must work without
+// network access, so nothing is installed for them.
+func installBasePackages(p Provisioner, packages []string, engineOptions engine.Options) error {
+	if engineOptions.InstallBundle != "" {
+		log.Debug("installing from an engine bundle, skipping base packages")
+		return nil
+	}
+
+	for _, pkg := range packages {
+		log.Debugf("installing base package: name=%s", pkg)
+		if err := p.Package(pkg, pkgaction.Install); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func installDockerGeneric(p Provisioner, engineOptions engine.Options) error {
+	if engineOptions.InstallBundle != "" {
+		return installDockerFromBundle(p, engineOptions)
+	}
+
 	// install docker - until cloudinit we use ubuntu everywhere so we
 	// just install it using the docker repos
-	if output, err := p.SSHCommand(fmt.Sprintf("if ! type docker; then curl -sSL %s | sh -; fi", baseURL)); err != nil {
+	if output, err := p.SSHCommand(installScriptCommand(engineOptions)); err != nil {
 		return fmt.Errorf("error installing docker: %s", output)
 	}
 
 	return nil
 }
 
+// installScriptCommand returns the command running the install script when
+// docker is missing, or when another version than the pinned one is
+// installed so that re-provisioning enforces the pin.
+func installScriptCommand(engineOptions engine.Options) string {
+	install := fmt.Sprintf("curl -sSL %s | %ssh -", engineOptions.InstallURL, installScriptEnv(engineOptions))
+
+	if engineOptions.InstallVersion == "" {
+		return fmt.Sprintf("if ! type docker; then %s; fi", install)
+	}
+
+	// docker --version prints "Docker version 18.09.1, build 4c52b90"
+	return fmt.Sprintf(`case "$(docker --version 2>/dev/null)" in "Docker version "%s[.,+-]*) ;; *) %s ;; esac`, mcnutils.ShellQuote(engineOptions.InstallVersion), install)
+}
+
+// installScriptEnv returns the environment assignments understood by the
+// get.docker.com install script to select a release channel and pin the
+// engine version.
+func installScriptEnv(engineOptions engine.Options) string {
+	env := ""
+	if engineOptions.InstallChannel != "" {
+		env += fmt.Sprintf("CHANNEL=%s ", mcnutils.ShellQuote(engineOptions.InstallChannel))
+	}
+	if engineOptions.InstallVersion != "" {
+		env += fmt.Sprintf("VERSION=%s ", mcnutils.ShellQuote(engineOptions.InstallVersion))
+	}
+
+	return env
+}
+
+// aptDockerPackage returns the apt-get package argument for the docker
+// package name, pinned to version if one is given. The full Debian version
+// string (e.g. "5:18.09.1~3-0~ubuntu-bionic") is resolved on the remote host.
+func aptDockerPackage(name, version string) string {
+	if version == "" {
+		return name
+	}
+
+	program := fmt.Sprintf(`$3 ~ /(^|:)%s[~-]/ { print $3; exit }`, regexp.QuoteMeta(version))
+	return fmt.Sprintf(`--allow-downgrades %s=$(apt-cache madison %s | awk %s)`, name, name, mcnutils.ShellQuote(program))
+}
+
 func makeDockerOptionsDir(p Provisioner) error {
 	dockerDir := p.GetDockerOptionsDir()
 	if _, err := p.SSHCommand(fmt.Sprintf("sudo mkdir -p %s", dockerDir)); err != nil {
@@ -156,22 +226,13 @@ func ConfigureAuth(p Provisioner) error {
 		return err
 	}
 
-	dockerURL, err := driver.GetURL()
+	dockerPort, err := enginePort(p)
 	if err != nil {
 		return err
 	}
-	u, err := url.Parse(dockerURL)
-	if err != nil {
-		return err
-	}
-	dockerPort := engine.DefaultPort
-	parts := strings.Split(u.Host, ":")
-	if len(parts) == 2 {
-		dPort, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return err
-		}
-		dockerPort = dPort
+
+	if p.GetEngineOptions().Rootless() {
+		return configureRootless(p, dockerPort, authOptions)
 	}
 
 	dkrcfg, err := p.GenerateDockerOptions(dockerPort)
@@ -185,6 +246,17 @@ func ConfigureAuth(p Provisioner) error {
 		return err
 	}
 
+	if dkrcfg.DaemonConfig != nil {
+		configPath := dkrcfg.DaemonConfigPath
+		if configPath == "" {
+			configPath = DefaultDaemonConfigPath
+		}
+
+		if err := writeDaemonConfig(p, configPath, dkrcfg.DaemonConfig); err != nil {
+			return err
+		}
+	}
+
 	if err := p.Service("docker", serviceaction.Start); err != nil {
 		return err
 	}
@@ -192,6 +264,30 @@ func ConfigureAuth(p Provisioner) error {
 	return WaitForDocker(p, dockerPort)
 }
 
+// driverDockerPort returns the port the engine listens on according to the
+// driver's URL.
+func driverDockerPort(driver drivers.Driver) (int, error) {
+	dockerURL, err := driver.GetURL()
+	if err != nil {
+		return 0, err
+	}
+	u, err := url.Parse(dockerURL)
+	if err != nil {
+		return 0, err
+	}
+	dockerPort := engine.DefaultPort
+	parts := strings.Split(u.Host, ":")
+	if len(parts) == 2 {
+		dPort, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, err
+		}
+		dockerPort = dPort
+	}
+
+	return dockerPort, nil
+}
+
 func matchNetstatOut(reDaemonListening, netstatOut string) bool {
 	// TODO: I would really prefer this be a Scanner directly on
 	// the STDOUT of the executed command than to do all the string
@@ -282,3 +282,20 @@ func TestDockerClientVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestInstallScriptEnv(t *testing.T) {
+	assert.Equal(t, "", installScriptEnv(engine.Options{}))
+	assert.Equal(t, "VERSION='18.09.1' ", installScriptEnv(engine.Options{InstallVersion: "18.09.1"}))
+	assert.Equal(t, "CHANNEL='test' VERSION='18.09.1' ", installScriptEnv(engine.Options{InstallVersion: "18.09.1", InstallChannel: "test"}))
+	assert.Equal(t, `VERSION='1; reboot'\''' `, installScriptEnv(engine.Options{InstallVersion: "1; reboot'"}))
+}
+
+func TestInstallScriptCommand(t *testing.T) {
+	assert.Equal(t, "if ! type docker; then curl -sSL https://get.docker.com | sh -; fi", installScriptCommand(engine.Options{InstallURL: "https://get.docker.com"}))
+	assert.Equal(t, `case "$(docker --version 2>/dev/null)" in "Docker version "'18.09.1'[.,+-]*) ;; *) curl -sSL https://get.docker.com | VERSION='18.09.1' sh - ;; esac`, installScriptCommand(engine.Options{InstallURL: "https://get.docker.com", InstallVersion: "18.09.1"}))
+}
+
+func TestAptDockerPackage(t *testing.T) {
+	assert.Equal(t, "docker-ce", aptDockerPackage("docker-ce", ""))
+	assert.Equal(t, `--allow-downgrades docker-ce=$(apt-cache madison docker-ce | awk '$3 ~ /(^|:)18\.09\.1[~-]/ { print $3; exit }')`, aptDockerPackage("docker-ce", "18.09.1"))
+}
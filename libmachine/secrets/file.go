@@ -0,0 +1,106 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const fileProviderName = "file"
+
+// FileProvider keeps the secrets in clear in a file readable by the user
+// only, out of the configuration of the machines. It's meant for tests.
+type FileProvider struct {
+	Path string
+	lock sync.Mutex
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{
+		Path: path,
+	}
+}
+
+func (p *FileProvider) Name() string {
+	return fileProviderName
+}
+
+// Seal stores the secret in the file, under its key.
+func (p *FileProvider) Seal(key, secret string) (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	secrets, err := p.load()
+	if err != nil {
+		return "", err
+	}
+
+	secrets[key] = secret
+
+	return key, p.save(secrets)
+}
+
+func (p *FileProvider) Open(key, sealed string) (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	secrets, err := p.load()
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := secrets[sealed]
+	if !ok {
+		return "", fmt.Errorf("No secret %q in %s", sealed, p.Path)
+	}
+
+	return secret, nil
+}
+
+func (p *FileProvider) Remove(key, sealed string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	secrets, err := p.load()
+	if err != nil {
+		return err
+	}
+
+	delete(secrets, sealed)
+
+	return p.save(secrets)
+}
+
+func (p *FileProvider) load() (map[string]string, error) {
+	secrets := map[string]string{}
+
+	data, err := ioutil.ReadFile(p.Path)
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+func (p *FileProvider) save(secrets map[string]string) error {
+	data, err := json.MarshalIndent(secrets, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.Path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(p.Path, data, 0600)
+}
@@ -0,0 +1,34 @@
+package secrets
+
+const (
+	keyringProviderName = "keyring"
+
+	// keyringService is the service the secrets are stored for in the
+	// keyring of the OS.
+	keyringService = "docker-machine"
+)
+
+// KeyringProvider keeps the secrets in the keyring of the OS, the keychain on
+// macOS and the Secret Service on Linux.
+type KeyringProvider struct{}
+
+func NewKeyringProvider() *KeyringProvider {
+	return &KeyringProvider{}
+}
+
+func (p *KeyringProvider) Name() string {
+	return keyringProviderName
+}
+
+// Seal stores the secret in the keyring, under its key.
+func (p *KeyringProvider) Seal(key, secret string) (string, error) {
+	return key, keyringStore(key, secret)
+}
+
+func (p *KeyringProvider) Open(key, sealed string) (string, error) {
+	return keyringLookup(sealed)
+}
+
+func (p *KeyringProvider) Remove(key, sealed string) error {
+	return keyringRemove(sealed)
+}
@@ -0,0 +1,38 @@
+package secrets
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func keyringStore(account, secret string) error {
+	// security reads the command from stdin in interactive mode, so that the
+	// secret doesn't show in the arguments of the process
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(addGenericPasswordCommand(account, secret))
+
+	return cmd.Run()
+}
+
+// addGenericPasswordCommand returns the security command storing the
+// secret, given in hexadecimal to be safe from the quoting rules of the
+// interactive mode.
+func addGenericPasswordCommand(account, secret string) string {
+	return fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", strconv.Quote(keyringService), strconv.Quote(account), hex.EncodeToString([]byte(secret)))
+}
+
+func keyringLookup(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keyringService, "-a", account, "-w").Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func keyringRemove(account string) error {
+	return exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", account).Run()
+}
@@ -0,0 +1,14 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddGenericPasswordCommand(t *testing.T) {
+	command := addGenericPasswordCommand("default/amazonec2-secret-key", `s3cr"et`)
+
+	assert.Equal(t, `add-generic-password -U -s "`+keyringService+`" -a "default/amazonec2-secret-key" -X 73336372226574`+"\n", command)
+	assert.NotContains(t, command, "s3cr")
+}
@@ -0,0 +1,25 @@
+package secrets
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func keyringStore(account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label=Docker Machine "+account, "service", keyringService, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	return cmd.Run()
+}
+
+func keyringLookup(account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", keyringService, "account", account).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func keyringRemove(account string) error {
+	return exec.Command("secret-tool", "clear", "service", keyringService, "account", account).Run()
+}
@@ -0,0 +1,19 @@
+// +build !darwin,!linux
+
+package secrets
+
+import "errors"
+
+var errKeyringUnsupported = errors.New("The keyring secrets provider isn't supported on this OS")
+
+func keyringStore(account, secret string) error {
+	return errKeyringUnsupported
+}
+
+func keyringLookup(account string) (string, error) {
+	return "", errKeyringUnsupported
+}
+
+func keyringRemove(account string) error {
+	return errKeyringUnsupported
+}
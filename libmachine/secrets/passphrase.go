@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	passphraseProviderName = "passphrase"
+
+	saltSize         = 16
+	keySize          = 32
+	pbkdf2Iterations = 100000
+)
+
+var errMalformedSecret = errors.New("The sealed secret is malformed")
+
+// PassphraseProvider encrypts the secrets with AES-GCM, with a key derived
+// from a passphrase and a random salt stored with each secret.
+type PassphraseProvider struct {
+	passphrase []byte
+}
+
+func NewPassphraseProvider(passphrase string) *PassphraseProvider {
+	return &PassphraseProvider{
+		passphrase: []byte(passphrase),
+	}
+}
+
+func (p *PassphraseProvider) Name() string {
+	return passphraseProviderName
+}
+
+// Seal encrypts the secret, the key authenticates it so that it can't be
+// moved to another field.
+func (p *PassphraseProvider) Seal(key, secret string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	aead, err := p.aead(salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := append(salt, nonce...)
+	sealed = aead.Seal(sealed, nonce, []byte(secret), []byte(key))
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (p *PassphraseProvider) Open(key, sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", errMalformedSecret
+	}
+
+	if len(data) < saltSize {
+		return "", errMalformedSecret
+	}
+
+	aead, err := p.aead(data[:saltSize])
+	if err != nil {
+		return "", err
+	}
+
+	data = data[saltSize:]
+	if len(data) < aead.NonceSize() {
+		return "", errMalformedSecret
+	}
+
+	secret, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(key))
+	if err != nil {
+		return "", errors.New("Unable to decrypt the secret, check the passphrase")
+	}
+
+	return string(secret), nil
+}
+
+// Remove has nothing to forget, the secrets are only kept where they were
+// sealed.
+func (p *PassphraseProvider) Remove(key, sealed string) error {
+	return nil
+}
+
+func (p *PassphraseProvider) aead(salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key(p.passphrase, salt, pbkdf2Iterations, keySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
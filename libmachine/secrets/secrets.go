@@ -0,0 +1,387 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// sealedPrefix starts the values of the sealed secrets, followed by the
+	// name of the provider which sealed them, a colon and what the provider
+	// returned.
+	sealedPrefix = "secret:"
+
+	// Masked replaces the secrets in what's shown to the user.
+	Masked = "********"
+
+	// PassphraseEnvVar holds the passphrase of the passphrase provider.
+	PassphraseEnvVar = "MACHINE_SECRETS_PASSPHRASE"
+)
+
+var (
+	ErrNoProvider      = errors.New("The configuration of the machine holds sealed secrets, a secrets provider is needed to open them")
+	ErrNoPassphrase    = fmt.Errorf("The passphrase secrets provider needs a passphrase, set %s", PassphraseEnvVar)
+	ErrUnknownProvider = errors.New("Unknown secrets provider, use passphrase, keyring or file")
+)
+
+// ErrProviderMismatch is returned when a secret was sealed by another provider
+// than the one opening it.
+type ErrProviderMismatch struct {
+	Sealer string
+	Opener string
+}
+
+func (e ErrProviderMismatch) Error() string {
+	return fmt.Sprintf("The secret was sealed by the %s secrets provider, not by the %s one", e.Sealer, e.Opener)
+}
+
+// Provider seals the secrets of the machines so that they aren't stored in
+// clear in their configuration.
+type Provider interface {
+	// Name is stored with the sealed secrets, they are only opened by the
+	// provider of the same name.
+	Name() string
+
+	// Seal returns what's stored in place of the secret known by key.
+	Seal(key, secret string) (string, error)
+
+	// Open returns the secret known by key from what Seal returned.
+	Open(key, sealed string) (string, error)
+
+	// Remove forgets the secret known by key, when the provider keeps it.
+	Remove(key, sealed string) error
+}
+
+// NewProvider returns the provider of the given name, keeping its files in
+// storePath. Without a name, the passphrase provider is returned when a
+// passphrase is set, and no provider at all otherwise: secrets are then
+// stored in clear.
+func NewProvider(name, storePath string) (Provider, error) {
+	passphrase := os.Getenv(PassphraseEnvVar)
+
+	switch name {
+	case "":
+		if passphrase == "" {
+			return nil, nil
+		}
+		return NewPassphraseProvider(passphrase), nil
+	case passphraseProviderName:
+		if passphrase == "" {
+			return nil, ErrNoPassphrase
+		}
+		return NewPassphraseProvider(passphrase), nil
+	case keyringProviderName:
+		return NewKeyringProvider(), nil
+	case fileProviderName:
+		return NewFileProvider(filepath.Join(storePath, "secrets.json")), nil
+	}
+
+	return nil, ErrUnknownProvider
+}
+
+// IsSealed tells whether a value is a sealed secret.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// key is what a secret is known by to the providers.
+func key(machineName, field string) string {
+	return machineName + "/" + field
+}
+
+// splitSealed returns the name of the provider which sealed a value, and what
+// it returned.
+func splitSealed(value string) (string, string) {
+	parts := strings.SplitN(strings.TrimPrefix(value, sealedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
+// FindFields returns the fields of a driver configuration holding one of the
+// given secrets. Fields of nested objects are joined by dots.
+func FindFields(rawDriver []byte, secrets []string) ([]string, error) {
+	fields := []string{}
+
+	config, err := decode(rawDriver)
+	if err != nil {
+		return nil, err
+	}
+
+	var find func(prefix string, object map[string]interface{})
+	find = func(prefix string, object map[string]interface{}) {
+		for name, value := range object {
+			switch value := value.(type) {
+			case string:
+				for _, secret := range secrets {
+					if secret != "" && value == secret {
+						fields = append(fields, prefix+name)
+						break
+					}
+				}
+			case map[string]interface{}:
+				find(prefix+name+".", value)
+			}
+		}
+	}
+	find("", config)
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+// FindFlagFields returns the fields of a driver configuration set by the given
+// secret flags of the driver, for the machines created before their secret
+// fields were recorded. A field is matched by its name, which is the one of
+// the flag without the driver prefix and dashes: amazonec2-secret-key sets
+// SecretKey.
+func FindFlagFields(rawDriver []byte, driverName string, flagNames []string) ([]string, error) {
+	fields := []string{}
+
+	config, err := decode(rawDriver)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, flagName := range flagNames {
+		name := strings.TrimPrefix(flagName, driverName+"-")
+		names[strings.ToLower(strings.Replace(name, "-", "", -1))] = true
+	}
+
+	var find func(prefix string, object map[string]interface{})
+	find = func(prefix string, object map[string]interface{}) {
+		for name, value := range object {
+			switch value := value.(type) {
+			case string:
+				if names[strings.ToLower(name)] {
+					fields = append(fields, prefix+name)
+				}
+			case map[string]interface{}:
+				find(prefix+name+".", value)
+			}
+		}
+	}
+	find("", config)
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+// Seal seals the secret fields of a driver configuration with the provider.
+// Fields already sealed are left alone.
+func Seal(p Provider, machineName string, rawDriver []byte, fields []string) ([]byte, error) {
+	if p == nil {
+		return rawDriver, nil
+	}
+
+	return rewrite(rawDriver, fields, func(field, value string) (string, error) {
+		if value == "" || IsSealed(value) {
+			return value, nil
+		}
+
+		sealed, err := p.Seal(key(machineName, field), value)
+		if err != nil {
+			return "", err
+		}
+
+		return sealedPrefix + p.Name() + ":" + sealed, nil
+	})
+}
+
+// Open opens the sealed secret fields of a driver configuration with the
+// provider.
+func Open(p Provider, machineName string, rawDriver []byte, fields []string) ([]byte, error) {
+	return rewrite(rawDriver, fields, func(field, value string) (string, error) {
+		if !IsSealed(value) {
+			return value, nil
+		}
+
+		if p == nil {
+			return "", ErrNoProvider
+		}
+
+		name, sealed := splitSealed(value)
+		if name != p.Name() {
+			return "", ErrProviderMismatch{
+				Sealer: name,
+				Opener: p.Name(),
+			}
+		}
+
+		return p.Open(key(machineName, field), sealed)
+	})
+}
+
+// Remove lets the provider forget the sealed secret fields of a driver
+// configuration.
+func Remove(p Provider, machineName string, rawDriver []byte, fields []string) error {
+	_, err := rewrite(rawDriver, fields, func(field, value string) (string, error) {
+		if p == nil || !IsSealed(value) {
+			return value, nil
+		}
+
+		name, sealed := splitSealed(value)
+		if name != p.Name() {
+			return value, nil
+		}
+
+		return value, p.Remove(key(machineName, field), sealed)
+	})
+
+	return err
+}
+
+// Mask masks the secret fields of a driver configuration, sealed or not.
+func Mask(rawDriver []byte, fields []string) ([]byte, error) {
+	return rewrite(rawDriver, fields, func(field, value string) (string, error) {
+		if value == "" {
+			return value, nil
+		}
+
+		return Masked, nil
+	})
+}
+
+// rewrite replaces the string values of the given fields of a driver
+// configuration by what fn returns for them. The order of the fields and the
+// other values are kept as they are.
+func rewrite(rawDriver []byte, fields []string, fn func(field, value string) (string, error)) ([]byte, error) {
+	if len(fields) == 0 {
+		return rawDriver, nil
+	}
+
+	config := json.RawMessage(rawDriver)
+	for _, field := range fields {
+		var err error
+		config, err = rewriteField(config, strings.Split(field, "."), func(value string) (string, error) {
+			return fn(field, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, config); err != nil {
+		return nil, err
+	}
+
+	return compacted.Bytes(), nil
+}
+
+// member is a field of a JSON object.
+type member struct {
+	name  string
+	value json.RawMessage
+}
+
+// rewriteField replaces the string value found at path in the JSON object by
+// what fn returns for it. The object is left alone when there's no such value.
+func rewriteField(object json.RawMessage, path []string, fn func(value string) (string, error)) (json.RawMessage, error) {
+	members, err := decodeMembers(object)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range members {
+		if m.name != path[0] {
+			continue
+		}
+
+		if len(path) > 1 {
+			if !bytes.HasPrefix(bytes.TrimSpace(m.value), []byte("{")) {
+				return object, nil
+			}
+
+			if members[i].value, err = rewriteField(m.value, path[1:], fn); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var value string
+		if json.Unmarshal(m.value, &value) != nil {
+			return object, nil
+		}
+
+		rewritten, err := fn(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if members[i].value, err = json.Marshal(rewritten); err != nil {
+			return nil, err
+		}
+	}
+
+	return encodeMembers(members), nil
+}
+
+// decodeMembers decodes the fields of a JSON object in their order.
+func decodeMembers(object json.RawMessage) ([]member, error) {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, errors.New("The driver configuration isn't a JSON object")
+	}
+
+	members := []member{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		m := member{name: token.(string)}
+		if err := decoder.Decode(&m.value); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+func encodeMembers(members []member) json.RawMessage {
+	buf := &bytes.Buffer{}
+
+	buf.WriteString("{")
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, _ := json.Marshal(m.name)
+		buf.Write(name)
+		buf.WriteString(":")
+		buf.Write(m.value)
+	}
+	buf.WriteString("}")
+
+	return buf.Bytes()
+}
+
+// decode decodes a driver configuration, keeping its numbers as they are.
+func decode(rawDriver []byte) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawDriver))
+	decoder.UseNumber()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,165 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rawDriver = `{"MachineName":"default","DropletID":12345678901,"AccessToken":"token","Client":{"User":"user","ApiKey":"key"}}`
+
+func TestFindFields(t *testing.T) {
+	fields, err := FindFields([]byte(rawDriver), []string{"token", "key", ""})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AccessToken", "Client.ApiKey"}, fields)
+}
+
+func TestFindFieldsNoSecret(t *testing.T) {
+	fields, err := FindFields([]byte(rawDriver), []string{""})
+
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestFindFlagFields(t *testing.T) {
+	fields, err := FindFlagFields([]byte(rawDriver), "digitalocean", []string{"digitalocean-access-token", "digitalocean-api-key"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AccessToken", "Client.ApiKey"}, fields)
+}
+
+func TestFindFlagFieldsNoMatch(t *testing.T) {
+	fields, err := FindFlagFields([]byte(rawDriver), "digitalocean", []string{"digitalocean-password"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestSealOpenPassphrase(t *testing.T) {
+	p := NewPassphraseProvider("passphrase")
+	fields := []string{"AccessToken", "Client.ApiKey"}
+
+	sealed, err := Seal(p, "default", []byte(rawDriver), fields)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(sealed), "token")
+	assert.NotContains(t, string(sealed), `"key"`)
+	assert.Contains(t, string(sealed), `"AccessToken":"secret:passphrase:`)
+	assert.Contains(t, string(sealed), `"DropletID":12345678901`)
+
+	resealed, err := Seal(p, "default", sealed, fields)
+
+	assert.NoError(t, err)
+	assert.Equal(t, sealed, resealed)
+
+	opened, err := Open(p, "default", sealed, fields)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rawDriver, string(opened))
+}
+
+func TestOpenWrongPassphrase(t *testing.T) {
+	sealed, err := Seal(NewPassphraseProvider("passphrase"), "default", []byte(rawDriver), []string{"AccessToken"})
+	assert.NoError(t, err)
+
+	_, err = Open(NewPassphraseProvider("wrong"), "default", sealed, []string{"AccessToken"})
+
+	assert.Error(t, err)
+}
+
+func TestOpenOtherMachine(t *testing.T) {
+	p := NewPassphraseProvider("passphrase")
+	sealed, err := Seal(p, "default", []byte(rawDriver), []string{"AccessToken"})
+	assert.NoError(t, err)
+
+	_, err = Open(p, "other", sealed, []string{"AccessToken"})
+
+	assert.Error(t, err)
+}
+
+func TestOpenWithoutProvider(t *testing.T) {
+	sealed, err := Seal(NewPassphraseProvider("passphrase"), "default", []byte(rawDriver), []string{"AccessToken"})
+	assert.NoError(t, err)
+
+	_, err = Open(nil, "default", sealed, []string{"AccessToken"})
+
+	assert.Equal(t, ErrNoProvider, err)
+}
+
+func TestOpenProviderMismatch(t *testing.T) {
+	sealed, err := Seal(NewPassphraseProvider("passphrase"), "default", []byte(rawDriver), []string{"AccessToken"})
+	assert.NoError(t, err)
+
+	_, err = Open(NewFileProvider("secrets.json"), "default", sealed, []string{"AccessToken"})
+
+	assert.Equal(t, ErrProviderMismatch{Sealer: "passphrase", Opener: "file"}, err)
+}
+
+func TestOpenInClear(t *testing.T) {
+	opened, err := Open(nil, "default", []byte(rawDriver), []string{"AccessToken"})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, rawDriver, string(opened))
+}
+
+func TestSealOpenRemoveFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "machine-secrets-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := NewFileProvider(filepath.Join(dir, "secrets.json"))
+	fields := []string{"AccessToken"}
+
+	sealed, err := Seal(p, "default", []byte(rawDriver), fields)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(sealed), `"AccessToken":"secret:file:default/AccessToken"`)
+
+	opened, err := Open(p, "default", sealed, fields)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, rawDriver, string(opened))
+
+	assert.NoError(t, Remove(p, "default", sealed, fields))
+
+	_, err = Open(p, "default", sealed, fields)
+
+	assert.Error(t, err)
+}
+
+func TestMask(t *testing.T) {
+	masked, err := Mask([]byte(rawDriver), []string{"AccessToken", "Client.ApiKey", "Missing.Field"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"MachineName":"default","DropletID":12345678901,"AccessToken":"********","Client":{"User":"user","ApiKey":"********"}}`, string(masked))
+}
+
+func TestNewProvider(t *testing.T) {
+	defer os.Setenv(PassphraseEnvVar, os.Getenv(PassphraseEnvVar))
+
+	os.Setenv(PassphraseEnvVar, "")
+
+	p, err := NewProvider("", "/store")
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = NewProvider("passphrase", "/store")
+	assert.Equal(t, ErrNoPassphrase, err)
+
+	p, err = NewProvider("file", "/store")
+	assert.NoError(t, err)
+	assert.Equal(t, NewFileProvider(filepath.Join("/store", "secrets.json")), p)
+
+	_, err = NewProvider("unknown", "/store")
+	assert.Equal(t, ErrUnknownProvider, err)
+
+	os.Setenv(PassphraseEnvVar, "passphrase")
+
+	p, err = NewProvider("", "/store")
+	assert.NoError(t, err)
+	assert.Equal(t, NewPassphraseProvider("passphrase"), p)
+}
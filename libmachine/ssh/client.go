@@ -35,6 +35,16 @@ type Client interface {
 	Wait() error
 }
 
+// StdinClient is implemented by the clients able to feed a command with data,
+// which is then read from its standard input.
+type StdinClient interface {
+	OutputWithStdin(command string, stdin io.Reader) (string, error)
+
+	// RunWithStdin runs the command with the content of stdin as its standard
+	// input, copying its output to stdout and stderr while it runs.
+	RunWithStdin(command string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
 type ExternalClient struct {
 	BaseArgs   []string
 	BinaryPath string
@@ -227,6 +237,39 @@ func (client *NativeClient) OutputWithPty(command string) (string, error) {
 	return string(output), err
 }
 
+// OutputWithStdin runs the command with the content of stdin as its standard
+// input, over a single session.
+func (client *NativeClient) OutputWithStdin(command string, stdin io.Reader) (string, error) {
+	conn, session, err := client.session(command)
+	if err != nil {
+		return "", err
+	}
+	defer closeConn(conn)
+	defer session.Close()
+
+	session.Stdin = stdin
+	output, err := session.CombinedOutput(command)
+
+	return string(output), err
+}
+
+// RunWithStdin runs the command with the content of stdin as its standard
+// input, over a single session, and streams its output to stdout and stderr.
+func (client *NativeClient) RunWithStdin(command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	conn, session, err := client.session(command)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn)
+	defer session.Close()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	return session.Run(command)
+}
+
 func (client *NativeClient) Start(command string) (io.ReadCloser, io.ReadCloser, error) {
 	conn, session, err := client.session(command)
 	if err != nil {
@@ -389,6 +432,27 @@ func (client *ExternalClient) Output(command string) (string, error) {
 	return string(output), err
 }
 
+// OutputWithStdin runs the command with the content of stdin as its standard
+// input.
+func (client *ExternalClient) OutputWithStdin(command string, stdin io.Reader) (string, error) {
+	args := append(client.BaseArgs, command)
+	cmd := getSSHCmd(client.BinaryPath, args...)
+	cmd.Stdin = stdin
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// RunWithStdin runs the command with the content of stdin as its standard
+// input and streams its output to stdout and stderr.
+func (client *ExternalClient) RunWithStdin(command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	args := append(client.BaseArgs, command)
+	cmd := getSSHCmd(client.BinaryPath, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
 func (client *ExternalClient) Shell(args ...string) error {
 	args = append(client.BaseArgs, args...)
 	cmd := getSSHCmd(client.BinaryPath, args...)
@@ -2,6 +2,15 @@ package swarm
 
 const (
 	DiscoveryServiceEndpoint = "https://discovery-stage.hub.docker.com/v1"
+
+	// ModeManager and ModeWorker are the roles of a node in a swarm mode
+	// cluster.
+	ModeManager = "manager"
+	ModeWorker  = "worker"
+
+	// ModePort is the port swarm mode managers listen on for cluster
+	// management.
+	ModePort = 2377
 )
 
 type Options struct {
@@ -19,4 +28,12 @@ type Options struct {
 	ArbitraryJoinFlags []string
 	Env                []string
 	IsExperimental     bool
+
+	// Swarm mode (swarmkit) settings, independent from the legacy swarm
+	// containers configured above.
+	Mode         string `json:",omitempty"`
+	ModeCluster  string `json:",omitempty"`
+	ModeJoinAddr string `json:",omitempty"`
+	// ModeJoinToken is only needed to join the cluster and is not persisted.
+	ModeJoinToken string `json:"-"`
 }